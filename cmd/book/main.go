@@ -0,0 +1,43 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"quarto/models/ai"
+	"quarto/models/game"
+)
+
+func main() {
+	plies := flag.Int("plies", 2, "Number of placements covered by the book, from the empty board")
+	depth := flag.Int("depth", 8, "Search depth used to evaluate each move")
+	timeLimit := flag.Duration("time", 10*time.Second, "Time limit per search (0 = no limit)")
+	margin := flag.Int("margin", 50, "Maximum score gap with the best move for a move to be kept")
+	variant := flag.String("variant", game.VariantStandard, "Rules variant")
+	output := flag.String("out", "book.qob", "Output file")
+	flag.Parse()
+
+	if !game.IsValidVariant(*variant) {
+		fmt.Fprintf(os.Stderr, "Unknown variant %q\n", *variant)
+		os.Exit(1)
+	}
+
+	start := time.Now()
+	book := ai.BuildBook(ai.BookOptions{
+		Variant:   *variant,
+		Plies:     *plies,
+		Depth:     *depth,
+		TimeLimit: *timeLimit,
+		Margin:    *margin,
+	}, func(positions int) {
+		fmt.Printf("%d positions in the book after %v\n", positions, time.Since(start).Round(time.Millisecond))
+	})
+
+	if err := book.Save(*output); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Printf("%d positions written to %s in %v\n", book.Len(), *output, time.Since(start).Round(time.Millisecond))
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"slices"
+	"time"
+
+	"quarto/models/ai"
+	"quarto/models/game"
+)
+
+// newPlayer crée un moteur du match
+func newPlayer(kind string, depth int, timeLimit time.Duration, nodeLimit int, exploration float64, seed int64) (ai.Searcher, error) {
+	searcher, err := ai.NewSearcher(kind, depth, timeLimit, nodeLimit)
+	if err != nil {
+		return nil, err
+	}
+	if mcts, ok := searcher.(*ai.MCTS); ok {
+		mcts.Exploration = exploration
+		mcts.Rand = rand.New(rand.NewSource(seed))
+	}
+	return searcher, nil
+}
+
+// applyMove joue un coup, y compris le choix de la première pièce qui ne place rien
+func applyMove(state ai.GameState, move ai.AIMove) ai.GameState {
+	if state.SelectedPiece != game.PieceEmpty {
+		return state.ApplyMove(move)
+	}
+	state.SelectedPiece = move.SelectedPiece
+	state.AvailablePieces = slices.DeleteFunc(slices.Clone(state.AvailablePieces), func(piece game.Piece) bool { return piece == move.SelectedPiece })
+	state.Hash = state.ComputeHash()
+	return state
+}
+
+// randomOpening joue plies coups au hasard depuis le plateau vide, le choix de la première pièce compris
+func randomOpening(rng *rand.Rand, variant string, plies int) ai.GameState {
+	state := ai.GameState{Board: game.GetEmptyBoard(), SelectedPiece: game.PieceEmpty, AvailablePieces: game.GetAllPieces(), Variant: variant}
+	state.Hash = state.ComputeHash()
+	for i := 0; i < plies && !state.IsGameOver; i++ {
+		if state.SelectedPiece == game.PieceEmpty {
+			state = applyMove(state, ai.AIMove{SelectedPiece: state.AvailablePieces[rng.Intn(len(state.AvailablePieces))]})
+			continue
+		}
+		moves := ai.GetValidMoves(state)
+		if len(moves) == 0 {
+			break
+		}
+		state = applyMove(state, moves[rng.Intn(len(moves))])
+	}
+	return state
+}
+
+// playGame joue une partie depuis la position donnée, players[0] étant le joueur 1, et retourne le gagnant : 1, -1
+// ou 0 pour un match nul
+func playGame(players [2]ai.Searcher, state ai.GameState) int {
+	for !state.IsGameOver {
+		// Le joueur 1 joue quand il reste un nombre pair de pièces à donner
+		player := players[0]
+		if len(state.AvailablePieces)%2 != 0 {
+			player = players[1]
+		}
+		result := player.Search(state)
+		if len(result.BestMoves) == 0 {
+			return 0
+		}
+		state = applyMove(state, result.BestMoves[0])
+	}
+	return state.Winner
+}
+
+func main() {
+	engineA := flag.String("a", ai.SearcherMinimax, "First engine (minimax or mcts)")
+	engineB := flag.String("b", ai.SearcherMCTS, "Second engine (minimax or mcts)")
+	depth := flag.Int("depth", ai.DefaultMaxDepth, "Search depth of minimax")
+	timeLimit := flag.Duration("time", 100*time.Millisecond, "Time limit per move (0 = no limit)")
+	nodeLimit := flag.Int("nodes", 0, "Node limit per move of minimax, iterations of mcts (0 = no limit)")
+	exploration := flag.Float64("exploration", ai.MCTSExploration, "UCT exploration constant of mcts")
+	pairs := flag.Int("games", 10, "Number of openings, each played twice with colours swapped")
+	random := flag.Int("random", 3, "Number of random plies of each opening")
+	variant := flag.String("variant", game.VariantStandard, "Rules variant")
+	seed := flag.Int64("seed", time.Now().UnixNano(), "Seed of the openings and of mcts")
+	flag.Parse()
+
+	if !game.IsValidVariant(*variant) {
+		fmt.Fprintf(os.Stderr, "Unknown variant %q\n", *variant)
+		os.Exit(1)
+	}
+	a, err := newPlayer(*engineA, *depth, *timeLimit, *nodeLimit, *exploration, *seed)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	b, err := newPlayer(*engineB, *depth, *timeLimit, *nodeLimit, *exploration, *seed+1)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	// Chaque ouverture est jouée deux fois, chaque moteur commençant une fois
+	rng := rand.New(rand.NewSource(*seed))
+	wins, draws, losses := 0, 0, 0
+	start := time.Now()
+	for i := 0; i < *pairs; i++ {
+		opening := randomOpening(rng, *variant, *random)
+		for swap, players := range [][2]ai.Searcher{{a, b}, {b, a}} {
+			result, side := playGame(players, opening), "player 1"
+			if swap == 1 {
+				result, side = -result, "player 2"
+			}
+			switch result {
+			case 1:
+				wins++
+			case -1:
+				losses++
+			default:
+				draws++
+			}
+			fmt.Printf("Game %d: %s as %s, result for %s: %+d\n", 2*i+swap+1, *engineA, side, *engineA, result)
+		}
+	}
+
+	games := wins + draws + losses
+	score := (float64(wins) + float64(draws)/2) / float64(games)
+	fmt.Printf("\n=== %s vs %s, %d games in %v ===\n", *engineA, *engineB, games, time.Since(start).Round(time.Millisecond))
+	fmt.Printf("%s: %d wins, %d draws, %d losses (score %.1f%%)\n", *engineA, wins, draws, losses, 100*score)
+	if score > 0 && score < 1 {
+		fmt.Printf("Elo difference: %+.0f\n", -400*math.Log10(1/score-1))
+	}
+}
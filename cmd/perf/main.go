@@ -1,10 +1,12 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"math/rand"
 	"runtime"
+	"slices"
 	"sort"
 	"time"
 
@@ -13,57 +15,46 @@ import (
 	"quarto/models/game"
 )
 
-func generateRandomQuartoGame(numMoves int) ai.GameState {
-	// Créer une nouvelle partie Quarto en mémoire uniquement
+func generateRandomQuartoGame(rng *rand.Rand, numMoves int) ai.GameState {
+	// Créer une nouvelle partie Quarto en mémoire uniquement, le premier joueur donnant une pièce au hasard
 	g := game.InitializeGame(1, 2)
-	var state ai.GameState
+	first := g.AvailablePieces[rng.Intn(len(g.AvailablePieces))]
+	g.SelectedPiece = first
+	g.AvailablePieces = slices.DeleteFunc(g.AvailablePieces, func(piece game.Piece) bool { return piece == first })
+	state := ai.ConvertGameToState(g)
 
 	// Simuler des mouvements aléatoires
-	for i := 0; i < numMoves && g.Status == game.StatusPlaying; i++ {
-		// Convertir en état pour l'IA
-		state = ai.ConvertGameToState(g)
-
-		// Vérifier si le jeu est terminé
-		if state.IsGameOver {
-			g.Status = game.StatusFinished
-			switch state.Winner {
-			case 1:
-				g.Winner = 1
-			case -1:
-				g.Winner = 2
-			default:
-				g.Winner = 0
-			}
-			break
-		}
-
+	for i := 0; i < numMoves && !state.IsGameOver; i++ {
 		// Obtenir les mouvements valides
 		validMoves := ai.GetValidMoves(state)
 		if len(validMoves) == 0 {
 			break // Plus de mouvements possibles
 		}
 
-		// Choisir un mouvement aléatoire
-		randomMove := validMoves[rand.Intn(len(validMoves))]
-
-		// Appliquer le mouvement directement à l'état
-		state = state.ApplyMove(randomMove)
+		// Choisir un mouvement aléatoire et l'appliquer directement à l'état
+		state = state.ApplyMove(validMoves[rng.Intn(len(validMoves))])
 	}
 
 	return state
 }
 
-func runQuartoBenchmark(depth int, numGames int, numMoves int, showStats bool) {
+// runQuartoBenchmark joue les parties tirées de la graine et retourne le nombre moyen de nœuds par seconde et la durée
+// totale des recherches. Avec perSearch, chaque recherche crée son moteur, comme une requête de l'API sans table
+// conservée ; sinon un moteur sert à toute la partie, sa table étant conservée d'un coup à l'autre avec keepTT.
+func runQuartoBenchmark(depth int, timeLimit time.Duration, ttSizeMB int, keepTT bool, perSearch bool, threads int, deterministic bool, seed int64, numGames int, numMoves int, plies int, showStats bool) (float64, time.Duration) {
 	totalStats := make(map[string]*stats.OperationStats)
 	totalTime := time.Duration(0)
+	totalNodes := 0
 	validGames := 0
 
-	fmt.Printf("Running Quarto benchmark with %d games (%d moves each, depth %d)...\n", numGames, numMoves, depth)
+	fmt.Printf("Running Quarto benchmark with %d games (%d moves each, %d searched plies, depth %d, %d MB table, keep table: %t, engine per search: %t, %d threads, deterministic: %t)...\n",
+		numGames, numMoves, plies, depth, ttSizeMB, keepTT, perSearch, threads, deterministic)
 
-	quartoAI := ai.NewEngine(depth)
+	ai.TTSizeMB = ttSizeMB
+	rng := rand.New(rand.NewSource(seed)) // Mêmes parties quel que soit le nombre de threads
 
 	for i := 0; i < numGames; i++ {
-		g := generateRandomQuartoGame(numMoves)
+		g := generateRandomQuartoGame(rng, numMoves)
 
 		// Vérifier que le jeu n'est pas terminé
 		if g.IsGameOver {
@@ -71,6 +62,19 @@ func runQuartoBenchmark(depth int, numGames int, numMoves int, showStats bool) {
 			continue
 		}
 
+		// Un moteur par partie : la table peut être conservée d'un coup à l'autre
+		newEngine := func() *ai.Engine {
+			engine := ai.NewEngine(depth)
+			if keepTT {
+				engine = ai.NewGameEngine(fmt.Sprintf("perf-%d-%d", seed, i), depth)
+			}
+			engine.TimeLimit = timeLimit
+			engine.Threads = threads
+			engine.Deterministic = deterministic
+			return engine
+		}
+		quartoAI := newEngine()
+
 		var gameStats *stats.PerformanceStats
 		if showStats {
 			gameStats = stats.NewPerformanceStats()
@@ -80,13 +84,24 @@ func runQuartoBenchmark(depth int, numGames int, numMoves int, showStats bool) {
 		var memBefore runtime.MemStats
 		runtime.ReadMemStats(&memBefore)
 
+		// Jouer les coups successifs de la partie avec le même moteur
 		start := time.Now()
-		if showStats {
-			quartoAI.SearchWithStats(g, gameStats)
-		} else {
-			quartoAI.Search(g)
+		nodes, reached := 0, 0
+		for ply := 0; ply < plies && !g.IsGameOver && g.SelectedPiece != game.PieceEmpty; ply++ {
+			if perSearch && ply > 0 {
+				quartoAI = newEngine()
+			}
+			result := quartoAI.SearchWithStats(context.Background(), g, gameStats)
+			nodes += result.Nodes
+			reached = result.Depth
+			if len(result.BestMoves) == 0 {
+				break
+			}
+			g = g.ApplyMove(result.BestMoves[0])
 		}
 		elapsed := time.Since(start)
+		totalNodes += nodes
+		hits, misses := quartoAI.TT.GetStats()
 
 		validGames++
 
@@ -97,11 +112,11 @@ func runQuartoBenchmark(depth int, numGames int, numMoves int, showStats bool) {
 		totalTime += elapsed
 
 		// Calcul usage mémoire
-		allocDiff := memAfter.Alloc - memBefore.Alloc
+		allocDiff := int64(memAfter.Alloc) - int64(memBefore.Alloc)
 		totalAllocDiff := memAfter.TotalAlloc - memBefore.TotalAlloc
 
-		fmt.Printf("Game %d: Memory: %d KB allocated, %d KB total\n",
-			i+1, allocDiff/1024, totalAllocDiff/1024)
+		fmt.Printf("Game %d: Depth %d, %d nodes (%.0f nodes/s), TT hits %d/%d, Memory: %d KB allocated, %d KB total\n",
+			i+1, reached, nodes, float64(nodes)/elapsed.Seconds(), hits, hits+misses, allocDiff/1024, totalAllocDiff/1024)
 
 		// Accumuler les statistiques
 		if showStats && gameStats != nil {
@@ -126,12 +141,14 @@ func runQuartoBenchmark(depth int, numGames int, numMoves int, showStats bool) {
 
 	if validGames == 0 {
 		fmt.Println("No valid games processed!")
-		return
+		return 0, 0
 	}
 
 	fmt.Printf("\n=== AVERAGE RESULTS OVER %d VALID GAMES ===\n", validGames)
 	fmt.Printf("Average time: %v\n", totalTime/time.Duration(validGames))
 	fmt.Printf("Total time: %v\n", totalTime)
+	speed := float64(totalNodes) / totalTime.Seconds()
+	fmt.Printf("Average speed: %.0f nodes/s\n", speed)
 
 	if showStats {
 		fmt.Printf("\n=== PERFORMANCE STATISTICS ===\n")
@@ -175,6 +192,7 @@ func runQuartoBenchmark(depth int, numGames int, numMoves int, showStats bool) {
 			}
 		}
 	}
+	return speed, totalTime
 }
 
 func min(a, b int) int {
@@ -186,13 +204,42 @@ func min(a, b int) int {
 
 func main() {
 	depth := flag.Int("depth", 8, "Search depth for AI")
+	timeLimit := flag.Duration("time", 0, "Time limit per search (0 = no limit)")
+	ttSizeMB := flag.Int("tt", ai.DefaultTTSizeMB, "Transposition table size in MB")
+	keepTT := flag.Bool("keep", false, "Keep the transposition table between the searches of a game")
+	compare := flag.Bool("compare", false, "Compare a new engine per search (API requests without a kept table) with the table kept per game")
+	plies := flag.Int("plies", 1, "Number of successive positions searched per game")
+	threads := flag.Int("threads", 1, "Maximum number of search threads: the benchmark is run for 1 to N threads")
+	deterministic := flag.Bool("deterministic", false, "Use the deterministic parallel search (root splitting)")
+	seed := flag.Int64("seed", time.Now().UnixNano(), "Seed of the random games")
 	showStats := flag.Bool("stats", false, "Show detailed performance stats")
 	numGames := flag.Int("games", 1, "Number of games to test")
 	numMoves := flag.Int("moves", 10, "Number of random moves for game generation")
 	flag.Parse()
 
-	// Initialiser le générateur aléatoire
-	// Note: Depuis Go 1.20, plus besoin d'appeler rand.Seed
+	// Comparer, sur les mêmes parties, des recherches qui allouent chacune leur moteur à celles qui reprennent la
+	// table de la partie
+	if *compare {
+		_, before := runQuartoBenchmark(*depth, *timeLimit, *ttSizeMB, false, true, *threads, *deterministic, *seed, *numGames, *numMoves, *plies, *showStats)
+		fmt.Println()
+		_, after := runQuartoBenchmark(*depth, *timeLimit, *ttSizeMB, true, false, *threads, *deterministic, *seed, *numGames, *numMoves, *plies, *showStats)
+		fmt.Printf("\n=== TABLE KEPT PER GAME ===\n")
+		fmt.Printf("Engine per search: %v\nTable kept per game: %v (x%.2f)\n", before, after, before.Seconds()/after.Seconds())
+		return
+	}
+
+	// Comparer les vitesses de 1 à N threads sur les mêmes parties
+	speeds := make([]float64, 0, *threads)
+	for t := 1; t <= *threads; t++ {
+		speed, _ := runQuartoBenchmark(*depth, *timeLimit, *ttSizeMB, *keepTT, false, t, *deterministic, *seed, *numGames, *numMoves, *plies, *showStats)
+		speeds = append(speeds, speed)
+		fmt.Println()
+	}
 
-	runQuartoBenchmark(*depth, *numGames, *numMoves, *showStats)
+	if len(speeds) > 1 {
+		fmt.Printf("=== THREAD SCALING ===\n")
+		for i, speed := range speeds {
+			fmt.Printf("%2d threads: %12.0f nodes/s (x%.2f)\n", i+1, speed, speed/speeds[0])
+		}
+	}
 }
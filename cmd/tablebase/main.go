@@ -0,0 +1,86 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"slices"
+	"time"
+
+	"quarto/models/ai"
+	"quarto/models/game"
+)
+
+// randomEndgame joue une partie au hasard jusqu'à ce qu'il ne reste que empty cases vides
+func randomEndgame(rng *rand.Rand, variant string, empty int) ai.GameState {
+	g := game.InitializeGame(1, 2)
+	first := g.AvailablePieces[rng.Intn(len(g.AvailablePieces))]
+	g.SelectedPiece = first
+	g.AvailablePieces = slices.DeleteFunc(g.AvailablePieces, func(piece game.Piece) bool { return piece == first })
+	state := ai.ConvertGameToState(g)
+	state.Variant = variant
+	state.Hash = state.ComputeHash()
+
+	for placed := 0; placed < 16-empty && !state.IsGameOver; placed++ {
+		moves := ai.GetValidMoves(state)
+		state = state.ApplyMove(moves[rng.Intn(len(moves))])
+	}
+	return state
+}
+
+func main() {
+	empty := flag.Int("empty", 7, "Maximum number of empty squares of the solved positions")
+	games := flag.Int("games", 1000, "Number of random games whose endgame is solved")
+	variant := flag.String("variant", game.VariantStandard, "Rules variant")
+	seed := flag.Int64("seed", time.Now().UnixNano(), "Seed of the random games")
+	output := flag.String("out", "tablebase.qtb", "Output file")
+	flag.Parse()
+
+	if !game.IsValidVariant(*variant) {
+		fmt.Fprintf(os.Stderr, "Unknown variant %q\n", *variant)
+		os.Exit(1)
+	}
+
+	// Toutes les positions de N cases vides sont bien trop nombreuses : la table couvre les fins des parties tirées
+	// au hasard, avec toutes les positions rencontrées en les résolvant
+	rng := rand.New(rand.NewSource(*seed))
+	solver := ai.NewSolver(*empty)
+	start := time.Now()
+	wins, draws, losses := 0, 0, 0
+	for i := 0; i < *games; i++ {
+		state := randomEndgame(rng, *variant, *empty)
+		if state.IsGameOver {
+			continue
+		}
+		outcome, err := solver.Solve(state)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		switch outcome.Result {
+		case 1:
+			wins++
+		case -1:
+			losses++
+		default:
+			draws++
+		}
+		if (i+1)%100 == 0 {
+			fmt.Printf("%d games, %d positions solved in %v\n", i+1, solver.Len(), time.Since(start).Round(time.Millisecond))
+		}
+	}
+	fmt.Printf("Endgames won %d, drawn %d, lost %d by the player to move\n", wins, draws, losses)
+
+	tablebase := solver.Tablebase()
+	if err := tablebase.Save(*output); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	info, err := os.Stat(*output)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Printf("%d positions written to %s (%d KB) in %v\n", tablebase.Len(), *output, info.Size()/1024, time.Since(start).Round(time.Millisecond))
+}
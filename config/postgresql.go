@@ -66,6 +66,11 @@ func InitPgSQL() (context.Context, *pgx.ConnConfig) {
 		PRIMARY KEY(id)
 	);
 
+	-- Compte du bot qui joue les parties contre le serveur ; son mot de passe aléatoire interdit toute connexion
+	INSERT INTO account (email, username, password)
+	VALUES ('bot@bot.quarto.local', 'QuartoBot', crypt(gen_random_uuid()::text, gen_salt('bf')))
+	ON CONFLICT (email) DO NOTHING;
+
 	-- Table pour les défis entre joueurs
 	CREATE TABLE IF NOT EXISTS challenges (
 		id 							VARCHAR(36) PRIMARY KEY,
@@ -97,15 +102,79 @@ func InitPgSQL() (context.Context, *pgx.ConnConfig) {
 		updated_at 			TIMESTAMP DEFAULT NOW()
 	);
 
+	-- Options des défis et des parties (cadence, partie classée, premier joueur)
+	ALTER TABLE challenges ADD COLUMN IF NOT EXISTS options JSONB DEFAULT '{}';
+	ALTER TABLE challenges ADD COLUMN IF NOT EXISTS proposed_by INTEGER REFERENCES account(id);
+	ALTER TABLE games ADD COLUMN IF NOT EXISTS options JSONB DEFAULT '{}';
+
+	-- Défis ouverts (sans adversaire désigné) et liens d'invitation
+	ALTER TABLE challenges ALTER COLUMN challenged_id DROP NOT NULL;
+	ALTER TABLE challenges ADD COLUMN IF NOT EXISTS visibility TEXT DEFAULT 'direct' CHECK (visibility IN ('direct', 'open', 'invite'));
+	ALTER TABLE challenges ADD COLUMN IF NOT EXISTS allow_guest boolean DEFAULT FALSE;
+
+	-- Revanches : une série regroupe une partie et ses revanches successives
+	ALTER TABLE challenges ADD COLUMN IF NOT EXISTS rematch_of VARCHAR(36);
+	ALTER TABLE games ADD COLUMN IF NOT EXISTS series_id VARCHAR(36);
+	ALTER TABLE games ADD COLUMN IF NOT EXISTS previous_game_id VARCHAR(36);
+
+	-- Propositions de nulle et demandes d'annulation de coup en attente
+	ALTER TABLE games ADD COLUMN IF NOT EXISTS draw_offered_by BIGINT DEFAULT 0;
+	ALTER TABLE games ADD COLUMN IF NOT EXISTS takeback_requested_by BIGINT DEFAULT 0;
+
+	-- Indices consommés par chaque joueur (le budget est fixé dans les options de la partie)
+	ALTER TABLE games ADD COLUMN IF NOT EXISTS player1_hints_used INTEGER DEFAULT 0;
+	ALTER TABLE games ADD COLUMN IF NOT EXISTS player2_hints_used INTEGER DEFAULT 0;
+
+	-- Table des relations d'amitié (demandes en attente et amitiés acceptées)
+	CREATE TABLE IF NOT EXISTS friendships (
+		id 							SERIAL PRIMARY KEY,
+		requester_id 		INTEGER REFERENCES account(id) NOT NULL,
+		addressee_id 		INTEGER REFERENCES account(id) NOT NULL,
+		status 					TEXT DEFAULT 'pending' CHECK (status IN ('pending', 'accepted')),
+		created_at 			TIMESTAMP DEFAULT NOW(),
+		responded_at 		TIMESTAMP,
+		CHECK (requester_id <> addressee_id)
+	);
+
+	-- Table des blocages entre joueurs
+	CREATE TABLE IF NOT EXISTS blocks (
+		blocker_id 			INTEGER REFERENCES account(id) NOT NULL,
+		blocked_id 			INTEGER REFERENCES account(id) NOT NULL,
+		created_at 			TIMESTAMP DEFAULT NOW(),
+		PRIMARY KEY(blocker_id, blocked_id)
+	);
+
+	-- Table des préférences utilisateur
+	CREATE TABLE IF NOT EXISTS user_settings (
+		user_id 									INTEGER REFERENCES account(id) PRIMARY KEY,
+		friends_only_challenges 	boolean DEFAULT FALSE,
+		updated_at 								TIMESTAMP DEFAULT NOW()
+	);
+
 	-- Index pour optimiser les requêtes
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_friendships_pair ON friendships(LEAST(requester_id, addressee_id), GREATEST(requester_id, addressee_id));
+	CREATE INDEX IF NOT EXISTS idx_friendships_addressee ON friendships(addressee_id);
+	CREATE INDEX IF NOT EXISTS idx_blocks_blocked ON blocks(blocked_id);
 	CREATE INDEX IF NOT EXISTS idx_challenges_challenger ON challenges(challenger_id);
 	CREATE INDEX IF NOT EXISTS idx_challenges_challenged ON challenges(challenged_id);
 	CREATE INDEX IF NOT EXISTS idx_challenges_status ON challenges(status);
 	CREATE INDEX IF NOT EXISTS idx_challenges_expires ON challenges(expires_at);
+	CREATE INDEX IF NOT EXISTS idx_challenges_visibility ON challenges(visibility, status);
 	CREATE INDEX IF NOT EXISTS idx_games_status ON games(status);
 	CREATE INDEX IF NOT EXISTS idx_games_player1 ON games(player1_id);
 	CREATE INDEX IF NOT EXISTS idx_games_player2 ON games(player2_id);
 	CREATE INDEX IF NOT EXISTS idx_games_players ON games(player1_id, player2_id);
+	CREATE INDEX IF NOT EXISTS idx_games_series ON games(series_id);
+	CREATE INDEX IF NOT EXISTS idx_games_previous ON games(previous_game_id);
+	CREATE INDEX IF NOT EXISTS idx_challenges_rematch ON challenges(rematch_of);
+
+	-- Une seule proposition de revanche en attente par partie : les doublons antérieurs à l'index sont expirés
+	UPDATE challenges AS c SET status = 'expired', updated_at = NOW()
+	WHERE c.status = 'pending' AND c.rematch_of IS NOT NULL AND EXISTS (
+		SELECT 1 FROM challenges AS d
+		WHERE d.rematch_of = c.rematch_of AND d.status = 'pending' AND (d.created_at, d.id) > (c.created_at, c.id)
+	);
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_challenges_pending_rematch ON challenges(rematch_of) WHERE status = 'pending';
 	`
 
 	_, err = sqlCo.Exec(ctx, query)
@@ -3,20 +3,37 @@ package config
 import (
 	"embed"
 	"html/template"
+	"math"
 	"os"
 	"quarto/email"
+	"strconv"
+	"time"
 
 	"github.com/charmbracelet/log"
+	"github.com/google/uuid"
 	"github.com/provectio/godotenv"
 )
 
 const Version = "0.0.1"
 
 var Config struct {
-	FrontURL      string
-	ListenPort    string
-	BodySizeLimit string
-	Email         email.Config
+	FrontURL                string
+	ListenPort              string
+	BodySizeLimit           string
+	ChallengeExpiryInterval time.Duration
+	ClockCheckInterval      time.Duration
+	InviteSecret            string
+	DrawOffersUnratedOnly   bool
+	TakebacksUnratedOnly    bool
+	AITableSizeMB           int
+	AIThreads               int
+	AIGameTables            int
+	AIEvalWeights           string
+	AITablebase             string
+	AIOpeningBook           string
+	AIMCTSExploration       float64
+	AIMCTSMaxNodes          int
+	Email                   email.Config
 }
 
 func Init(publicFolder embed.FS) {
@@ -51,6 +68,85 @@ func Init(publicFolder embed.FS) {
 	}
 	Config.BodySizeLimit = bodySizeLimit
 
+	expiryInterval, err := time.ParseDuration(os.Getenv("CHALLENGE_EXPIRY_INTERVAL"))
+	if err != nil || expiryInterval <= 0 {
+		log.Warn("CHALLENGE_EXPIRY_INTERVAL not set or invalid, using default value (1m)")
+		expiryInterval = time.Minute
+	}
+	Config.ChallengeExpiryInterval = expiryInterval
+
+	clockInterval, err := time.ParseDuration(os.Getenv("CLOCK_CHECK_INTERVAL"))
+	if err != nil || clockInterval <= 0 {
+		log.Warn("CLOCK_CHECK_INTERVAL not set or invalid, using default value (5s)")
+		clockInterval = 5 * time.Second
+	}
+	Config.ClockCheckInterval = clockInterval
+
+	inviteSecret := os.Getenv("INVITE_SECRET")
+	if inviteSecret == "" {
+		log.Warn("INVITE_SECRET not set, using a random value (invite links will not survive a restart)")
+		inviteSecret = uuid.New().String()
+	}
+	Config.InviteSecret = inviteSecret
+
+	drawOffersUnratedOnly, err := strconv.ParseBool(os.Getenv("DRAW_OFFERS_UNRATED_ONLY"))
+	if err != nil {
+		log.Warn("DRAW_OFFERS_UNRATED_ONLY not set or invalid, using default value (false)")
+		drawOffersUnratedOnly = false
+	}
+	Config.DrawOffersUnratedOnly = drawOffersUnratedOnly
+
+	takebacksUnratedOnly, err := strconv.ParseBool(os.Getenv("TAKEBACKS_UNRATED_ONLY"))
+	if err != nil {
+		log.Warn("TAKEBACKS_UNRATED_ONLY not set or invalid, using default value (true)")
+		takebacksUnratedOnly = true
+	}
+	Config.TakebacksUnratedOnly = takebacksUnratedOnly
+
+	aiTableSizeMB, err := strconv.Atoi(os.Getenv("AI_TT_SIZE_MB"))
+	if err != nil || aiTableSizeMB <= 0 {
+		log.Warn("AI_TT_SIZE_MB not set or invalid, using default value (64)")
+		aiTableSizeMB = 64
+	}
+	Config.AITableSizeMB = aiTableSizeMB
+
+	aiThreads, err := strconv.Atoi(os.Getenv("AI_THREADS"))
+	if err != nil || aiThreads <= 0 {
+		log.Warn("AI_THREADS not set or invalid, using default value (1)")
+		aiThreads = 1
+	}
+	Config.AIThreads = aiThreads
+
+	aiGameTables, err := strconv.Atoi(os.Getenv("AI_GAME_TABLES"))
+	if err != nil || aiGameTables < 0 {
+		log.Warn("AI_GAME_TABLES not set or invalid, using default value (8)")
+		aiGameTables = 8
+	}
+	Config.AIGameTables = aiGameTables
+
+	// Poids de l'évaluation heuristique, au format "winning_piece=2000,threat=-10,safe_piece=10,no_safe_piece=-500,parity=100"
+	Config.AIEvalWeights = os.Getenv("AI_EVAL_WEIGHTS")
+
+	// Table de fins de partie générée par cmd/tablebase (vide = aucune)
+	Config.AITablebase = os.Getenv("AI_TABLEBASE")
+
+	// Livre d'ouvertures généré par cmd/book (vide = aucun)
+	Config.AIOpeningBook = os.Getenv("AI_OPENING_BOOK")
+
+	aiMCTSExploration, err := strconv.ParseFloat(os.Getenv("AI_MCTS_EXPLORATION"), 64)
+	if err != nil || aiMCTSExploration <= 0 {
+		log.Warn("AI_MCTS_EXPLORATION not set or invalid, using default value (1.414)")
+		aiMCTSExploration = math.Sqrt2
+	}
+	Config.AIMCTSExploration = aiMCTSExploration
+
+	aiMCTSMaxNodes, err := strconv.Atoi(os.Getenv("AI_MCTS_MAX_NODES"))
+	if err != nil || aiMCTSMaxNodes < 0 {
+		log.Warn("AI_MCTS_MAX_NODES not set or invalid, using default value (100000)")
+		aiMCTSMaxNodes = 100000
+	}
+	Config.AIMCTSMaxNodes = aiMCTSMaxNodes
+
 	if env := os.Getenv("SMTP_HOST"); env != "" {
 		Config.Email.Host = env
 	} else {
@@ -22,7 +22,7 @@ const docTemplate = `{
     "paths": {
         "/ai/solve": {
             "post": {
-                "description": "Analyzes the current game state and returns the optimal move using minimax algorithm",
+                "description": "Analyzes the current game state and returns the best move found by an iterative deepening minimax search, bounded by the requested depth, time limit and node limit. The result comes from the last fully searched depth. Positions of the opening book or the endgame tablebase are answered without searching (0 nodes). With the mcts engine, the move is the most visited one of a Monte Carlo tree search bounded by the time limit and the node limit (as a number of iterations, 20000 by default), and the score reflects its win rate. Its tree is capped in size: past the cap, iterations only run playouts",
                 "consumes": [
                     "application/json"
                 ],
@@ -35,7 +35,7 @@ const docTemplate = `{
                 "summary": "Find the best move using AI",
                 "parameters": [
                     {
-                        "description": "Solve request containing game history and search depth",
+                        "description": "Solve request containing the game history (or a compact position) and search limits",
                         "name": "request",
                         "in": "body",
                         "required": true,
@@ -52,12 +52,9 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Bad request - invalid format, depth, move history, or game state",
+                        "description": "Bad request - invalid format, engine, search limits, move history, position, or game state",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apperror.Response"
                         }
                     }
                 }
@@ -370,16 +367,19 @@ const docTemplate = `{
                 }
             }
         },
-        "/challenge/my": {
-            "get": {
-                "description": "Get all challenges sent and received by the user",
+        "/challenge/counter": {
+            "post": {
+                "description": "Propose modified options for a pending challenge; the other player then has to accept or decline them",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "challenges"
                 ],
-                "summary": "Get my challenges",
+                "summary": "Counter challenge",
                 "parameters": [
                     {
                         "type": "string",
@@ -387,137 +387,119 @@ const docTemplate = `{
                         "name": "Quarto-Connect-Token",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "description": "Counter-proposal",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/challenge.CounterChallengeRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/challenge.ChallengeListResponse"
+                            "$ref": "#/definitions/challenge.Challenge"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apperror.Response"
                         }
                     }
                 }
             }
         },
-        "/challenge/respond": {
-            "post": {
-                "description": "Accept or decline a challenge",
-                "consumes": [
-                    "application/json"
-                ],
+        "/challenge/invite/{code}": {
+            "get": {
+                "description": "Get the challenge behind an invite code (no session required)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "challenges"
                 ],
-                "summary": "Respond to challenge",
+                "summary": "Get invite",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Session token",
-                        "name": "Quarto-Connect-Token",
-                        "in": "header",
+                        "description": "Invite code",
+                        "name": "code",
+                        "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Response to challenge",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/challenge.RespondToChallengeRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/challenge.ChallengeResponse"
+                            "$ref": "#/definitions/challenge.InvitePreviewResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apperror.Response"
                         }
                     }
                 }
             }
         },
-        "/challenge/send": {
+        "/challenge/invite/{code}/accept": {
             "post": {
-                "description": "Send a challenge to another player",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Accept the challenge behind an invite code and start the game. Without session, a guest account is created when the challenge allows it and its token is returned",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "challenges"
                 ],
-                "summary": "Send challenge",
+                "summary": "Accept invite",
                 "parameters": [
                     {
                         "type": "string",
                         "description": "Session token",
                         "name": "Quarto-Connect-Token",
-                        "in": "header",
-                        "required": true
+                        "in": "header"
                     },
                     {
-                        "description": "Send challenge request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/challenge.SendChallengeRequest"
-                        }
+                        "type": "string",
+                        "description": "Invite code",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/challenge.Challenge"
+                            "$ref": "#/definitions/challenge.AcceptInviteResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apperror.Response"
                         }
                     }
                 }
             }
         },
-        "/game/my": {
+        "/challenge/my": {
             "get": {
-                "description": "Get all games for the current user",
+                "description": "Get all challenges sent and received by the user",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "games"
+                    "challenges"
                 ],
-                "summary": "Get my games",
+                "summary": "Get my challenges",
                 "parameters": [
                     {
                         "type": "string",
@@ -525,46 +507,34 @@ const docTemplate = `{
                         "name": "Quarto-Connect-Token",
                         "in": "header",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Game status filter (active, finished)",
-                        "name": "status",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/game.Game"
-                            }
+                            "$ref": "#/definitions/challenge.ChallengeListResponse"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apperror.Response"
                         }
                     }
                 }
             }
         },
-        "/game/{id}": {
+        "/challenge/open": {
             "get": {
-                "description": "Get a game by ID",
+                "description": "Get the open challenges of the lobby that the user can join",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "games"
+                    "challenges"
                 ],
-                "summary": "Get game",
+                "summary": "Get open challenges",
                 "parameters": [
                     {
                         "type": "string",
@@ -572,41 +542,38 @@ const docTemplate = `{
                         "name": "Quarto-Connect-Token",
                         "in": "header",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Game ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/game.Game"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/challenge.Challenge"
+                            }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apperror.Response"
                         }
                     }
                 }
-            }
-        },
-        "/game/{id}/forfeit": {
+            },
             "post": {
-                "description": "Forfeit the current game",
+                "description": "Create a challenge without opponent, listed in the lobby (or only reachable by its invite link when invite_only is set)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "games"
+                    "challenges"
                 ],
-                "summary": "Forfeit game",
+                "summary": "Create open challenge",
                 "parameters": [
                     {
                         "type": "string",
@@ -616,35 +583,34 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Game ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Open challenge request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/challenge.CreateOpenChallengeRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/game.Game"
+                            "$ref": "#/definitions/challenge.OpenChallengeResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apperror.Response"
                         }
                     }
                 }
             }
         },
-        "/game/{id}/place-piece": {
+        "/challenge/respond": {
             "post": {
-                "description": "Place a piece on the board",
+                "description": "Accept or decline a challenge (or the opponent's counter-proposal)",
                 "consumes": [
                     "application/json"
                 ],
@@ -652,9 +618,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "games"
+                    "challenges"
                 ],
-                "summary": "Place piece",
+                "summary": "Respond to challenge",
                 "parameters": [
                     {
                         "type": "string",
@@ -664,19 +630,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Game ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Place piece request",
+                        "description": "Response to challenge",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/game.PlacePieceRequest"
+                            "$ref": "#/definitions/challenge.RespondToChallengeRequest"
                         }
                     }
                 ],
@@ -684,24 +643,21 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/game.Game"
+                            "$ref": "#/definitions/challenge.ChallengeResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apperror.Response"
                         }
                     }
                 }
             }
         },
-        "/game/{id}/select-piece": {
+        "/challenge/send": {
             "post": {
-                "description": "Select a piece for the next move",
+                "description": "Send a challenge to another player, with optional game options (starting player, time control, rated, expiry)",
                 "consumes": [
                     "application/json"
                 ],
@@ -709,9 +665,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "games"
+                    "challenges"
                 ],
-                "summary": "Select piece",
+                "summary": "Send challenge",
                 "parameters": [
                     {
                         "type": "string",
@@ -721,51 +677,41 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Game ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Select piece request",
+                        "description": "Send challenge request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/game.SelectPieceRequest"
+                            "$ref": "#/definitions/challenge.SendChallengeRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/game.Game"
+                            "$ref": "#/definitions/challenge.Challenge"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apperror.Response"
                         }
                     }
                 }
             }
         },
-        "/users": {
-            "get": {
-                "description": "Get paginated list of users",
+        "/challenge/{id}/join": {
+            "post": {
+                "description": "Accept an open challenge from the lobby and start the game",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "challenges"
                 ],
-                "summary": "Get users list",
+                "summary": "Join open challenge",
                 "parameters": [
                     {
                         "type": "string",
@@ -775,56 +721,39 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Page number (default: 1)",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page size (default: 20, max: 100)",
-                        "name": "page_size",
-                        "in": "query"
+                        "type": "string",
+                        "description": "Challenge ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/user.UserPaginationResponse"
+                            "$ref": "#/definitions/challenge.ChallengeResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apperror.Response"
                         }
                     }
                 }
             }
         },
-        "/users/{id}": {
+        "/friends": {
             "get": {
-                "description": "Get user information by ID",
+                "description": "Get the friends list of the user with online presence",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "friends"
                 ],
-                "summary": "Get user by ID",
+                "summary": "Get friends",
                 "parameters": [
                     {
                         "type": "string",
@@ -832,72 +761,1350 @@ const docTemplate = `{
                         "name": "Quarto-Connect-Token",
                         "in": "header",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/user.UserPublic"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/friend.Friend"
                             }
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apperror.Response"
                         }
                     }
                 }
             }
         },
-        "/ws": {
-            "get": {
-                "description": "Establish WebSocket connection for real-time communication",
+        "/friends/block": {
+            "post": {
+                "description": "Block a player: removes the friendship and prevents challenges, spectating and messages",
+                "consumes": [
+                    "application/json"
+                ],
                 "tags": [
-                    "websocket"
+                    "friends"
                 ],
-                "summary": "WebSocket connection",
+                "summary": "Block user",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Game ID (required for game-specific communication)",
-                        "name": "game_id",
-                        "in": "query",
+                        "description": "Session token",
+                        "name": "Quarto-Connect-Token",
+                        "in": "header",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Session token",
-                        "name": "token",
-                        "in": "query",
+                        "description": "User to block",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/friend.BlockUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/friends/block/{id}": {
+            "delete": {
+                "description": "Unblock a previously blocked player",
+                "tags": [
+                    "friends"
+                ],
+                "summary": "Unblock user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session token",
+                        "name": "Quarto-Connect-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Blocked user ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/friends/blocked": {
+            "get": {
+                "description": "Get the players blocked by the user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "friends"
+                ],
+                "summary": "Get blocked users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session token",
+                        "name": "Quarto-Connect-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/friend.BlockedUser"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/friends/request": {
+            "post": {
+                "description": "Send a friend request to another player (accepts it if that player already sent one)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "friends"
+                ],
+                "summary": "Send friend request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session token",
+                        "name": "Quarto-Connect-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Friend request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/friend.SendFriendRequestRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/friend.Friendship"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/friends/requests": {
+            "get": {
+                "description": "Get pending friend requests sent and received by the user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "friends"
+                ],
+                "summary": "Get friend requests",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session token",
+                        "name": "Quarto-Connect-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/friend.FriendRequestListResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/friends/respond": {
+            "post": {
+                "description": "Accept or decline a received friend request",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "friends"
+                ],
+                "summary": "Respond to friend request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session token",
+                        "name": "Quarto-Connect-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Response to friend request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/friend.RespondToFriendRequestRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/friend.Friendship"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/friends/{id}": {
+            "delete": {
+                "description": "Remove a friend or cancel a sent friend request",
+                "tags": [
+                    "friends"
+                ],
+                "summary": "Remove friend",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session token",
+                        "name": "Quarto-Connect-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Friend user ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/bot": {
+            "post": {
+                "description": "Start an unrated, untimed game against the server bot. Its moves are searched by the chosen engine (minimax or mcts) within the thinking time, and broadcast over the game WebSocket. Draw offers and takebacks are refused in bot games",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "games"
+                ],
+                "summary": "Create bot game",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session token",
+                        "name": "Quarto-Connect-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Bot game request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/game.CreateBotGameRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/game.Game"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/my": {
+            "get": {
+                "description": "Get all games for the current user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "games"
+                ],
+                "summary": "Get my games",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session token",
+                        "name": "Quarto-Connect-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Game status filter (active, finished)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/game.Game"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/{id}": {
+            "get": {
+                "description": "Get a game by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "games"
+                ],
+                "summary": "Get game",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session token",
+                        "name": "Quarto-Connect-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Game ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/game.Game"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/{id}/action": {
+            "post": {
+                "description": "Offer, accept or decline a draw, request, accept or decline a takeback of the last placement, or claim a Quarto (call Quarto games)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "games"
+                ],
+                "summary": "Game action",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session token",
+                        "name": "Quarto-Connect-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Game ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Game action request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/game.GameActionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/game.Game"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/{id}/analysis": {
+            "get": {
+                "description": "List the lines one piece away from a Quarto, the poisoned and safe pieces, and optionally an engine hint for the player to move. During the game, analysis requires hints to be enabled and each engine hint uses one of the player's hints; both are unlimited once the game is over",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "games"
+                ],
+                "summary": "Get game analysis",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session token",
+                        "name": "Quarto-Connect-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Game ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include an engine hint",
+                        "name": "hint",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/game.AnalysisResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/{id}/export": {
+            "get": {
+                "description": "Export a game as a portable text record (PGN-like header tags followed by the move list with piece hand-offs)",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "games"
+                ],
+                "summary": "Export game",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session token",
+                        "name": "Quarto-Connect-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Game ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Game record",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/{id}/forfeit": {
+            "post": {
+                "description": "Forfeit the current game",
+                "tags": [
+                    "games"
+                ],
+                "summary": "Forfeit game",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session token",
+                        "name": "Quarto-Connect-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Game ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/game.Game"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/{id}/legal-moves": {
+            "get": {
+                "description": "List the pieces that can be given (selection phase) or the squares where the selected piece can be placed (placement phase)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "games"
+                ],
+                "summary": "Get legal moves",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session token",
+                        "name": "Quarto-Connect-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Game ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/game.LegalMovesResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/{id}/place-piece": {
+            "post": {
+                "description": "Place a piece on the board. With dry_run, only report whether the placement is legal and whether it would win",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "games"
+                ],
+                "summary": "Place piece",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session token",
+                        "name": "Quarto-Connect-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Game ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Check the move without playing it (returns a game.MoveCheck)",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Place piece request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/game.PlacePieceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/game.Game"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/{id}/position": {
+            "get": {
+                "description": "Get the position of a game after the given ply (0 = initial position, default = current position)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "games"
+                ],
+                "summary": "Get position",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session token",
+                        "name": "Quarto-Connect-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Game ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ply number",
+                        "name": "ply",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/game.Ply"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Corrupt history, the message reports the first illegal ply",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/{id}/rematch": {
+            "post": {
+                "description": "Offer a rematch (colours swapped) after a finished game. If the opponent already offered one, it is accepted and the new game is returned",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "games"
+                ],
+                "summary": "Offer rematch",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session token",
+                        "name": "Quarto-Connect-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Game ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/challenge.ChallengeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/{id}/rematch/accept": {
+            "post": {
+                "description": "Accept the rematch offered by the opponent and start the new game",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "games"
+                ],
+                "summary": "Accept rematch",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session token",
+                        "name": "Quarto-Connect-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Game ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/challenge.ChallengeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/{id}/replay": {
+            "get": {
+                "description": "Get the position (board, available pieces, selected piece, phase, notation) after every ply of a game",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "games"
+                ],
+                "summary": "Get replay",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session token",
+                        "name": "Quarto-Connect-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Game ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/game.ReplayResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Corrupt history, the message reports the first illegal ply",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/{id}/select-piece": {
+            "post": {
+                "description": "Select a piece for the next move. With dry_run, only report whether the selection is legal",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "games"
+                ],
+                "summary": "Select piece",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session token",
+                        "name": "Quarto-Connect-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Game ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Check the move without playing it (returns a game.MoveCheck)",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Select piece request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/game.SelectPieceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/game.Game"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/{id}/series": {
+            "get": {
+                "description": "Get the series of consecutive rematches a game belongs to, with the running score",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "games"
+                ],
+                "summary": "Get series",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session token",
+                        "name": "Quarto-Connect-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Game ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/game.Series"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/games/import": {
+            "post": {
+                "description": "Parse a portable game record and replay it for analysis, without creating a game. Players are replayed as 1 (first to select) and 2.",
+                "consumes": [
+                    "text/plain"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "games"
+                ],
+                "summary": "Import game record",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session token",
+                        "name": "Quarto-Connect-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Game record",
+                        "name": "record",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/game.ImportedRecord"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Illegal move, the message reports the first illegal ply",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "description": "Get paginated list of users",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get users list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session token",
+                        "name": "Quarto-Connect-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (default: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default: 20, max: 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.UserPaginationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/settings": {
+            "get": {
+                "description": "Get the preferences of the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get my settings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session token",
+                        "name": "Quarto-Connect-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.UserSettings"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Update the preferences of the authenticated user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update my settings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session token",
+                        "name": "Quarto-Connect-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "New settings",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user.UserSettings"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.UserSettings"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "description": "Get user information by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get user by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session token",
+                        "name": "Quarto-Connect-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.UserPublic"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apperror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/ws": {
+            "get": {
+                "description": "Establish WebSocket connection for real-time communication",
+                "tags": [
+                    "websocket"
+                ],
+                "summary": "WebSocket connection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Game ID (omit to open a lobby connection for presence and notifications)",
+                        "name": "game_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Session token",
+                        "name": "token",
+                        "in": "query",
                         "required": true
                     }
                 ],
@@ -910,445 +2117,1045 @@ const docTemplate = `{
             "type": "object",
             "properties": {
                 "depth": {
+                    "description": "Profondeur maximale de minimax (défaut: 16)",
                     "type": "integer"
                 },
+                "engine": {
+                    "description": "Moteur de recherche : minimax (défaut) ou mcts",
+                    "type": "string"
+                },
                 "history": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
+                "node_limit": {
+                    "description": "Nombre maximal de nœuds visités, ou d'itérations de mcts (défaut: sans limite, 20000 itérations pour mcts)",
+                    "type": "integer"
+                },
+                "position": {
+                    "description": "Notation compacte de la position, à la place de history et selected_piece",
+                    "type": "string"
+                },
                 "selected_piece": {
                     "$ref": "#/definitions/game.Piece"
+                },
+                "time_limit_ms": {
+                    "description": "Durée maximale de la recherche (défaut: 5000, max: 30000)",
+                    "type": "integer"
+                },
+                "variant": {
+                    "description": "Variante de règles de l'historique (défaut: standard), incluse dans position",
+                    "type": "string"
+                }
+            }
+        },
+        "aiHandler.SolveResponse": {
+            "type": "object",
+            "properties": {
+                "best_move": {
+                    "type": "string"
+                },
+                "continuation": {
+                    "description": "Liste des coups de la continuation",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "depth": {
+                    "description": "Profondeur effectivement atteinte (mcts : longueur de la continuation)",
+                    "type": "integer"
+                },
+                "nodes": {
+                    "description": "Nombre de nœuds visités (mcts : nombre d'itérations)",
+                    "type": "integer"
+                },
+                "score": {
+                    "type": "integer"
+                },
+                "suggested_piece": {
+                    "description": "Pièce suggérée pour l'adversaire au coup suivant",
+                    "type": "integer"
+                }
+            }
+        },
+        "apperror.Response": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Code stable, exploitable par les clients",
+                    "type": "string"
+                },
+                "details": {
+                    "description": "Valeurs insérées dans le message",
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "message": {
+                    "description": "Message dans la langue demandée par Accept-Language",
+                    "type": "string"
+                }
+            }
+        },
+        "authHandler.AskRecoverForm": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "authHandler.LoginError400": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Please fully fill in the login form"
+                }
+            }
+        },
+        "authHandler.LoginError403": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Invalid email or password"
+                }
+            }
+        },
+        "authHandler.LoginForm": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "authHandler.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string"
+                },
+                "user": {
+                    "type": "object",
+                    "properties": {
+                        "email": {
+                            "type": "string"
+                        },
+                        "id": {
+                            "type": "integer"
+                        },
+                        "username": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "authHandler.LogoutError401": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Invalid session"
+                }
+            }
+        },
+        "authHandler.LogoutResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Logged out successfully"
+                }
+            }
+        },
+        "authHandler.MeError": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Incorrect token"
+                }
+            }
+        },
+        "authHandler.PasswordResetError400": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Please fully fill in the password reset form"
+                }
+            }
+        },
+        "authHandler.PasswordResetError403": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Invalid recover token"
+                }
+            }
+        },
+        "authHandler.PasswordResetForm": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "authHandler.PasswordResetResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Password reset successfully"
+                }
+            }
+        },
+        "authHandler.RecoverError400": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Please fully fill in the account recovery form"
+                }
+            }
+        },
+        "authHandler.RecoverResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Recovery token created successfully"
+                }
+            }
+        },
+        "authHandler.SignoutError400": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Empty password"
+                }
+            }
+        },
+        "authHandler.SignoutError403": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Invalid password"
                 }
             }
         },
-        "aiHandler.SolveResponse": {
+        "authHandler.SignoutForm": {
             "type": "object",
+            "required": [
+                "password"
+            ],
             "properties": {
-                "best_move": {
+                "password": {
                     "type": "string"
-                },
-                "continuation": {
-                    "description": "Liste des coups de la continuation",
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "score": {
-                    "type": "integer"
-                },
-                "suggested_piece": {
-                    "description": "Pièce suggérée pour l'adversaire au coup suivant",
-                    "type": "integer"
                 }
             }
         },
-        "authHandler.AskRecoverForm": {
+        "authHandler.SignoutResponse": {
             "type": "object",
             "properties": {
-                "email": {
-                    "type": "string"
+                "message": {
+                    "type": "string",
+                    "example": "Signout successful"
                 }
             }
         },
-        "authHandler.LoginError400": {
+        "authHandler.SignupError400": {
             "type": "object",
             "properties": {
                 "message": {
                     "type": "string",
-                    "example": "Please fully fill in the login form"
+                    "example": "Please fully fill in the signup form"
                 }
             }
         },
-        "authHandler.LoginError403": {
+        "authHandler.SignupError409": {
             "type": "object",
             "properties": {
                 "message": {
                     "type": "string",
-                    "example": "Invalid email or password"
+                    "example": "Email not available"
                 }
             }
         },
-        "authHandler.LoginForm": {
+        "authHandler.SignupForm": {
             "type": "object",
             "properties": {
                 "email": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "user@example.com"
                 },
                 "password": {
+                    "type": "string",
+                    "example": "Password123!"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "john_doe"
+                }
+            }
+        },
+        "authHandler.SignupResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Signup successful"
+                }
+            }
+        },
+        "authHandler.UserResponse": {
+            "type": "object",
+            "properties": {
+                "email": {
                     "type": "string"
                 },
-                "token": {
+                "id": {
+                    "type": "integer"
+                },
+                "username": {
                     "type": "string"
                 }
             }
         },
-        "authHandler.LoginResponse": {
+        "challenge.AcceptInviteResponse": {
             "type": "object",
             "properties": {
+                "challenge": {
+                    "$ref": "#/definitions/challenge.Challenge"
+                },
+                "game": {},
                 "token": {
+                    "description": "Jeton de session du compte invité créé",
                     "type": "string"
                 },
                 "user": {
-                    "type": "object",
-                    "properties": {
-                        "email": {
-                            "type": "string"
-                        },
-                        "id": {
-                            "type": "integer"
-                        },
-                        "username": {
-                            "type": "string"
-                        }
-                    }
+                    "description": "Compte invité créé"
                 }
             }
         },
-        "authHandler.LogoutError401": {
+        "challenge.Challenge": {
             "type": "object",
             "properties": {
+                "allow_guest": {
+                    "description": "Le lien d'invitation peut créer un compte invité",
+                    "type": "boolean"
+                },
+                "challenged_id": {
+                    "type": "integer"
+                },
+                "challenger_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "game_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
                 "message": {
-                    "type": "string",
-                    "example": "Invalid session"
+                    "type": "string"
+                },
+                "options": {
+                    "$ref": "#/definitions/challenge.Options"
+                },
+                "proposed_by": {
+                    "description": "Auteur de la dernière proposition, l'autre joueur doit répondre",
+                    "type": "integer"
+                },
+                "rematch_of": {
+                    "description": "Partie dont ce défi propose la revanche",
+                    "type": "string"
+                },
+                "responded_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "pending, accepted, declined, expired, cancelled",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "visibility": {
+                    "description": "direct, open, invite",
+                    "type": "string"
                 }
             }
         },
-        "authHandler.LogoutResponse": {
+        "challenge.ChallengeListResponse": {
             "type": "object",
             "properties": {
-                "message": {
-                    "type": "string",
-                    "example": "Logged out successfully"
+                "received": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/challenge.Challenge"
+                    }
+                },
+                "sent": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/challenge.Challenge"
+                    }
                 }
             }
         },
-        "authHandler.MeError": {
+        "challenge.ChallengeResponse": {
             "type": "object",
             "properties": {
-                "message": {
-                    "type": "string",
-                    "example": "Incorrect token"
+                "challenge": {
+                    "$ref": "#/definitions/challenge.Challenge"
+                },
+                "game": {}
+            }
+        },
+        "challenge.CounterChallengeRequest": {
+            "type": "object",
+            "required": [
+                "challenge_id"
+            ],
+            "properties": {
+                "challenge_id": {
+                    "type": "string"
+                },
+                "options": {
+                    "$ref": "#/definitions/challenge.OptionsRequest"
                 }
             }
         },
-        "authHandler.PasswordResetError400": {
+        "challenge.CreateOpenChallengeRequest": {
             "type": "object",
             "properties": {
+                "allow_guest": {
+                    "description": "Le lien d'invitation peut créer un compte invité",
+                    "type": "boolean"
+                },
+                "invite_only": {
+                    "description": "Ne pas lister le défi dans le lobby",
+                    "type": "boolean"
+                },
                 "message": {
-                    "type": "string",
-                    "example": "Please fully fill in the password reset form"
+                    "type": "string"
+                },
+                "options": {
+                    "$ref": "#/definitions/challenge.OptionsRequest"
                 }
             }
         },
-        "authHandler.PasswordResetError403": {
+        "challenge.Invite": {
             "type": "object",
             "properties": {
-                "message": {
-                    "type": "string",
-                    "example": "Invalid recover token"
+                "code": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
                 }
             }
         },
-        "authHandler.PasswordResetForm": {
+        "challenge.InvitePreviewResponse": {
             "type": "object",
             "properties": {
-                "password": {
+                "challenge": {
+                    "$ref": "#/definitions/challenge.Challenge"
+                },
+                "challenger": {
+                    "$ref": "#/definitions/user.UserPublic"
+                }
+            }
+        },
+        "challenge.OpenChallengeResponse": {
+            "type": "object",
+            "properties": {
+                "challenge": {
+                    "$ref": "#/definitions/challenge.Challenge"
+                },
+                "invite": {
+                    "$ref": "#/definitions/challenge.Invite"
+                }
+            }
+        },
+        "challenge.Options": {
+            "type": "object",
+            "properties": {
+                "call_quarto": {
+                    "type": "boolean"
+                },
+                "hints": {
+                    "type": "integer"
+                },
+                "rated": {
+                    "type": "boolean"
+                },
+                "starter": {
+                    "description": "challenger, challenged, random",
                     "type": "string"
                 },
-                "token": {
+                "time_control": {
+                    "$ref": "#/definitions/game.TimeControl"
+                },
+                "variant": {
+                    "description": "standard, squares, squares_torus",
                     "type": "string"
                 }
             }
         },
-        "authHandler.PasswordResetResponse": {
+        "challenge.OptionsRequest": {
             "type": "object",
             "properties": {
-                "message": {
+                "call_quarto": {
+                    "description": "Les victoires doivent être annoncées",
+                    "type": "boolean"
+                },
+                "expires_in_minutes": {
+                    "description": "Durée de validité du défi (défaut: 24h)",
+                    "type": "integer"
+                },
+                "hints": {
+                    "description": "Indices accordés à chaque joueur (parties non classées)",
+                    "type": "integer"
+                },
+                "rated": {
+                    "type": "boolean"
+                },
+                "starter": {
+                    "description": "Premier joueur, du point de vue de l'auteur (défaut: me)",
                     "type": "string",
-                    "example": "Password reset successfully"
+                    "enum": [
+                        "me",
+                        "opponent",
+                        "random"
+                    ]
+                },
+                "time_control": {
+                    "$ref": "#/definitions/game.TimeControl"
+                },
+                "variant": {
+                    "description": "Variante de règles (défaut: standard)",
+                    "type": "string",
+                    "enum": [
+                        "standard",
+                        "squares",
+                        "squares_torus"
+                    ]
+                }
+            }
+        },
+        "challenge.RespondToChallengeRequest": {
+            "type": "object",
+            "required": [
+                "challenge_id"
+            ],
+            "properties": {
+                "accept": {
+                    "type": "boolean"
+                },
+                "challenge_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "challenge.SendChallengeRequest": {
+            "type": "object",
+            "required": [
+                "challenged_id"
+            ],
+            "properties": {
+                "challenged_id": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "options": {
+                    "$ref": "#/definitions/challenge.OptionsRequest"
                 }
             }
         },
-        "authHandler.RecoverError400": {
+        "friend.BlockUserRequest": {
             "type": "object",
+            "required": [
+                "user_id"
+            ],
             "properties": {
-                "message": {
-                    "type": "string",
-                    "example": "Please fully fill in the account recovery form"
+                "user_id": {
+                    "type": "integer"
                 }
             }
         },
-        "authHandler.RecoverResponse": {
+        "friend.BlockedUser": {
             "type": "object",
             "properties": {
-                "message": {
-                    "type": "string",
-                    "example": "Recovery token created successfully"
+                "created_at": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/user.UserPublic"
                 }
             }
         },
-        "authHandler.SignoutError400": {
+        "friend.Friend": {
             "type": "object",
             "properties": {
-                "message": {
-                    "type": "string",
-                    "example": "Empty password"
+                "online": {
+                    "type": "boolean"
+                },
+                "since": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/user.UserPublic"
                 }
             }
         },
-        "authHandler.SignoutError403": {
+        "friend.FriendRequest": {
             "type": "object",
             "properties": {
-                "message": {
-                    "type": "string",
-                    "example": "Invalid password"
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "user": {
+                    "description": "L'autre joueur (destinataire ou expéditeur)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/user.UserPublic"
+                        }
+                    ]
                 }
             }
         },
-        "authHandler.SignoutForm": {
+        "friend.FriendRequestListResponse": {
             "type": "object",
-            "required": [
-                "password"
-            ],
             "properties": {
-                "password": {
-                    "type": "string"
+                "received": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/friend.FriendRequest"
+                    }
+                },
+                "sent": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/friend.FriendRequest"
+                    }
                 }
             }
         },
-        "authHandler.SignoutResponse": {
+        "friend.Friendship": {
             "type": "object",
             "properties": {
-                "message": {
-                    "type": "string",
-                    "example": "Signout successful"
+                "addressee_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "requester_id": {
+                    "type": "integer"
+                },
+                "responded_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "pending, accepted",
+                    "type": "string"
                 }
             }
         },
-        "authHandler.SignupError400": {
+        "friend.RespondToFriendRequestRequest": {
             "type": "object",
+            "required": [
+                "request_id"
+            ],
             "properties": {
-                "message": {
-                    "type": "string",
-                    "example": "Please fully fill in the signup form"
+                "accept": {
+                    "type": "boolean"
+                },
+                "request_id": {
+                    "type": "integer"
                 }
             }
         },
-        "authHandler.SignupError409": {
+        "friend.SendFriendRequestRequest": {
             "type": "object",
+            "required": [
+                "user_id"
+            ],
             "properties": {
-                "message": {
-                    "type": "string",
-                    "example": "Email not available"
+                "user_id": {
+                    "type": "integer"
                 }
             }
         },
-        "authHandler.SignupForm": {
+        "game.AnalysisResponse": {
             "type": "object",
             "properties": {
-                "email": {
-                    "type": "string",
-                    "example": "user@example.com"
+                "best_moves": {
+                    "description": "Continuation conseillée par le moteur",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "password": {
-                    "type": "string",
-                    "example": "Password123!"
+                "evaluation": {
+                    "description": "Évaluation du moteur pour le joueur au trait, entre -1 et 1 : 1 gain forcé, -1 perte forcée, sinon estimation heuristique (0 nulle ou position équilibrée)",
+                    "type": "number"
                 },
-                "username": {
-                    "type": "string",
-                    "example": "john_doe"
+                "game_id": {
+                    "type": "string"
+                },
+                "hint": {
+                    "description": "Coup conseillé par le moteur, si demandé",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/game.Hint"
+                        }
+                    ]
+                },
+                "hints_remaining": {
+                    "description": "Indices restant au joueur (-1 si illimité)",
+                    "type": "integer"
+                },
+                "poisoned_pieces": {
+                    "description": "Pièces qui permettraient à celui qui les reçoit de gagner",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/game.Piece"
+                    }
+                },
+                "safe_pieces": {
+                    "description": "Pièces ne complétant aucun alignement",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/game.Piece"
+                    }
+                },
+                "threats": {
+                    "description": "Alignements auxquels il ne manque qu'une pièce",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/game.Threat"
+                    }
                 }
             }
         },
-        "authHandler.SignupResponse": {
+        "game.BotOptions": {
             "type": "object",
             "properties": {
-                "message": {
+                "engine": {
+                    "description": "Moteur de recherche (défaut: minimax)",
                     "type": "string",
-                    "example": "Signup successful"
+                    "enum": [
+                        "minimax",
+                        "mcts"
+                    ]
+                },
+                "player_id": {
+                    "type": "integer"
+                },
+                "time_limit_ms": {
+                    "description": "Temps de réflexion par coup (défaut: 5000)",
+                    "type": "integer"
                 }
             }
         },
-        "authHandler.UserResponse": {
+        "game.CreateBotGameRequest": {
             "type": "object",
             "properties": {
-                "email": {
-                    "type": "string"
+                "engine": {
+                    "description": "Moteur de recherche du bot (défaut: minimax)",
+                    "type": "string",
+                    "enum": [
+                        "minimax",
+                        "mcts"
+                    ]
                 },
-                "id": {
+                "starter": {
+                    "description": "Premier joueur (défaut: me)",
+                    "type": "string",
+                    "enum": [
+                        "me",
+                        "bot",
+                        "random"
+                    ]
+                },
+                "time_limit_ms": {
+                    "description": "Temps de réflexion du bot par coup (défaut: 5000, max: 30000)",
                     "type": "integer"
                 },
-                "username": {
-                    "type": "string"
+                "variant": {
+                    "description": "Variante de règles (défaut: standard)",
+                    "type": "string",
+                    "enum": [
+                        "standard",
+                        "squares",
+                        "squares_torus"
+                    ]
                 }
             }
         },
-        "challenge.Challenge": {
+        "game.Game": {
             "type": "object",
             "properties": {
-                "challenged_id": {
-                    "type": "integer"
+                "available_pieces": {
+                    "description": "List of available pieces (1-16)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/game.Piece"
+                    }
                 },
-                "challenger_id": {
-                    "type": "integer"
+                "board": {
+                    "description": "4x4 matrix of Piece",
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "$ref": "#/definitions/game.Piece"
+                        }
+                    }
                 },
                 "created_at": {
                     "type": "string"
                 },
-                "expires_at": {
-                    "type": "string"
+                "current_turn": {
+                    "description": "ID of the player whose turn it is",
+                    "type": "integer"
                 },
-                "game_id": {
-                    "type": "string"
+                "draw_offered_by": {
+                    "description": "Joueur ayant proposé la nulle (0 si aucune proposition)",
+                    "type": "integer"
+                },
+                "game_phase": {
+                    "description": "0 = \"selectPiece\", 1 = \"placePiece\"",
+                    "type": "integer"
                 },
                 "id": {
                     "type": "string"
                 },
-                "message": {
+                "move_history": {
+                    "description": "Selections and placements made in the game",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/game.MoveEvent"
+                    }
+                },
+                "options": {
+                    "description": "Options chosen when the game was created",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/game.GameOptions"
+                        }
+                    ]
+                },
+                "player1_hints_used": {
+                    "description": "Indices consommés par le joueur 1",
+                    "type": "integer"
+                },
+                "player1_id": {
+                    "type": "integer"
+                },
+                "player2_hints_used": {
+                    "description": "Indices consommés par le joueur 2",
+                    "type": "integer"
+                },
+                "player2_id": {
+                    "type": "integer"
+                },
+                "previous_game_id": {
+                    "description": "Partie dont celle-ci est la revanche",
                     "type": "string"
                 },
-                "responded_at": {
+                "selected_piece": {
+                    "description": "Current piece to place",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/game.Piece"
+                        }
+                    ]
+                },
+                "series_id": {
+                    "description": "Série de revanches à laquelle appartient la partie",
                     "type": "string"
                 },
                 "status": {
-                    "description": "pending, accepted, declined, expired, cancelled",
-                    "type": "string"
+                    "description": "0 = \"playing\", 1 = \"finished\"",
+                    "type": "integer"
+                },
+                "takeback_requested_by": {
+                    "description": "Joueur ayant demandé l'annulation de son coup (0 si aucune demande)",
+                    "type": "integer"
                 },
                 "updated_at": {
                     "type": "string"
+                },
+                "winner": {
+                    "description": "ID of the winner (0 if draw)",
+                    "type": "integer"
                 }
             }
         },
-        "challenge.ChallengeListResponse": {
+        "game.GameActionRequest": {
             "type": "object",
+            "required": [
+                "action"
+            ],
             "properties": {
-                "received": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/challenge.Challenge"
-                    }
-                },
-                "sent": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/challenge.Challenge"
-                    }
+                "action": {
+                    "description": "offer_draw, accept_draw, decline_draw, request_takeback, accept_takeback, decline_takeback, claim_quarto",
+                    "type": "string",
+                    "example": "offer_draw"
                 }
             }
         },
-        "challenge.ChallengeResponse": {
+        "game.GameOptions": {
             "type": "object",
             "properties": {
-                "challenge": {
-                    "$ref": "#/definitions/challenge.Challenge"
+                "bot": {
+                    "description": "Adversaire joué par le serveur (parties contre un bot)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/game.BotOptions"
+                        }
+                    ]
                 },
-                "game": {}
-            }
-        },
-        "challenge.RespondToChallengeRequest": {
-            "type": "object",
-            "required": [
-                "challenge_id"
-            ],
-            "properties": {
-                "accept": {
+                "call_quarto": {
+                    "description": "Les victoires doivent être annoncées par l'action claim_quarto",
+                    "type": "boolean"
+                },
+                "hints": {
+                    "description": "Indices accordés à chaque joueur pendant la partie",
+                    "type": "integer"
+                },
+                "rated": {
                     "type": "boolean"
                 },
-                "challenge_id": {
-                    "type": "string"
+                "time_control": {
+                    "$ref": "#/definitions/game.TimeControl"
+                },
+                "variant": {
+                    "description": "Variante de règles (défaut: standard)",
+                    "type": "string",
+                    "enum": [
+                        "standard",
+                        "squares",
+                        "squares_torus"
+                    ]
                 }
             }
         },
-        "challenge.SendChallengeRequest": {
+        "game.Hint": {
             "type": "object",
-            "required": [
-                "challenged_id"
-            ],
             "properties": {
-                "challenged_id": {
-                    "type": "integer"
+                "evaluation": {
+                    "description": "Entre -1 et 1 : 1 gain forcé, -1 perte forcée, sinon estimation heuristique (0 nulle ou position équilibrée)",
+                    "type": "number"
                 },
-                "message": {
+                "piece": {
+                    "description": "Pièce à donner à l'adversaire (-1 si aucune)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/game.Piece"
+                        }
+                    ]
+                },
+                "position": {
+                    "description": "Case où placer la pièce en main (phase de placement)",
                     "type": "string"
                 }
             }
         },
-        "game.Game": {
+        "game.ImportedRecord": {
             "type": "object",
             "properties": {
-                "available_pieces": {
-                    "description": "List of available pieces (1-16)",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/game.Piece"
-                    }
-                },
-                "board": {
-                    "description": "4x4 matrix of Piece",
+                "plies": {
                     "type": "array",
                     "items": {
-                        "type": "array",
-                        "items": {
-                            "$ref": "#/definitions/game.Piece"
-                        }
+                        "$ref": "#/definitions/game.Ply"
                     }
                 },
-                "created_at": {
-                    "type": "string"
-                },
+                "record": {
+                    "$ref": "#/definitions/game.Record"
+                }
+            }
+        },
+        "game.LegalMovesResponse": {
+            "type": "object",
+            "properties": {
                 "current_turn": {
-                    "description": "ID of the player whose turn it is",
                     "type": "integer"
                 },
+                "game_id": {
+                    "type": "string"
+                },
                 "game_phase": {
-                    "description": "0 = \"selectPiece\", 1 = \"placePiece\"",
                     "type": "integer"
                 },
-                "id": {
-                    "type": "string"
-                },
-                "move_history": {
-                    "description": "List of moves made in the game",
+                "pieces": {
+                    "description": "Pièces pouvant être données (phase de sélection)",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/game.Move"
+                        "$ref": "#/definitions/game.Piece"
                     }
                 },
-                "player1_id": {
-                    "type": "integer"
-                },
-                "player2_id": {
-                    "type": "integer"
-                },
-                "selected_piece": {
-                    "description": "Current piece to place",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/game.Piece"
-                        }
-                    ]
-                },
-                "status": {
-                    "description": "0 = \"playing\", 1 = \"finished\"",
-                    "type": "integer"
-                },
-                "updated_at": {
-                    "type": "string"
-                },
-                "winner": {
-                    "description": "ID of the winner (0 if draw)",
-                    "type": "integer"
+                "positions": {
+                    "description": "Cases libres pour la pièce sélectionnée (phase de placement)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "game.Move": {
+        "game.MoveEvent": {
             "type": "object",
             "properties": {
+                "accepted": {
+                    "description": "Annonce de Quarto fondée (claim uniquement)",
+                    "type": "boolean"
+                },
+                "actor": {
+                    "description": "Joueur ayant effectué l'action",
+                    "type": "integer"
+                },
                 "piece": {
-                    "description": "ID de la pièce sélectionnée par l'adversaire (0-15)",
+                    "description": "Pièce sélectionnée ou placée",
                     "allOf": [
                         {
                             "$ref": "#/definitions/game.Piece"
@@ -1356,12 +3163,24 @@ const docTemplate = `{
                     ]
                 },
                 "position": {
-                    "description": "Position où placer la pièce sélectionnée",
+                    "description": "Position du placement",
                     "allOf": [
                         {
                             "$ref": "#/definitions/game.Position"
                         }
                     ]
+                },
+                "thinking_ms": {
+                    "description": "Temps de réflexion depuis l'action précédente",
+                    "type": "integer"
+                },
+                "timestamp": {
+                    "description": "Date de l'action (vide pour les anciennes parties)",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "select, place, claim",
+                    "type": "string"
                 }
             }
         },
@@ -1417,6 +3236,60 @@ const docTemplate = `{
                 }
             }
         },
+        "game.Ply": {
+            "type": "object",
+            "properties": {
+                "available_pieces": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/game.Piece"
+                    }
+                },
+                "board": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "$ref": "#/definitions/game.Piece"
+                        }
+                    }
+                },
+                "current_turn": {
+                    "type": "integer"
+                },
+                "event": {
+                    "description": "Action ayant mené à cette position",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/game.MoveEvent"
+                        }
+                    ]
+                },
+                "fen": {
+                    "description": "Notation compacte de la position",
+                    "type": "string"
+                },
+                "game_phase": {
+                    "type": "integer"
+                },
+                "notation": {
+                    "description": "BCGP pour une sélection, BCGP-a1 pour un placement",
+                    "type": "string"
+                },
+                "ply": {
+                    "type": "integer"
+                },
+                "selected_piece": {
+                    "$ref": "#/definitions/game.Piece"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "winner": {
+                    "type": "integer"
+                }
+            }
+        },
         "game.Position": {
             "type": "object",
             "properties": {
@@ -1428,6 +3301,55 @@ const docTemplate = `{
                 }
             }
         },
+        "game.Record": {
+            "type": "object",
+            "properties": {
+                "moves": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/game.RecordMove"
+                    }
+                },
+                "result": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "game.RecordMove": {
+            "type": "object",
+            "properties": {
+                "claim": {
+                    "description": "Quarto annoncé après le dernier élément du tour, par le joueur qui a reçu la pièce",
+                    "type": "boolean"
+                },
+                "piece": {
+                    "$ref": "#/definitions/game.Piece"
+                },
+                "position": {
+                    "$ref": "#/definitions/game.Position"
+                }
+            }
+        },
+        "game.ReplayResponse": {
+            "type": "object",
+            "properties": {
+                "game_id": {
+                    "type": "string"
+                },
+                "plies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/game.Ply"
+                    }
+                }
+            }
+        },
         "game.SelectPieceRequest": {
             "type": "object",
             "required": [
@@ -1439,6 +3361,105 @@ const docTemplate = `{
                 }
             }
         },
+        "game.Series": {
+            "type": "object",
+            "properties": {
+                "games": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/game.SeriesGame"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "player1_id": {
+                    "description": "Joueur 1 de la première partie",
+                    "type": "integer"
+                },
+                "player2_id": {
+                    "description": "Joueur 2 de la première partie",
+                    "type": "integer"
+                },
+                "score": {
+                    "$ref": "#/definitions/game.SeriesScore"
+                }
+            }
+        },
+        "game.SeriesGame": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "game_id": {
+                    "type": "string"
+                },
+                "player1_id": {
+                    "type": "integer"
+                },
+                "player2_id": {
+                    "type": "integer"
+                },
+                "score": {
+                    "$ref": "#/definitions/game.SeriesScore"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "winner": {
+                    "type": "integer"
+                }
+            }
+        },
+        "game.SeriesScore": {
+            "type": "object",
+            "properties": {
+                "draws": {
+                    "type": "integer"
+                },
+                "player1_wins": {
+                    "type": "integer"
+                },
+                "player2_wins": {
+                    "type": "integer"
+                }
+            }
+        },
+        "game.Threat": {
+            "type": "object",
+            "properties": {
+                "characteristics": {
+                    "description": "Caractéristiques qu'une pièce doit partager pour le compléter",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "square": {
+                    "description": "Case libre à compléter",
+                    "type": "string"
+                },
+                "squares": {
+                    "description": "Cases de l'alignement",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "game.TimeControl": {
+            "type": "object",
+            "properties": {
+                "increment_seconds": {
+                    "type": "integer"
+                },
+                "initial_seconds": {
+                    "type": "integer"
+                }
+            }
+        },
         "user.UserPaginationResponse": {
             "type": "object",
             "properties": {
@@ -1472,6 +3493,15 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "user.UserSettings": {
+            "type": "object",
+            "properties": {
+                "friends_only_challenges": {
+                    "description": "Seuls les amis peuvent envoyer un défi",
+                    "type": "boolean"
+                }
+            }
         }
     }
 }`
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"quarto/models/ai"
 	"quarto/models/game"
+	"time"
 
 	"github.com/labstack/echo/v4"
 )
@@ -11,7 +12,12 @@ import (
 type SolveRequest struct {
 	History       []string   `json:"history"`
 	SelectedPiece game.Piece `json:"selected_piece"`
-	Depth         int        `json:"depth"`
+	Position      string     `json:"position"`      // Notation compacte de la position, à la place de history et selected_piece
+	Variant       string     `json:"variant"`       // Variante de règles de l'historique (défaut: standard), incluse dans position
+	Engine        string     `json:"engine"`        // Moteur de recherche : minimax (défaut) ou mcts
+	Depth         int        `json:"depth"`         // Profondeur maximale de minimax (défaut: 16)
+	TimeLimitMs   int        `json:"time_limit_ms"` // Durée maximale de la recherche (défaut: 5000, max: 30000)
+	NodeLimit     int        `json:"node_limit"`    // Nombre maximal de nœuds visités, ou d'itérations de mcts (défaut: sans limite, 20000 itérations pour mcts)
 }
 
 type SolveResponse struct {
@@ -19,18 +25,20 @@ type SolveResponse struct {
 	Score          int      `json:"score"`
 	SuggestedPiece int      `json:"suggested_piece"` // Pièce suggérée pour l'adversaire au coup suivant
 	Continuation   []string `json:"continuation"`    // Liste des coups de la continuation
+	Depth          int      `json:"depth"`           // Profondeur effectivement atteinte (mcts : longueur de la continuation)
+	Nodes          int      `json:"nodes"`           // Nombre de nœuds visités (mcts : nombre d'itérations)
 }
 
 // solve handles the AI solve request for finding the best move in a Quarto game.
 //
 // @Summary Find the best move using AI
-// @Description Analyzes the current game state and returns the optimal move using minimax algorithm
+// @Description Analyzes the current game state and returns the best move found by an iterative deepening minimax search, bounded by the requested depth, time limit and node limit. The result comes from the last fully searched depth. Positions of the opening book or the endgame tablebase are answered without searching (0 nodes). With the mcts engine, the move is the most visited one of a Monte Carlo tree search bounded by the time limit and the node limit (as a number of iterations, 20000 by default), and the score reflects its win rate. Its tree is capped in size: past the cap, iterations only run playouts
 // @Tags AI
 // @Accept json
 // @Produce json
-// @Param request body SolveRequest true "Solve request containing game history and search depth"
+// @Param request body SolveRequest true "Solve request containing the game history (or a compact position) and search limits"
 // @Success 200 {object} SolveResponse "Best move and evaluation score"
-// @Failure 400 {object} map[string]string "Bad request - invalid format, depth, move history, or game state"
+// @Failure 400 {object} apperror.Response "Bad request - invalid format, engine, search limits, move history, position, or game state"
 // @Router /ai/solve [post]
 func solve(c echo.Context) error {
 
@@ -39,22 +47,99 @@ func solve(c echo.Context) error {
 		return echo.NewHTTPError(400, "Invalid request format")
 	}
 
-	// Validate depth
+	// Validate search limits
+	if req.Depth == 0 {
+		req.Depth = ai.DefaultMaxDepth
+	}
 	if req.Depth < 1 || req.Depth > 16 {
 		return echo.NewHTTPError(400, "Depth must be between 1 and 16")
 	}
+	timeLimit := ai.DefaultTimeLimit
+	if req.TimeLimitMs != 0 {
+		timeLimit = time.Duration(req.TimeLimitMs) * time.Millisecond
+	}
+	if timeLimit < 0 || timeLimit > ai.MaxTimeLimit {
+		return echo.NewHTTPError(400, fmt.Sprintf("Time limit must be between 1 and %d ms", ai.MaxTimeLimit.Milliseconds()))
+	}
+	if req.NodeLimit < 0 {
+		return echo.NewHTTPError(400, "Node limit must be positive")
+	}
+	if req.Engine == ai.SearcherMCTS && req.NodeLimit == 0 {
+		req.NodeLimit = ai.DefaultMCTSIterations
+	}
+
+	var state ai.GameState
+	switch {
+	case req.Position != "" && len(req.History) > 0:
+		return echo.NewHTTPError(400, "Provide either a position or a move history, not both")
+	case req.Position != "":
+		g, err := game.ParseFEN(req.Position, 1, 2)
+		if err != nil {
+			return echo.NewHTTPError(400, err)
+		}
+		if g.Status == game.StatusFinished {
+			return echo.NewHTTPError(400, "The game is already over in this position")
+		}
+		if g.GamePhase != game.GamePhasePlacePiece {
+			return echo.NewHTTPError(400, "The position must have a piece in hand to place")
+		}
+		state = ai.ConvertGameToState(g)
+	default:
+		if !game.IsValidVariant(req.Variant) {
+			return echo.NewHTTPError(400, "Unknown variant: "+req.Variant)
+		}
+		var err error
+		state, err = stateFromHistory(req.History, req.SelectedPiece, req.Variant)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Initialize AI engine with the specified limits
+	engine, err := ai.NewSearcher(req.Engine, req.Depth, timeLimit, req.NodeLimit)
+	if err != nil {
+		return echo.NewHTTPError(400, "Unknown engine: "+req.Engine)
+	}
+
+	fmt.Printf("State: AvailablePieces=%v, SelectedPiece=%v, IsGameOver=%t, Winner=%d\n",
+		state.AvailablePieces, state.SelectedPiece, state.IsGameOver, state.Winner)
+
+	// La recherche s'arrête aussi si le client se déconnecte
+	result := engine.SearchContext(c.Request().Context(), state)
+
+	fmt.Printf("Best move found: Score=%d (%v), Depth=%d, Move=%d (%d) on %d,%d give %d\n", result.Score, len(state.AvailablePieces)%2 == 0, result.Depth, result.BestMoves[0].Move.Piece, state.SelectedPiece, result.BestMoves[0].Move.Position.Row, result.BestMoves[0].Move.Position.Col, result.BestMoves[0].SelectedPiece)
+
+	bestMoveNotation := game.CreateMoveNotation(result.BestMoves[0].Move.Piece, game.CoordsToPosition(result.BestMoves[0].Move.Position.Row, result.BestMoves[0].Move.Position.Col))
 
-	// Parse the move history
+	var strContinuation []string
+	for _, move := range result.BestMoves {
+		notation := game.CreateMoveNotation(move.Move.Piece, game.CoordsToPosition(move.Move.Position.Row, move.Move.Position.Col))
+		strContinuation = append(strContinuation, notation)
+	}
+
+	return c.JSON(200, SolveResponse{
+		BestMove:       bestMoveNotation,
+		Score:          result.Score,
+		SuggestedPiece: int(result.BestMoves[0].SelectedPiece),
+		Continuation:   strContinuation,
+		Depth:          result.Depth,
+		Nodes:          result.Nodes,
+	})
+
+}
+
+// stateFromHistory reconstruit la position à partir de l'historique des coups et de la pièce à placer
+func stateFromHistory(history []string, selectedPiece game.Piece, variant string) (ai.GameState, error) {
 	var moves []game.Move
-	for _, moveStr := range req.History {
+	for _, moveStr := range history {
 		piece, strPosition, err := game.ParseMoveNotation(moveStr)
 		if err != nil {
-			return echo.NewHTTPError(400, "Invalid move in history: "+moveStr)
+			return ai.GameState{}, err
 		}
 
 		row, col, err := game.PositionToCoords(strPosition)
 		if err != nil {
-			return echo.NewHTTPError(400, "Invalid position in move: "+moveStr)
+			return ai.GameState{}, err
 		}
 
 		fmt.Printf("Parsed move: Piece ID=%d, Position=%s (Row=%d, Col=%d)\n", piece, strPosition, row, col)
@@ -64,49 +149,22 @@ func solve(c echo.Context) error {
 			Position: game.Position{Row: row, Col: col},
 		})
 	}
-	// Initialize AI engine with the specified depth
-	depth := req.Depth
-	if len(moves) <= 6 {
-		depth = 5
-	}
-	engine := ai.NewEngine(depth)
 
-	state := ai.ConvertHistoryToGameState(moves)
+	state := ai.ConvertHistoryToGameState(moves, variant)
 	found := false
 	newAvailablePieces := make([]game.Piece, 0, len(state.AvailablePieces))
 	for _, piece := range state.AvailablePieces {
-		if piece == req.SelectedPiece {
+		if piece == selectedPiece {
 			found = true
 			continue
 		}
 		newAvailablePieces = append(newAvailablePieces, piece)
 	}
 	if !found {
-		return echo.NewHTTPError(400, "Selected piece not found in available pieces")
+		return ai.GameState{}, echo.NewHTTPError(400, "Selected piece not found in available pieces")
 	}
-	state.SelectedPiece = req.SelectedPiece
+	state.SelectedPiece = selectedPiece
 	state.AvailablePieces = newAvailablePieces
 
-	fmt.Printf("State: AvailablePieces=%v, SelectedPiece=%v, IsGameOver=%t, Winner=%d\n",
-		state.AvailablePieces, state.SelectedPiece, state.IsGameOver, state.Winner)
-
-	result := engine.Search(state)
-
-	fmt.Printf("Best move found: Score=%d (%v), Depth=%d, Move=%d (%d) on %d,%d give %d\n", result.Score, len(state.AvailablePieces)%2 == 0, result.Depth, result.BestMoves[0].Move.Piece, req.SelectedPiece, result.BestMoves[0].Move.Position.Row, result.BestMoves[0].Move.Position.Col, result.BestMoves[0].SelectedPiece)
-
-	bestMoveNotation := game.CreateMoveNotation(result.BestMoves[0].Move.Piece, game.CoordsToPosition(result.BestMoves[0].Move.Position.Row, result.BestMoves[0].Move.Position.Col))
-
-	var strContinuation []string
-	for _, move := range result.BestMoves {
-		notation := game.CreateMoveNotation(move.Move.Piece, game.CoordsToPosition(move.Move.Position.Row, move.Move.Position.Col))
-		strContinuation = append(strContinuation, notation)
-	}
-
-	return c.JSON(200, SolveResponse{
-		BestMove:       bestMoveNotation,
-		Score:          result.Score,
-		SuggestedPiece: int(result.BestMoves[0].SelectedPiece),
-		Continuation:   strContinuation,
-	})
-
+	return state, nil
 }
@@ -2,27 +2,30 @@ package challengeHandler
 
 import (
 	"net/http"
+	"quarto/handlers/websocketHandler"
 	"quarto/models/challenge"
 	"quarto/models/user"
+	"quarto/models/websocket"
+	"strconv"
 
 	"github.com/labstack/echo/v4"
 )
 
 // sendChallenge envoie un défi à un autre joueur
 // @Summary Send challenge
-// @Description Send a challenge to another player
+// @Description Send a challenge to another player, with optional game options (starting player, time control, rated, expiry)
 // @Tags challenges
 // @Accept json
 // @Produce json
 // @Param Quarto-Connect-Token header string true "Session token"
 // @Param request body challenge.SendChallengeRequest true "Send challenge request"
 // @Success 201 {object} challenge.Challenge
-// @Failure 400 {object} map[string]string
+// @Failure 400 {object} apperror.Response
 // @Router /challenge/send [post]
 func sendChallenge(c echo.Context) error {
 	userToken, err := user.GetTokenFromRequest(c)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+		return echo.NewHTTPError(http.StatusUnauthorized, err)
 	}
 
 	var req challenge.SendChallengeRequest
@@ -30,29 +33,70 @@ func sendChallenge(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "Données invalides")
 	}
 
-	newChallenge, err := challenge.SendChallenge(userToken.User.ID, req.ChallengedID, req.Message)
+	newChallenge, err := challenge.SendChallenge(userToken.User.ID, req.ChallengedID, req.Message, req.Options)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	return c.JSON(http.StatusCreated, newChallenge.ToWeb())
 }
 
+// counterChallenge propose des options modifiées pour un défi en attente
+// @Summary Counter challenge
+// @Description Propose modified options for a pending challenge; the other player then has to accept or decline them
+// @Tags challenges
+// @Accept json
+// @Produce json
+// @Param Quarto-Connect-Token header string true "Session token"
+// @Param request body challenge.CounterChallengeRequest true "Counter-proposal"
+// @Success 200 {object} challenge.Challenge
+// @Failure 400 {object} apperror.Response
+// @Router /challenge/counter [post]
+func counterChallenge(c echo.Context) error {
+	userToken, err := user.GetTokenFromRequest(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, err)
+	}
+
+	var req challenge.CounterChallengeRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Données invalides")
+	}
+
+	updatedChallenge, err := challenge.CounterChallenge(req.ChallengeID, userToken.User.ID, req.Options)
+	if err != nil {
+		return err
+	}
+
+	// Prévenir l'autre joueur qu'il doit répondre à la contre-proposition
+	opponentID := updatedChallenge.ChallengerID
+	if opponentID == userToken.User.ID {
+		opponentID = updatedChallenge.ChallengedID
+	}
+	websocketHandler.NotifyUser(opponentID, websocket.WSMessage{
+		Type:   "challenge_countered",
+		UserID: strconv.FormatInt(userToken.User.ID, 10),
+		Data:   updatedChallenge.ToWeb(),
+	})
+
+	return c.JSON(http.StatusOK, updatedChallenge.ToWeb())
+}
+
 // respondToChallenge répond à un défi (accepter ou refuser)
 // @Summary Respond to challenge
-// @Description Accept or decline a challenge
+// @Description Accept or decline a challenge (or the opponent's counter-proposal)
 // @Tags challenges
 // @Accept json
 // @Produce json
 // @Param Quarto-Connect-Token header string true "Session token"
 // @Param request body challenge.RespondToChallengeRequest true "Response to challenge"
 // @Success 200 {object} challenge.ChallengeResponse
-// @Failure 400 {object} map[string]string
+// @Failure 400 {object} apperror.Response
 // @Router /challenge/respond [post]
 func respondToChallenge(c echo.Context) error {
 	userToken, err := user.GetTokenFromRequest(c)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+		return echo.NewHTTPError(http.StatusUnauthorized, err)
 	}
 
 	var req challenge.RespondToChallengeRequest
@@ -64,31 +108,51 @@ func respondToChallenge(c echo.Context) error {
 		// Accepter le défi
 		updatedChallenge, newGame, err := challenge.AcceptChallenge(req.ChallengeID, userToken.User.ID)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return err
 		}
 
 		response := challenge.ChallengeResponse{
 			Challenge: updatedChallenge,
 			Game:      newGame.ToWeb(),
 		}
+		notifyRematchResponse(updatedChallenge, "rematch_accepted", userToken.User.ID, response)
 
 		return c.JSON(http.StatusOK, response)
 	} else {
 		// Refuser le défi
 		updatedChallenge, err := challenge.DeclineChallenge(req.ChallengeID, userToken.User.ID)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return err
 		}
 
 		response := challenge.ChallengeResponse{
 			Challenge: updatedChallenge,
 			Game:      nil,
 		}
+		notifyRematchResponse(updatedChallenge, "rematch_declined", userToken.User.ID, response)
 
 		return c.JSON(http.StatusOK, response)
 	}
 }
 
+// notifyRematchResponse prévient les joueurs de la partie d'origine de la réponse à une revanche
+func notifyRematchResponse(respondedChallenge *challenge.Challenge, messageType string, userID int64, response challenge.ChallengeResponse) {
+	if respondedChallenge.RematchOf == "" {
+		return
+	}
+
+	hub := websocketHandler.GetGameHub(respondedChallenge.RematchOf)
+	if hub != nil {
+		message := websocket.WSMessage{
+			Type:   messageType,
+			GameID: respondedChallenge.RematchOf,
+			UserID: strconv.FormatInt(userID, 10),
+			Data:   response,
+		}
+		hub.BroadcastToGame(respondedChallenge.RematchOf, message)
+	}
+}
+
 // getMyChallenges récupère tous les défis de l'utilisateur
 // @Summary Get my challenges
 // @Description Get all challenges sent and received by the user
@@ -96,17 +160,17 @@ func respondToChallenge(c echo.Context) error {
 // @Produce json
 // @Param Quarto-Connect-Token header string true "Session token"
 // @Success 200 {object} challenge.ChallengeListResponse
-// @Failure 401 {object} map[string]string
+// @Failure 401 {object} apperror.Response
 // @Router /challenge/my [get]
 func getMyChallenges(c echo.Context) error {
 	userToken, err := user.GetTokenFromRequest(c)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+		return echo.NewHTTPError(http.StatusUnauthorized, err)
 	}
 
 	challenges, err := challenge.GetMyChallenges(userToken.User.ID)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return err
 	}
 
 	return c.JSON(http.StatusOK, challenges)
@@ -19,6 +19,36 @@ func All(prefix string) []models.Route {
 			Method:  echo.POST,
 			Handler: respondToChallenge,
 		},
+		{
+			Path:    prefix + "/counter",
+			Method:  echo.POST,
+			Handler: counterChallenge,
+		},
+		{
+			Path:    prefix + "/open",
+			Method:  echo.POST,
+			Handler: createOpenChallenge,
+		},
+		{
+			Path:    prefix + "/open",
+			Method:  echo.GET,
+			Handler: getOpenChallenges,
+		},
+		{
+			Path:    prefix + "/:id/join",
+			Method:  echo.POST,
+			Handler: joinOpenChallenge,
+		},
+		{
+			Path:    prefix + "/invite/:code",
+			Method:  echo.GET,
+			Handler: getInvite,
+		},
+		{
+			Path:    prefix + "/invite/:code/accept",
+			Method:  echo.POST,
+			Handler: acceptInvite,
+		},
 		{
 			Path:    prefix + "/my",
 			Method:  echo.GET,
@@ -0,0 +1,47 @@
+package challengeHandler
+
+import (
+	"context"
+	"quarto/handlers/websocketHandler"
+	"quarto/models/challenge"
+	"quarto/models/websocket"
+	"quarto/scheduler"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// ExpiryJob retourne la tâche planifiée qui fait expirer les défis en attente dépassés
+func ExpiryJob(interval time.Duration) scheduler.Job {
+	return scheduler.Job{
+		Name:     "challenge_expiry",
+		Interval: interval,
+		Run:      expireChallenges,
+	}
+}
+
+// expireChallenges passe les défis dépassés au statut expiré et prévient les deux joueurs
+func expireChallenges(ctx context.Context) error {
+	expired, err := challenge.CleanupExpiredChallenges()
+	if err != nil {
+		return err
+	}
+
+	for _, expiredChallenge := range expired {
+		message := websocket.WSMessage{
+			Type:   "challenge_expired",
+			UserID: "server",
+			Data:   expiredChallenge.ToWeb(),
+		}
+		websocketHandler.NotifyUser(expiredChallenge.ChallengerID, message)
+		if !expiredChallenge.IsOpen() {
+			websocketHandler.NotifyUser(expiredChallenge.ChallengedID, message)
+		}
+	}
+
+	if len(expired) > 0 {
+		log.Info("Expired pending challenges", "count", len(expired))
+	}
+
+	return nil
+}
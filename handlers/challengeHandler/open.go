@@ -0,0 +1,183 @@
+package challengeHandler
+
+import (
+	"net/http"
+	"quarto/config"
+	"quarto/handlers/websocketHandler"
+	"quarto/models/challenge"
+	"quarto/models/game"
+	"quarto/models/user"
+	"quarto/models/websocket"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// createOpenChallenge crée un défi ouvert et son lien d'invitation
+// @Summary Create open challenge
+// @Description Create a challenge without opponent, listed in the lobby (or only reachable by its invite link when invite_only is set)
+// @Tags challenges
+// @Accept json
+// @Produce json
+// @Param Quarto-Connect-Token header string true "Session token"
+// @Param request body challenge.CreateOpenChallengeRequest true "Open challenge request"
+// @Success 201 {object} challenge.OpenChallengeResponse
+// @Failure 400 {object} apperror.Response
+// @Router /challenge/open [post]
+func createOpenChallenge(c echo.Context) error {
+	userToken, err := user.GetTokenFromRequest(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, err)
+	}
+
+	var req challenge.CreateOpenChallengeRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Données invalides")
+	}
+
+	newChallenge, err := challenge.CreateOpenChallenge(userToken.User.ID, req)
+	if err != nil {
+		return err
+	}
+
+	code := challenge.NewInviteCode(newChallenge.ID, newChallenge.ExpiresAt)
+	response := challenge.OpenChallengeResponse{
+		Challenge: newChallenge,
+		Invite: challenge.Invite{
+			Code:      code,
+			URL:       config.Config.FrontURL + "/invite/" + code,
+			ExpiresAt: newChallenge.ExpiresAt,
+		},
+	}
+
+	return c.JSON(http.StatusCreated, response)
+}
+
+// getOpenChallenges liste les défis ouverts du lobby
+// @Summary Get open challenges
+// @Description Get the open challenges of the lobby that the user can join
+// @Tags challenges
+// @Produce json
+// @Param Quarto-Connect-Token header string true "Session token"
+// @Success 200 {object} []challenge.Challenge
+// @Failure 401 {object} apperror.Response
+// @Router /challenge/open [get]
+func getOpenChallenges(c echo.Context) error {
+	userToken, err := user.GetTokenFromRequest(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, err)
+	}
+
+	challenges, err := challenge.GetLobbyChallenges(userToken.User.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, challenge.ToWebList(challenges))
+}
+
+// joinOpenChallenge rejoint un défi ouvert du lobby
+// @Summary Join open challenge
+// @Description Accept an open challenge from the lobby and start the game
+// @Tags challenges
+// @Produce json
+// @Param Quarto-Connect-Token header string true "Session token"
+// @Param id path string true "Challenge ID"
+// @Success 200 {object} challenge.ChallengeResponse
+// @Failure 400 {object} apperror.Response
+// @Router /challenge/{id}/join [post]
+func joinOpenChallenge(c echo.Context) error {
+	userToken, err := user.GetTokenFromRequest(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, err)
+	}
+
+	updatedChallenge, newGame, err := challenge.JoinOpenChallenge(c.Param("id"), userToken.User.ID)
+	if err != nil {
+		return err
+	}
+
+	notifyChallengeJoined(updatedChallenge, newGame)
+
+	response := challenge.ChallengeResponse{
+		Challenge: updatedChallenge,
+		Game:      newGame.ToWeb(),
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// getInvite affiche le défi correspondant à un lien d'invitation
+// @Summary Get invite
+// @Description Get the challenge behind an invite code (no session required)
+// @Tags challenges
+// @Produce json
+// @Param code path string true "Invite code"
+// @Success 200 {object} challenge.InvitePreviewResponse
+// @Failure 400 {object} apperror.Response
+// @Router /challenge/invite/{code} [get]
+func getInvite(c echo.Context) error {
+	invitedChallenge, err := challenge.GetInvite(c.Param("code"))
+	if err != nil {
+		return err
+	}
+
+	challenger, err := user.GetUserPublicByID(invitedChallenge.ChallengerID)
+	if err != nil {
+		return err
+	}
+
+	response := challenge.InvitePreviewResponse{
+		Challenge:  invitedChallenge,
+		Challenger: *challenger,
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// acceptInvite accepte un défi via son lien d'invitation
+// @Summary Accept invite
+// @Description Accept the challenge behind an invite code and start the game. Without session, a guest account is created when the challenge allows it and its token is returned
+// @Tags challenges
+// @Produce json
+// @Param Quarto-Connect-Token header string false "Session token"
+// @Param code path string true "Invite code"
+// @Success 200 {object} challenge.AcceptInviteResponse
+// @Failure 400 {object} apperror.Response
+// @Router /challenge/invite/{code}/accept [post]
+func acceptInvite(c echo.Context) error {
+	var session *user.UserToken
+	if userToken, err := user.GetTokenFromRequest(c); err == nil {
+		session = &userToken
+	}
+
+	updatedChallenge, newGame, guestToken, err := challenge.AcceptInvite(c.Param("code"), session)
+	if err != nil {
+		return err
+	}
+
+	notifyChallengeJoined(updatedChallenge, newGame)
+
+	response := challenge.AcceptInviteResponse{
+		Challenge: updatedChallenge,
+		Game:      newGame.ToWeb(),
+	}
+	if guestToken != nil {
+		response.Token = guestToken.TokenID
+		response.User = guestToken.User.ToSelfWebDetail()
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// notifyChallengeJoined prévient l'auteur d'un défi ouvert qu'un adversaire l'a rejoint
+func notifyChallengeJoined(joinedChallenge *challenge.Challenge, newGame *game.Game) {
+	websocketHandler.NotifyUser(joinedChallenge.ChallengerID, websocket.WSMessage{
+		Type:   "challenge_joined",
+		UserID: strconv.FormatInt(joinedChallenge.ChallengedID, 10),
+		Data: challenge.ChallengeResponse{
+			Challenge: joinedChallenge,
+			Game:      newGame.ToWeb(),
+		},
+	})
+}
@@ -1,15 +1,19 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"quarto/models/apperror"
+	"strings"
 
 	"github.com/charmbracelet/log"
 	"github.com/labstack/echo/v4"
 )
 
 func OnError(err error, c echo.Context) {
-	fmtError := Error{
-		Code:    http.StatusInternalServerError,
+	status := http.StatusInternalServerError
+	fmtError := apperror.Response{
+		Code:    "internal_error",
 		Message: "Erreur interne au serveur",
 	}
 
@@ -17,17 +21,36 @@ func OnError(err error, c echo.Context) {
 
 	he, ok := err.(*echo.HTTPError)
 	if ok {
+		status = he.Code
+		fmtError.Code = statusCode(he.Code)
 		switch message := he.Message.(type) {
 		case string:
 			fmtError.Message = message
 		case error:
 			fmtError.Message = message.Error()
+			err = message
 		}
-		fmtError.Code = he.Code
+	}
 
+	// Les erreurs métier fixent leur propre statut, leur code et leur message traduit
+	var appErr *apperror.Error
+	if errors.As(err, &appErr) {
+		status = appErr.Status
+		fmtError.Code = appErr.Code
+		fmtError.Message = appErr.Message(apperror.Language(req.Header.Get("Accept-Language")))
+		fmtError.Details = appErr.Details
 	}
 
 	log.Warnf("[%s - %s] %s", req.Method, req.RequestURI, err)
 
-	_ = c.JSONPretty(fmtError.Code, fmtError, "\t")
+	_ = c.JSONPretty(status, fmtError, "\t")
+}
+
+// statusCode retourne le code générique d'une erreur sans code métier (ex: 404 -> not_found)
+func statusCode(status int) string {
+	text := http.StatusText(status)
+	if text == "" {
+		return "error"
+	}
+	return strings.ReplaceAll(strings.ToLower(text), " ", "_")
 }
@@ -0,0 +1,263 @@
+package friendHandler
+
+import (
+	"net/http"
+	"quarto/handlers/websocketHandler"
+	"quarto/models/friend"
+	"quarto/models/user"
+	"quarto/models/websocket"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// getFriends récupère la liste d'amis avec leur présence en ligne
+// @Summary Get friends
+// @Description Get the friends list of the user with online presence
+// @Tags friends
+// @Produce json
+// @Param Quarto-Connect-Token header string true "Session token"
+// @Success 200 {object} []friend.Friend
+// @Failure 401 {object} apperror.Response
+// @Router /friends [get]
+func getFriends(c echo.Context) error {
+	userToken, err := user.GetTokenFromRequest(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, err)
+	}
+
+	friends, err := friend.GetFriendList(userToken.User.ID)
+	if err != nil {
+		return err
+	}
+
+	for i := range friends {
+		friends[i].Online = websocketHandler.IsUserOnline(friends[i].User.ID)
+	}
+
+	return c.JSON(http.StatusOK, friends)
+}
+
+// getFriendRequests récupère les demandes d'ami en attente
+// @Summary Get friend requests
+// @Description Get pending friend requests sent and received by the user
+// @Tags friends
+// @Produce json
+// @Param Quarto-Connect-Token header string true "Session token"
+// @Success 200 {object} friend.FriendRequestListResponse
+// @Failure 401 {object} apperror.Response
+// @Router /friends/requests [get]
+func getFriendRequests(c echo.Context) error {
+	userToken, err := user.GetTokenFromRequest(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, err)
+	}
+
+	requests, err := friend.GetPendingFriendRequests(userToken.User.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, requests)
+}
+
+// sendFriendRequest envoie une demande d'ami
+// @Summary Send friend request
+// @Description Send a friend request to another player (accepts it if that player already sent one)
+// @Tags friends
+// @Accept json
+// @Produce json
+// @Param Quarto-Connect-Token header string true "Session token"
+// @Param request body friend.SendFriendRequestRequest true "Friend request"
+// @Success 201 {object} friend.Friendship
+// @Failure 400 {object} apperror.Response
+// @Failure 403 {object} apperror.Response
+// @Failure 404 {object} apperror.Response
+// @Failure 409 {object} apperror.Response
+// @Router /friends/request [post]
+func sendFriendRequest(c echo.Context) error {
+	userToken, err := user.GetTokenFromRequest(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, err)
+	}
+
+	var req friend.SendFriendRequestRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Données invalides")
+	}
+
+	friendship, err := friend.SendFriendRequest(userToken.User.ID, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	messageType := "friend_request_received"
+	if friendship.Status == friend.StatusAccepted {
+		messageType = "friend_request_accepted"
+	}
+	websocketHandler.NotifyUser(req.UserID, websocket.WSMessage{
+		Type:   messageType,
+		UserID: strconv.FormatInt(userToken.User.ID, 10),
+		Data:   friendship,
+	})
+
+	return c.JSON(http.StatusCreated, friendship)
+}
+
+// respondToFriendRequest répond à une demande d'ami (accepter ou refuser)
+// @Summary Respond to friend request
+// @Description Accept or decline a received friend request
+// @Tags friends
+// @Accept json
+// @Produce json
+// @Param Quarto-Connect-Token header string true "Session token"
+// @Param request body friend.RespondToFriendRequestRequest true "Response to friend request"
+// @Success 200 {object} friend.Friendship
+// @Failure 400 {object} apperror.Response
+// @Failure 403 {object} apperror.Response
+// @Failure 404 {object} apperror.Response
+// @Failure 409 {object} apperror.Response
+// @Router /friends/respond [post]
+func respondToFriendRequest(c echo.Context) error {
+	userToken, err := user.GetTokenFromRequest(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, err)
+	}
+
+	var req friend.RespondToFriendRequestRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Données invalides")
+	}
+
+	if !req.Accept {
+		friendship, err := friend.DeclineFriendRequest(req.RequestID, userToken.User.ID)
+		if err != nil {
+			return err
+		}
+		return c.JSON(http.StatusOK, friendship)
+	}
+
+	friendship, err := friend.AcceptFriendRequest(req.RequestID, userToken.User.ID)
+	if err != nil {
+		return err
+	}
+
+	websocketHandler.NotifyUser(friendship.RequesterID, websocket.WSMessage{
+		Type:   "friend_request_accepted",
+		UserID: strconv.FormatInt(userToken.User.ID, 10),
+		Data:   friendship,
+	})
+
+	return c.JSON(http.StatusOK, friendship)
+}
+
+// removeFriend supprime un ami
+// @Summary Remove friend
+// @Description Remove a friend or cancel a sent friend request
+// @Tags friends
+// @Param Quarto-Connect-Token header string true "Session token"
+// @Param id path int true "Friend user ID"
+// @Success 204
+// @Failure 400 {object} apperror.Response
+// @Failure 404 {object} apperror.Response
+// @Router /friends/{id} [delete]
+func removeFriend(c echo.Context) error {
+	userToken, err := user.GetTokenFromRequest(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, err)
+	}
+
+	friendID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "ID utilisateur invalide")
+	}
+
+	err = friend.RemoveFriend(userToken.User.ID, friendID)
+	if err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// getBlockedUsers récupère la liste des joueurs bloqués
+// @Summary Get blocked users
+// @Description Get the players blocked by the user
+// @Tags friends
+// @Produce json
+// @Param Quarto-Connect-Token header string true "Session token"
+// @Success 200 {object} []friend.BlockedUser
+// @Failure 401 {object} apperror.Response
+// @Router /friends/blocked [get]
+func getBlockedUsers(c echo.Context) error {
+	userToken, err := user.GetTokenFromRequest(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, err)
+	}
+
+	blocked, err := friend.GetBlockedUsers(userToken.User.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, blocked)
+}
+
+// blockUser bloque un joueur
+// @Summary Block user
+// @Description Block a player: removes the friendship and prevents challenges, spectating and messages
+// @Tags friends
+// @Accept json
+// @Param Quarto-Connect-Token header string true "Session token"
+// @Param request body friend.BlockUserRequest true "User to block"
+// @Success 204
+// @Failure 400 {object} apperror.Response
+// @Failure 404 {object} apperror.Response
+// @Router /friends/block [post]
+func blockUser(c echo.Context) error {
+	userToken, err := user.GetTokenFromRequest(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, err)
+	}
+
+	var req friend.BlockUserRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Données invalides")
+	}
+
+	err = friend.BlockUser(userToken.User.ID, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// unblockUser débloque un joueur
+// @Summary Unblock user
+// @Description Unblock a previously blocked player
+// @Tags friends
+// @Param Quarto-Connect-Token header string true "Session token"
+// @Param id path int true "Blocked user ID"
+// @Success 204
+// @Failure 400 {object} apperror.Response
+// @Failure 404 {object} apperror.Response
+// @Router /friends/block/{id} [delete]
+func unblockUser(c echo.Context) error {
+	userToken, err := user.GetTokenFromRequest(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, err)
+	}
+
+	blockedID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "ID utilisateur invalide")
+	}
+
+	err = friend.UnblockUser(userToken.User.ID, blockedID)
+	if err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
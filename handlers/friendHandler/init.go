@@ -0,0 +1,52 @@
+package friendHandler
+
+import (
+	"quarto/models"
+
+	"github.com/labstack/echo/v4"
+)
+
+func All(prefix string) []models.Route {
+	return []models.Route{
+		{
+			Path:    prefix,
+			Method:  echo.GET,
+			Handler: getFriends,
+		},
+		{
+			Path:    prefix + "/requests",
+			Method:  echo.GET,
+			Handler: getFriendRequests,
+		},
+		{
+			Path:    prefix + "/request",
+			Method:  echo.POST,
+			Handler: sendFriendRequest,
+		},
+		{
+			Path:    prefix + "/respond",
+			Method:  echo.POST,
+			Handler: respondToFriendRequest,
+		},
+		{
+			Path:    prefix + "/:id",
+			Method:  echo.DELETE,
+			Handler: removeFriend,
+		},
+		{
+			Path:    prefix + "/blocked",
+			Method:  echo.GET,
+			Handler: getBlockedUsers,
+		},
+		{
+			Path:    prefix + "/block",
+			Method:  echo.POST,
+			Handler: blockUser,
+		},
+		{
+			Path:    prefix + "/block/:id",
+			Method:  echo.DELETE,
+			Handler: unblockUser,
+		},
+	}
+}
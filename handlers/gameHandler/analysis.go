@@ -0,0 +1,50 @@
+package gameHandler
+
+import (
+	"net/http"
+	"quarto/models/ai"
+	"quarto/models/game"
+	"quarto/models/user"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// GetAnalysis analyse la position courante d'une partie
+// @Summary Get game analysis
+// @Description List the lines one piece away from a Quarto, the poisoned and safe pieces, and optionally an engine hint for the player to move. During the game, analysis requires hints to be enabled and each engine hint uses one of the player's hints; both are unlimited once the game is over
+// @Tags games
+// @Produce json
+// @Param Quarto-Connect-Token header string true "Session token"
+// @Param id path string true "Game ID"
+// @Param hint query bool false "Include an engine hint"
+// @Success 200 {object} game.AnalysisResponse
+// @Failure 403 {object} apperror.Response
+// @Failure 409 {object} apperror.Response
+// @Router /game/{id}/analysis [get]
+func getAnalysis(c echo.Context) error {
+	userToken, err := user.GetTokenFromRequest(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, err)
+	}
+
+	g, err := game.GetGame(c.Param("id"), userToken.User.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, err)
+	}
+
+	if err := g.CanAnalyze(); err != nil {
+		return echo.NewHTTPError(http.StatusForbidden, err)
+	}
+
+	analysis := g.Analyze()
+	if withHint, _ := strconv.ParseBool(c.QueryParam("hint")); withHint {
+		if err := g.UseHint(userToken.User.ID); err != nil {
+			return echo.NewHTTPError(http.StatusConflict, err)
+		}
+		analysis.SetHint(ai.Hint(g, ai.HintDepth))
+	}
+	analysis.HintsRemaining = g.HintsRemaining(userToken.User.ID)
+
+	return c.JSON(http.StatusOK, analysis)
+}
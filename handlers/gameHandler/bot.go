@@ -0,0 +1,130 @@
+package gameHandler
+
+import (
+	"context"
+	"net/http"
+	"quarto/handlers/websocketHandler"
+	"quarto/models/ai"
+	"quarto/models/game"
+	"quarto/models/user"
+	"quarto/models/websocket"
+	"strconv"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/labstack/echo/v4"
+)
+
+// CreateBotGame crée une partie contre le bot du serveur
+// @Summary Create bot game
+// @Description Start an unrated, untimed game against the server bot. Its moves are searched by the chosen engine (minimax or mcts) within the thinking time, and broadcast over the game WebSocket. Draw offers and takebacks are refused in bot games
+// @Tags games
+// @Accept json
+// @Produce json
+// @Param Quarto-Connect-Token header string true "Session token"
+// @Param request body game.CreateBotGameRequest true "Bot game request"
+// @Success 201 {object} game.Game
+// @Failure 422 {object} apperror.Response
+// @Failure 503 {object} apperror.Response
+// @Router /game/bot [post]
+func createBotGame(c echo.Context) error {
+	userToken, err := user.GetTokenFromRequest(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, err)
+	}
+
+	var req game.CreateBotGameRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Données invalides")
+	}
+
+	if req.TimeLimitMs < 0 || time.Duration(req.TimeLimitMs)*time.Millisecond > ai.MaxTimeLimit {
+		return game.ErrBotTimeLimit.With("ms", req.TimeLimitMs).With("max", ai.MaxTimeLimit.Milliseconds())
+	}
+	if req.Engine == "" {
+		req.Engine = ai.SearcherMinimax
+	}
+	options := game.BotOptions{Engine: req.Engine, TimeLimitMs: req.TimeLimitMs}
+	if _, err := ai.NewBotSearcher(options); err != nil {
+		return game.ErrBotEngine.With("engine", req.Engine)
+	}
+
+	bot, err := user.GetBotAccount()
+	if err != nil {
+		return game.ErrBotUnavailable.Wrap(err)
+	}
+	options.PlayerID = bot.ID
+
+	player1ID, player2ID, err := req.Players(userToken.User.ID, bot.ID)
+	if err != nil {
+		return err
+	}
+
+	g, err := game.CreateNewGame(player1ID, player2ID, game.GameOptions{Variant: req.Variant, Bot: &options})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	// Le bot qui commence donne sa première pièce avant la réponse
+	if g.IsBotTurn() {
+		playBotTurn(&g)
+	}
+
+	return c.JSON(http.StatusCreated, g.ToWeb())
+}
+
+// playBotTurn cherche et joue le coup du bot au trait, puis le diffuse aux joueurs de la partie
+func playBotTurn(g *game.Game) {
+	botID := g.Options.Bot.PlayerID
+	move, err := ai.FindBotMove(context.Background(), *g)
+	if err != nil {
+		log.Error("Bot move search failed", "game", g.ID, "error", err)
+		return
+	}
+
+	// La partie a pu changer pendant la recherche (abandon) : le coup est joué sur son état enregistré
+	current, err := game.GetGame(g.ID, botID)
+	if err != nil {
+		log.Error("Bot game reload failed", "game", g.ID, "error", err)
+		return
+	}
+	if !current.IsBotTurn() || current.GamePhase != g.GamePhase || current.SelectedPiece != g.SelectedPiece {
+		return
+	}
+	*g = current
+
+	if move.Place {
+		if err := g.PlacePiece(botID, move.Position); err != nil {
+			log.Error("Bot placement failed", "game", g.ID, "error", err)
+			return
+		}
+		messageType := "piece_placed"
+		if g.Status == game.StatusFinished {
+			messageType = "game_finished"
+		}
+		broadcastBotMove(*g, messageType)
+		if g.Status == game.StatusFinished {
+			return
+		}
+	}
+
+	if err := g.SelectPiece(botID, move.Piece); err != nil {
+		log.Error("Bot selection failed", "game", g.ID, "error", err)
+		return
+	}
+	broadcastBotMove(*g, "piece_selected")
+}
+
+// broadcastBotMove prévient les joueurs d'un coup du bot
+func broadcastBotMove(g game.Game, messageType string) {
+	hub := websocketHandler.GetGameHub(g.ID)
+	if hub == nil {
+		return
+	}
+	hub.BroadcastToGame(g.ID, websocket.WSMessage{
+		Type:   messageType,
+		GameID: g.ID,
+		UserID: strconv.FormatInt(g.Options.Bot.PlayerID, 10),
+		Data:   g.ToWeb(),
+	})
+}
@@ -1,8 +1,10 @@
 package gameHandler
 
 import (
+	"errors"
 	"net/http"
 	"quarto/handlers/websocketHandler"
+	"quarto/models/apperror"
 	"quarto/models/game"
 	"quarto/models/user"
 	"quarto/models/websocket"
@@ -19,18 +21,18 @@ import (
 // @Param Quarto-Connect-Token header string true "Session token"
 // @Param id path string true "Game ID"
 // @Success 200 {object} game.Game
-// @Failure 404 {object} map[string]string
+// @Failure 404 {object} apperror.Response
 // @Router /game/{id} [get]
 func getGame(c echo.Context) error {
 	userToken, err := user.GetTokenFromRequest(c)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+		return echo.NewHTTPError(http.StatusUnauthorized, err)
 	}
 
 	gameID := c.Param("id")
 	g, err := game.GetGame(gameID, userToken.User.ID)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		return echo.NewHTTPError(http.StatusNotFound, err)
 	}
 
 	return c.JSON(http.StatusOK, g.ToWeb())
@@ -38,20 +40,21 @@ func getGame(c echo.Context) error {
 
 // SelectPiece sélectionne une pièce pour le prochain coup
 // @Summary Select piece
-// @Description Select a piece for the next move
+// @Description Select a piece for the next move. With dry_run, only report whether the selection is legal
 // @Tags games
 // @Accept json
 // @Produce json
 // @Param Quarto-Connect-Token header string true "Session token"
 // @Param id path string true "Game ID"
+// @Param dry_run query bool false "Check the move without playing it (returns a game.MoveCheck)"
 // @Param request body game.SelectPieceRequest true "Select piece request"
 // @Success 200 {object} game.Game
-// @Failure 400 {object} map[string]string
+// @Failure 400 {object} apperror.Response
 // @Router /game/{id}/select-piece [post]
 func selectPiece(c echo.Context) error {
 	userToken, err := user.GetTokenFromRequest(c)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+		return echo.NewHTTPError(http.StatusUnauthorized, err)
 	}
 
 	gameID := c.Param("id")
@@ -62,12 +65,20 @@ func selectPiece(c echo.Context) error {
 
 	g, err := game.GetGame(gameID, userToken.User.ID)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		return echo.NewHTTPError(http.StatusNotFound, err)
 	}
 
-	err = g.SelectPiece(req.PieceID)
+	if isDryRun(c) {
+		check := g.CheckSelection(userToken.User.ID, req.PieceID)
+		return c.JSON(http.StatusOK, check.Localized(apperror.Language(c.Request().Header.Get("Accept-Language"))))
+	}
+
+	err = g.SelectPiece(userToken.User.ID, req.PieceID)
+	if errors.Is(err, game.ErrTimeExpired) {
+		notifyTimeout(g)
+	}
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		return echo.NewHTTPError(http.StatusBadRequest, err)
 	}
 
 	// Notifier tous les joueurs de la partie via WebSocket
@@ -82,25 +93,62 @@ func selectPiece(c echo.Context) error {
 		hub.BroadcastToGame(gameID, message)
 	}
 
+	// Dans une partie contre un bot, le serveur répond par le coup du bot
+	if g.IsBotTurn() {
+		botGame := g
+		go playBotTurn(&botGame)
+	}
+
 	return c.JSON(http.StatusOK, g.ToWeb())
 }
 
+// GetLegalMoves liste les coups légaux de la phase en cours
+// @Summary Get legal moves
+// @Description List the pieces that can be given (selection phase) or the squares where the selected piece can be placed (placement phase)
+// @Tags games
+// @Produce json
+// @Param Quarto-Connect-Token header string true "Session token"
+// @Param id path string true "Game ID"
+// @Success 200 {object} game.LegalMovesResponse
+// @Failure 404 {object} apperror.Response
+// @Router /game/{id}/legal-moves [get]
+func getLegalMoves(c echo.Context) error {
+	userToken, err := user.GetTokenFromRequest(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, err)
+	}
+
+	g, err := game.GetGame(c.Param("id"), userToken.User.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, err)
+	}
+
+	return c.JSON(http.StatusOK, g.LegalMoves())
+}
+
+// isDryRun indique si le coup doit seulement être vérifié
+func isDryRun(c echo.Context) bool {
+	dryRun, _ := strconv.ParseBool(c.QueryParam("dry_run"))
+	return dryRun
+}
+
 // PlacePiece place une pièce sur le plateau
 // @Summary Place piece
-// @Description Place a piece on the board
+// @Description Place a piece on the board. With dry_run, only report whether the placement is legal and whether it would win
 // @Tags games
 // @Accept json
 // @Produce json
 // @Param Quarto-Connect-Token header string true "Session token"
 // @Param id path string true "Game ID"
+// @Param dry_run query bool false "Check the move without playing it (returns a game.MoveCheck)"
 // @Param request body game.PlacePieceRequest true "Place piece request"
 // @Success 200 {object} game.Game
-// @Failure 400 {object} map[string]string
+// @Failure 400 {object} apperror.Response
 // @Router /game/{id}/place-piece [post]
 func placePiece(c echo.Context) error {
 	userToken, err := user.GetTokenFromRequest(c)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+		return echo.NewHTTPError(http.StatusUnauthorized, err)
 	}
 
 	gameID := c.Param("id")
@@ -111,17 +159,25 @@ func placePiece(c echo.Context) error {
 
 	g, err := game.GetGame(gameID, userToken.User.ID)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		return echo.NewHTTPError(http.StatusNotFound, err)
 	}
 
 	row, col, err := game.PositionToCoords(req.Position)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		return err
+	}
+
+	if isDryRun(c) {
+		check := g.CheckPlacement(userToken.User.ID, game.Position{Row: row, Col: col})
+		return c.JSON(http.StatusOK, check.Localized(apperror.Language(c.Request().Header.Get("Accept-Language"))))
 	}
 
-	err = g.PlacePiece(game.Position{Row: row, Col: col})
+	err = g.PlacePiece(userToken.User.ID, game.Position{Row: row, Col: col})
+	if errors.Is(err, game.ErrTimeExpired) {
+		notifyTimeout(g)
+	}
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		return echo.NewHTTPError(http.StatusBadRequest, err)
 	}
 
 	// Notifier tous les joueurs de la partie via WebSocket
@@ -151,23 +207,23 @@ func placePiece(c echo.Context) error {
 // @Param Quarto-Connect-Token header string true "Session token"
 // @Param id path string true "Game ID"
 // @Success 200 {object} game.Game
-// @Failure 400 {object} map[string]string
+// @Failure 400 {object} apperror.Response
 // @Router /game/{id}/forfeit [post]
 func forfeitGame(c echo.Context) error {
 	userToken, err := user.GetTokenFromRequest(c)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+		return echo.NewHTTPError(http.StatusUnauthorized, err)
 	}
 
 	gameID := c.Param("id")
 	g, err := game.GetGame(gameID, userToken.User.ID)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		return echo.NewHTTPError(http.StatusNotFound, err)
 	}
 
 	err = g.ForfeitGame(userToken.User.ID)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		return echo.NewHTTPError(http.StatusBadRequest, err)
 	}
 
 	// Notifier tous les joueurs de la partie via WebSocket
@@ -185,6 +241,58 @@ func forfeitGame(c echo.Context) error {
 	return c.JSON(http.StatusOK, g.ToWeb())
 }
 
+// GameAction effectue une action de partie (nulle, annulation de coup, annonce de Quarto)
+// @Summary Game action
+// @Description Offer, accept or decline a draw, request, accept or decline a takeback of the last placement, or claim a Quarto (call Quarto games)
+// @Tags games
+// @Accept json
+// @Produce json
+// @Param Quarto-Connect-Token header string true "Session token"
+// @Param id path string true "Game ID"
+// @Param request body game.GameActionRequest true "Game action request"
+// @Success 200 {object} game.Game
+// @Failure 400 {object} apperror.Response
+// @Router /game/{id}/action [post]
+func gameAction(c echo.Context) error {
+	userToken, err := user.GetTokenFromRequest(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, err)
+	}
+
+	gameID := c.Param("id")
+	var req game.GameActionRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Données invalides")
+	}
+
+	g, err := game.GetGame(gameID, userToken.User.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, err)
+	}
+
+	event, err := g.PerformAction(userToken.User.ID, req.Action)
+	if errors.Is(err, game.ErrTimeExpired) {
+		notifyTimeout(g)
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	// Notifier tous les joueurs de la partie via WebSocket
+	hub := websocketHandler.GetGameHub(gameID)
+	if hub != nil {
+		message := websocket.WSMessage{
+			Type:   event,
+			GameID: gameID,
+			UserID: strconv.FormatInt(userToken.User.ID, 10),
+			Data:   g.ToWeb(),
+		}
+		hub.BroadcastToGame(gameID, message)
+	}
+
+	return c.JSON(http.StatusOK, g.ToWeb())
+}
+
 // GetMyGames récupère toutes les parties de l'utilisateur
 // @Summary Get my games
 // @Description Get all games for the current user
@@ -193,12 +301,12 @@ func forfeitGame(c echo.Context) error {
 // @Param Quarto-Connect-Token header string true "Session token"
 // @Param status query string false "Game status filter (active, finished)"
 // @Success 200 {object} []game.Game
-// @Failure 401 {object} map[string]string
+// @Failure 401 {object} apperror.Response
 // @Router /game/my [get]
 func getMyGames(c echo.Context) error {
 	userToken, err := user.GetTokenFromRequest(c)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+		return echo.NewHTTPError(http.StatusUnauthorized, err)
 	}
 
 	status := c.QueryParam("status")
@@ -211,7 +319,7 @@ func getMyGames(c echo.Context) error {
 	}
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
 	}
 
 	// Convertir en format web
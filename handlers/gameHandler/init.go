@@ -23,11 +23,61 @@ func All(prefix string) []models.Route {
 			Method:  echo.POST,
 			Handler: placePiece,
 		},
+		{
+			Path:    prefix + "/:id/legal-moves",
+			Method:  echo.GET,
+			Handler: getLegalMoves,
+		},
+		{
+			Path:    prefix + "/:id/analysis",
+			Method:  echo.GET,
+			Handler: getAnalysis,
+		},
 		{
 			Path:    prefix + "/:id/forfeit",
 			Method:  echo.POST,
 			Handler: forfeitGame,
 		},
+		{
+			Path:    prefix + "/:id/action",
+			Method:  echo.POST,
+			Handler: gameAction,
+		},
+		{
+			Path:    prefix + "/:id/replay",
+			Method:  echo.GET,
+			Handler: getReplay,
+		},
+		{
+			Path:    prefix + "/:id/position",
+			Method:  echo.GET,
+			Handler: getPosition,
+		},
+		{
+			Path:    prefix + "/:id/export",
+			Method:  echo.GET,
+			Handler: exportGame,
+		},
+		{
+			Path:    prefix + "/:id/rematch",
+			Method:  echo.POST,
+			Handler: offerRematch,
+		},
+		{
+			Path:    prefix + "/:id/rematch/accept",
+			Method:  echo.POST,
+			Handler: acceptRematch,
+		},
+		{
+			Path:    prefix + "/:id/series",
+			Method:  echo.GET,
+			Handler: getSeries,
+		},
+		{
+			Path:    prefix + "/bot",
+			Method:  echo.POST,
+			Handler: createBotGame,
+		},
 		{
 			Path:    prefix + "/my",
 			Method:  echo.GET,
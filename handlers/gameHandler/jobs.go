@@ -0,0 +1,64 @@
+package gameHandler
+
+import (
+	"context"
+	"quarto/handlers/websocketHandler"
+	"quarto/models/game"
+	"quarto/models/websocket"
+	"quarto/scheduler"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// ClockJob retourne la tâche planifiée qui fait perdre au temps les joueurs ayant épuisé leur pendule
+func ClockJob(interval time.Duration) scheduler.Job {
+	return scheduler.Job{
+		Name:     "game_clock",
+		Interval: interval,
+		Run:      flagTimeouts,
+	}
+}
+
+// flagTimeouts termine les parties dont le joueur au trait a épuisé son temps et prévient les joueurs
+func flagTimeouts(ctx context.Context) error {
+	games, err := game.GetTimedActiveGames()
+	if err != nil {
+		return err
+	}
+
+	flagged := 0
+	for _, g := range games {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !g.FlagTimeout(time.Now()) {
+			continue
+		}
+		if err := game.UpdateGame(g); err != nil {
+			return err
+		}
+		notifyTimeout(g)
+		flagged++
+	}
+
+	if flagged > 0 {
+		log.Info("Flagged games lost on time", "count", flagged)
+	}
+
+	return nil
+}
+
+// notifyTimeout prévient les joueurs d'une partie perdue au temps
+func notifyTimeout(g game.Game) {
+	hub := websocketHandler.GetGameHub(g.ID)
+	if hub == nil {
+		return
+	}
+	hub.BroadcastToGame(g.ID, websocket.WSMessage{
+		Type:   "game_timeout",
+		GameID: g.ID,
+		UserID: "server",
+		Data:   g.ToWeb(),
+	})
+}
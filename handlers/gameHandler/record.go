@@ -0,0 +1,46 @@
+package gameHandler
+
+import (
+	"fmt"
+	"net/http"
+	"quarto/models/game"
+	"quarto/models/user"
+
+	"github.com/labstack/echo/v4"
+)
+
+// exportGame exporte une partie au format d'enregistrement portable
+// @Summary Export game
+// @Description Export a game as a portable text record (PGN-like header tags followed by the move list with piece hand-offs)
+// @Tags games
+// @Produce plain
+// @Param Quarto-Connect-Token header string true "Session token"
+// @Param id path string true "Game ID"
+// @Success 200 {string} string "Game record"
+// @Failure 404 {object} apperror.Response
+// @Router /game/{id}/export [get]
+func exportGame(c echo.Context) error {
+	userToken, err := user.GetTokenFromRequest(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, err)
+	}
+
+	g, err := game.GetGame(c.Param("id"), userToken.User.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, err)
+	}
+
+	record := game.NewRecord(g, playerName(g.Player1ID), playerName(g.Player2ID))
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=\"quarto-%s.qgn\"", g.ID))
+	return c.String(http.StatusOK, record.Format())
+}
+
+// playerName retourne le pseudo d'un joueur, ou "?" s'il est introuvable (compte supprimé)
+func playerName(userID int64) string {
+	player, err := user.GetUserPublicByID(userID)
+	if err != nil {
+		return "?"
+	}
+	return player.Username
+}
@@ -0,0 +1,123 @@
+package gameHandler
+
+import (
+	"net/http"
+	"quarto/handlers/websocketHandler"
+	"quarto/models/challenge"
+	"quarto/models/game"
+	"quarto/models/user"
+	"quarto/models/websocket"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// offerRematch propose une revanche après une partie terminée
+// @Summary Offer rematch
+// @Description Offer a rematch (colours swapped) after a finished game. If the opponent already offered one, it is accepted and the new game is returned
+// @Tags games
+// @Produce json
+// @Param Quarto-Connect-Token header string true "Session token"
+// @Param id path string true "Game ID"
+// @Success 200 {object} challenge.ChallengeResponse
+// @Failure 400 {object} apperror.Response
+// @Router /game/{id}/rematch [post]
+func offerRematch(c echo.Context) error {
+	userToken, err := user.GetTokenFromRequest(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, err)
+	}
+
+	gameID := c.Param("id")
+	rematch, newGame, err := challenge.OfferRematch(gameID, userToken.User.ID)
+	if err != nil {
+		return err
+	}
+
+	response := challenge.ChallengeResponse{Challenge: rematch}
+	messageType := "rematch_offered"
+	if newGame != nil {
+		response.Game = newGame.ToWeb()
+		messageType = "rematch_accepted"
+	}
+
+	// Notifier tous les joueurs de la partie via WebSocket
+	hub := websocketHandler.GetGameHub(gameID)
+	if hub != nil {
+		message := websocket.WSMessage{
+			Type:   messageType,
+			GameID: gameID,
+			UserID: strconv.FormatInt(userToken.User.ID, 10),
+			Data:   response,
+		}
+		hub.BroadcastToGame(gameID, message)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// acceptRematch accepte la revanche proposée par l'adversaire
+// @Summary Accept rematch
+// @Description Accept the rematch offered by the opponent and start the new game
+// @Tags games
+// @Produce json
+// @Param Quarto-Connect-Token header string true "Session token"
+// @Param id path string true "Game ID"
+// @Success 200 {object} challenge.ChallengeResponse
+// @Failure 400 {object} apperror.Response
+// @Router /game/{id}/rematch/accept [post]
+func acceptRematch(c echo.Context) error {
+	userToken, err := user.GetTokenFromRequest(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, err)
+	}
+
+	gameID := c.Param("id")
+	rematch, newGame, err := challenge.AcceptRematch(gameID, userToken.User.ID)
+	if err != nil {
+		return err
+	}
+
+	response := challenge.ChallengeResponse{
+		Challenge: rematch,
+		Game:      newGame.ToWeb(),
+	}
+
+	// Notifier tous les joueurs de la partie via WebSocket
+	hub := websocketHandler.GetGameHub(gameID)
+	if hub != nil {
+		message := websocket.WSMessage{
+			Type:   "rematch_accepted",
+			GameID: gameID,
+			UserID: strconv.FormatInt(userToken.User.ID, 10),
+			Data:   response,
+		}
+		hub.BroadcastToGame(gameID, message)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// getSeries récupère la série de revanches d'une partie
+// @Summary Get series
+// @Description Get the series of consecutive rematches a game belongs to, with the running score
+// @Tags games
+// @Produce json
+// @Param Quarto-Connect-Token header string true "Session token"
+// @Param id path string true "Game ID"
+// @Success 200 {object} game.Series
+// @Failure 404 {object} apperror.Response
+// @Router /game/{id}/series [get]
+func getSeries(c echo.Context) error {
+	userToken, err := user.GetTokenFromRequest(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, err)
+	}
+
+	series, err := game.GetSeries(c.Param("id"), userToken.User.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, err)
+	}
+
+	return c.JSON(http.StatusOK, series)
+}
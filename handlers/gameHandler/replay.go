@@ -0,0 +1,80 @@
+package gameHandler
+
+import (
+	"net/http"
+	"quarto/models/game"
+	"quarto/models/user"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// getReplay récupère le déroulé complet d'une partie
+// @Summary Get replay
+// @Description Get the position (board, available pieces, selected piece, phase, notation) after every ply of a game
+// @Tags games
+// @Produce json
+// @Param Quarto-Connect-Token header string true "Session token"
+// @Param id path string true "Game ID"
+// @Success 200 {object} game.ReplayResponse
+// @Failure 404 {object} apperror.Response
+// @Failure 422 {object} apperror.Response "Corrupt history, the message reports the first illegal ply"
+// @Router /game/{id}/replay [get]
+func getReplay(c echo.Context) error {
+	userToken, err := user.GetTokenFromRequest(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, err)
+	}
+
+	g, err := game.GetGame(c.Param("id"), userToken.User.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, err)
+	}
+
+	plies, err := game.Replay(g.Player1ID, g.Player2ID, g.Options, g.History)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, err)
+	}
+
+	return c.JSON(http.StatusOK, game.ReplayResponse{GameID: g.ID, Plies: plies})
+}
+
+// getPosition récupère la position d'une partie après un demi-coup
+// @Summary Get position
+// @Description Get the position of a game after the given ply (0 = initial position, default = current position)
+// @Tags games
+// @Produce json
+// @Param Quarto-Connect-Token header string true "Session token"
+// @Param id path string true "Game ID"
+// @Param ply query int false "Ply number"
+// @Success 200 {object} game.Ply
+// @Failure 400 {object} apperror.Response
+// @Failure 404 {object} apperror.Response
+// @Failure 422 {object} apperror.Response "Corrupt history, the message reports the first illegal ply"
+// @Router /game/{id}/position [get]
+func getPosition(c echo.Context) error {
+	userToken, err := user.GetTokenFromRequest(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, err)
+	}
+
+	g, err := game.GetGame(c.Param("id"), userToken.User.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, err)
+	}
+
+	ply := len(g.History)
+	if param := c.QueryParam("ply"); param != "" {
+		ply, err = strconv.Atoi(param)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Demi-coup invalide")
+		}
+	}
+
+	position, err := game.PositionAt(g, ply)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	return c.JSON(http.StatusOK, position)
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"quarto/config"
 	"quarto/models"
+	"quarto/scheduler"
 
 	"github.com/labstack/echo/v4"
 )
@@ -13,5 +14,6 @@ func health(c echo.Context) error {
 	return c.JSON(http.StatusOK, models.HealthModel{
 		Status:  "Healthy !",
 		Version: config.Version,
+		Jobs:    scheduler.Status(),
 	})
 }
@@ -4,7 +4,9 @@ import (
 	"quarto/handlers/aiHandler"
 	"quarto/handlers/authHandler"
 	"quarto/handlers/challengeHandler"
+	"quarto/handlers/friendHandler"
 	"quarto/handlers/gameHandler"
+	"quarto/handlers/recordHandler"
 	"quarto/handlers/userHandler"
 	"quarto/handlers/websocketHandler"
 	"quarto/models"
@@ -21,8 +23,10 @@ func All() (routes []models.Route) {
 
 	routes = append(routes, authHandler.All("/auth")...)
 	routes = append(routes, gameHandler.All("/game")...)
+	routes = append(routes, recordHandler.All("/games")...)
 	routes = append(routes, challengeHandler.All("/challenge")...)
 	routes = append(routes, userHandler.All("/users")...)
+	routes = append(routes, friendHandler.All("/friends")...)
 	routes = append(routes, aiHandler.All("/ai")...)
 	routes = append(routes, websocketHandler.All()...)
 
@@ -0,0 +1,16 @@
+package handlers
+
+import (
+	"quarto/config"
+	"quarto/handlers/challengeHandler"
+	"quarto/handlers/gameHandler"
+	"quarto/scheduler"
+)
+
+// Jobs retourne les tâches périodiques du serveur
+func Jobs() (jobs []scheduler.Job) {
+	jobs = append(jobs, challengeHandler.ExpiryJob(config.Config.ChallengeExpiryInterval))
+	jobs = append(jobs, gameHandler.ClockJob(config.Config.ClockCheckInterval))
+
+	return
+}
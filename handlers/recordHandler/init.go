@@ -0,0 +1,17 @@
+package recordHandler
+
+import (
+	"quarto/models"
+
+	"github.com/labstack/echo/v4"
+)
+
+func All(prefix string) []models.Route {
+	return []models.Route{
+		{
+			Path:    prefix + "/import",
+			Method:  echo.POST,
+			Handler: importRecord,
+		},
+	}
+}
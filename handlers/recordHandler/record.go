@@ -0,0 +1,51 @@
+package recordHandler
+
+import (
+	"io"
+	"net/http"
+	"quarto/models/game"
+	"quarto/models/user"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Taille maximale d'un enregistrement importé
+const maxRecordSize = 64 << 10
+
+// importRecord importe un enregistrement de partie pour analyse
+// @Summary Import game record
+// @Description Parse a portable game record and replay it for analysis, without creating a game. Players are replayed as 1 (first to select) and 2.
+// @Tags games
+// @Accept plain
+// @Produce json
+// @Param Quarto-Connect-Token header string true "Session token"
+// @Param record body string true "Game record"
+// @Success 200 {object} game.ImportedRecord
+// @Failure 400 {object} apperror.Response
+// @Failure 422 {object} apperror.Response "Illegal move, the message reports the first illegal ply"
+// @Router /games/import [post]
+func importRecord(c echo.Context) error {
+	if _, err := user.GetTokenFromRequest(c); err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, err)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request().Body, maxRecordSize+1))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Données invalides")
+	}
+	if len(body) > maxRecordSize {
+		return echo.NewHTTPError(http.StatusRequestEntityTooLarge, "Enregistrement trop volumineux")
+	}
+
+	record, err := game.ParseRecord(string(body))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	imported, err := game.ImportRecord(record)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, err)
+	}
+
+	return c.JSON(http.StatusOK, imported)
+}
@@ -15,6 +15,18 @@ func All(prefix string) (routes []models.Route) {
 		Handler: userHandler.GetUsers,
 	})
 
+	routes = append(routes, models.Route{
+		Path:    prefix + "/me/settings",
+		Method:  echo.GET,
+		Handler: userHandler.GetSettings,
+	})
+
+	routes = append(routes, models.Route{
+		Path:    prefix + "/me/settings",
+		Method:  echo.POST,
+		Handler: userHandler.UpdateSettings,
+	})
+
 	routes = append(routes, models.Route{
 		Path:    prefix + "/:id",
 		Method:  echo.GET,
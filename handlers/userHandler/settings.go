@@ -0,0 +1,62 @@
+package userHandler
+
+import (
+	"net/http"
+	"quarto/models/user"
+
+	"github.com/labstack/echo/v4"
+)
+
+// GetSettings récupère les préférences de l'utilisateur connecté
+// @Summary Get my settings
+// @Description Get the preferences of the authenticated user
+// @Tags users
+// @Produce json
+// @Param Quarto-Connect-Token header string true "Session token"
+// @Success 200 {object} user.UserSettings
+// @Failure 401 {object} apperror.Response
+// @Router /users/me/settings [get]
+func (uh *UserHandler) GetSettings(c echo.Context) error {
+	userToken, err := user.GetTokenFromRequest(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, err)
+	}
+
+	settings, err := user.GetUserSettings(userToken.User.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, settings)
+}
+
+// UpdateSettings met à jour les préférences de l'utilisateur connecté
+// @Summary Update my settings
+// @Description Update the preferences of the authenticated user
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param Quarto-Connect-Token header string true "Session token"
+// @Param request body user.UserSettings true "New settings"
+// @Success 200 {object} user.UserSettings
+// @Failure 400 {object} apperror.Response
+// @Failure 401 {object} apperror.Response
+// @Router /users/me/settings [post]
+func (uh *UserHandler) UpdateSettings(c echo.Context) error {
+	userToken, err := user.GetTokenFromRequest(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, err)
+	}
+
+	var settings user.UserSettings
+	if err := c.Bind(&settings); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Données invalides")
+	}
+
+	err = user.UpdateUserSettings(userToken.User.ID, settings)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, settings)
+}
@@ -1,6 +1,7 @@
 package userHandler
 
 import (
+	"errors"
 	"net/http"
 	"quarto/models/user"
 	"strconv"
@@ -24,13 +25,13 @@ func NewUserHandler() *UserHandler {
 // @Param page query int false "Page number (default: 1)"
 // @Param page_size query int false "Page size (default: 20, max: 100)"
 // @Success 200 {object} user.UserPaginationResponse
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
+// @Failure 400 {object} apperror.Response
+// @Failure 401 {object} apperror.Response
 // @Router /users [get]
 func (uh *UserHandler) GetUsers(c echo.Context) error {
 	userToken, err := user.GetTokenFromRequest(c)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+		return echo.NewHTTPError(http.StatusUnauthorized, err)
 	}
 
 	// Paramètres de pagination
@@ -76,14 +77,14 @@ func (uh *UserHandler) GetUsers(c echo.Context) error {
 // @Param Quarto-Connect-Token header string true "Session token"
 // @Param id path int true "User ID"
 // @Success 200 {object} user.UserPublic
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
-// @Failure 404 {object} map[string]string
+// @Failure 400 {object} apperror.Response
+// @Failure 401 {object} apperror.Response
+// @Failure 404 {object} apperror.Response
 // @Router /users/{id} [get]
 func (uh *UserHandler) GetUser(c echo.Context) error {
 	_, err := user.GetTokenFromRequest(c)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+		return echo.NewHTTPError(http.StatusUnauthorized, err)
 	}
 
 	userIDParam := c.Param("id")
@@ -94,8 +95,8 @@ func (uh *UserHandler) GetUser(c echo.Context) error {
 
 	userData, err := user.GetUserPublicByID(userID)
 	if err != nil {
-		if err.Error() == "utilisateur non trouvé" {
-			return echo.NewHTTPError(http.StatusNotFound, "Utilisateur non trouvé")
+		if errors.Is(err, user.ErrUserNotFound) {
+			return err
 		}
 		log.Error("Erreur lors de la récupération de l'utilisateur", "error", err, "requested_user", userID)
 		return echo.NewHTTPError(http.StatusInternalServerError, "Erreur lors de la récupération de l'utilisateur")
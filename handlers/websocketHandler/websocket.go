@@ -2,6 +2,8 @@ package websocketHandler
 
 import (
 	"net/http"
+	"quarto/models/friend"
+	"quarto/models/game"
 	"quarto/models/user"
 	"quarto/models/websocket"
 	"sync"
@@ -12,6 +14,7 @@ import (
 
 type WebSocketHandler struct {
 	gameHubs map[string]*websocket.Hub
+	lobbyHub *websocket.Hub // Connexions hors partie (présence et notifications)
 	mutex    sync.RWMutex
 }
 
@@ -20,7 +23,9 @@ var gameHandler *WebSocketHandler
 func init() {
 	gameHandler = &WebSocketHandler{
 		gameHubs: make(map[string]*websocket.Hub),
+		lobbyHub: websocket.NewHub(),
 	}
+	go gameHandler.lobbyHub.Run()
 }
 
 func NewWebSocketHandler() *WebSocketHandler {
@@ -47,7 +52,7 @@ func (wsh *WebSocketHandler) GetOrCreateGameHub(gameID string) *websocket.Hub {
 // @Summary WebSocket connection
 // @Description Establish WebSocket connection for real-time communication
 // @Tags websocket
-// @Param game_id query string true "Game ID (required for game-specific communication)"
+// @Param game_id query string false "Game ID (omit to open a lobby connection for presence and notifications)"
 // @Param token query string true "Session token"
 // @Router /ws [get]
 func (wsh *WebSocketHandler) HandleWebSocket(c echo.Context) error {
@@ -70,8 +75,27 @@ func (wsh *WebSocketHandler) HandleWebSocket(c echo.Context) error {
 
 	gameID := c.QueryParam("game_id")
 
+	// Connexion au lobby : présence en ligne et notifications personnelles
 	if gameID == "" {
-		return echo.NewHTTPError(400, "game_id requis pour la connexion WebSocket")
+		return wsh.lobbyHub.HandleWebSocket(c, userToken.User.ID, "")
+	}
+
+	g, err := game.GetGameByID(gameID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, err)
+	}
+
+	// Un spectateur bloqué par l'un des joueurs ne peut pas regarder la partie
+	if g.Player1ID != userToken.User.ID && g.Player2ID != userToken.User.ID {
+		for _, playerID := range []int64{g.Player1ID, g.Player2ID} {
+			blocked, err := friend.IsBlockedBetween(userToken.User.ID, playerID)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, err)
+			}
+			if blocked {
+				return echo.NewHTTPError(http.StatusForbidden, "vous ne pouvez pas regarder cette partie")
+			}
+		}
 	}
 
 	// Connexion pour une partie spécifique
@@ -84,6 +108,28 @@ func GetGameHub(gameID string) *websocket.Hub {
 	return gameHandler.GetOrCreateGameHub(gameID)
 }
 
+// IsUserOnline indique si un utilisateur a une connexion WebSocket active (lobby ou partie)
+func IsUserOnline(userID int64) bool {
+	if gameHandler.lobbyHub.HasUser(userID) {
+		return true
+	}
+
+	gameHandler.mutex.RLock()
+	defer gameHandler.mutex.RUnlock()
+
+	for _, hub := range gameHandler.gameHubs {
+		if hub.HasUser(userID) {
+			return true
+		}
+	}
+	return false
+}
+
+// NotifyUser envoie une notification personnelle à un utilisateur connecté au lobby
+func NotifyUser(userID int64, message websocket.WSMessage) {
+	gameHandler.lobbyHub.SendToUser(userID, message)
+}
+
 // CleanupGameHub supprime le hub d'une partie terminée
 func (wsh *WebSocketHandler) CleanupGameHub(gameID string) {
 	wsh.mutex.Lock()
@@ -2,6 +2,9 @@ package main
 
 import (
 	"quarto/config"
+	"quarto/models/ai"
+	"quarto/models/challenge"
+	"quarto/models/game"
 	"quarto/models/postgresql"
 	"time"
 
@@ -13,6 +16,38 @@ func init() {
 
 	config.Init(Folder)
 	postgresql.SQLCtx, postgresql.SQLConn = config.InitPgSQL()
+	challenge.InviteSecret = []byte(config.Config.InviteSecret)
+	game.Rules = game.ActionRules{
+		DrawOffersUnratedOnly: config.Config.DrawOffersUnratedOnly,
+		TakebacksUnratedOnly:  config.Config.TakebacksUnratedOnly,
+	}
+
+	ai.TTSizeMB = config.Config.AITableSizeMB
+	ai.Threads = config.Config.AIThreads
+	ai.GameTables = config.Config.AIGameTables
+	ai.MCTSExploration = config.Config.AIMCTSExploration
+	ai.MCTSMaxNodes = config.Config.AIMCTSMaxNodes
+	if weights, err := ai.ParseWeights(config.Config.AIEvalWeights); err != nil {
+		log.Warn("AI_EVAL_WEIGHTS invalid, using default weights", "err", err)
+	} else {
+		ai.EvalWeights = weights
+	}
+	if config.Config.AITablebase != "" {
+		if tablebase, err := ai.LoadTablebase(config.Config.AITablebase); err != nil {
+			log.Warn("AI_TABLEBASE could not be loaded, searching without endgame tablebase", "err", err)
+		} else {
+			ai.EndgameTablebase = tablebase
+			log.Info("Endgame tablebase loaded", "positions", tablebase.Len(), "maxEmpty", tablebase.MaxEmpty)
+		}
+	}
+	if config.Config.AIOpeningBook != "" {
+		if book, err := ai.LoadBook(config.Config.AIOpeningBook); err != nil {
+			log.Warn("AI_OPENING_BOOK could not be loaded, searching without opening book", "err", err)
+		} else {
+			ai.OpeningBook = book
+			log.Info("Opening book loaded", "positions", book.Len(), "depth", book.Depth)
+		}
+	}
 
 	log.Debug("Initialization ended", "took", time.Since(start).Round(time.Millisecond).String())
 }
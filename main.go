@@ -1,11 +1,13 @@
 package main
 
 import (
+	"context"
 	"embed"
 	"fmt"
 	"os"
 	"quarto/config"
 	"quarto/handlers"
+	"quarto/scheduler"
 
 	_ "quarto/docs"
 
@@ -58,6 +60,12 @@ func main() {
 		api.Add(handler.Method, handler.Path, handler.Handler, handler.Middlewares...)
 	}
 
+	// Start background jobs
+	for _, job := range handlers.Jobs() {
+		scheduler.Register(job)
+	}
+	scheduler.Start(context.Background())
+
 	// Swagger
 	if log.GetLevel() == log.DebugLevel {
 		api.GET("/swagger/*", echoSwagger.WrapHandler)
@@ -0,0 +1,315 @@
+package ai
+
+import (
+	"math/bits"
+	"quarto/models/game"
+)
+
+// Bitboard est la représentation interne de l'état du jeu pendant la recherche : chaque case est un bit
+// (rangée*4 + colonne), chaque pièce un bit de l'ensemble des pièces disponibles. La position d'une pièce est
+// entièrement décrite par son bit d'occupation et ses quatre bits de caractéristiques, ce qui permet de vérifier
+// un alignement par quelques opérations bit à bit et de jouer un coup sans allocation.
+type Bitboard struct {
+	Occupied   uint16     // Cases occupées
+	Attributes [4]uint16  // Cases occupées par une pièce dont la caractéristique vaut 1 (bit correspondant de la pièce)
+	Available  uint16     // Pièces restant à donner, hors pièce en main
+	Selected   game.Piece // Pièce en main, PieceEmpty si aucune
+	IsGameOver bool       // Jeu terminé
+	Winner     int        // 0 = pas de gagnant, 1 = joueur 1, -1 = joueur 2
+	Hash       uint64     // Clé de Zobrist, identique à celle du GameState correspondant
+	rules      *rules
+}
+
+// bitMove est un coup de la recherche : la case où poser la pièce en main et la pièce donnée à l'adversaire
+// (PieceEmpty pour le dernier placement)
+type bitMove struct {
+	square int8
+	piece  int8
+}
+
+// maxMoves borne le nombre de coups d'une position : 16 cases pour 15 pièces à donner
+const maxMoves = 16 * 15
+
+// rules regroupe ce que la recherche doit connaître de la variante
+type rules struct {
+	variant    string
+	key        uint64          // Clé de Zobrist de la variante
+	all        []uint16        // Alignements gagnants
+	lines      [16][]uint16    // Alignements gagnants passant par chaque case
+	symmetries []boardSymmetry // Symétries du plateau préservant les alignements
+}
+
+// variantRules contient les règles des variantes connues, calculées une fois pour toutes
+var variantRules = func() map[string]*rules {
+	known := map[string]*rules{}
+	for _, variant := range []string{"", game.VariantStandard, game.VariantSquares, game.VariantSquaresTorus} {
+		known[variant] = newRules(variant)
+	}
+	return known
+}()
+
+// newRules calcule les masques d'alignement de la variante
+func newRules(variant string) *rules {
+	r := &rules{
+		variant:    variant,
+		key:        zobristVariants[variant],
+		symmetries: symmetriesFor(variant),
+	}
+	for _, line := range game.WinningLines(variant) {
+		var mask uint16
+		for _, square := range line {
+			mask |= 1 << (square.Row*4 + square.Col)
+		}
+		r.all = append(r.all, mask)
+		for _, square := range line {
+			r.lines[square.Row*4+square.Col] = append(r.lines[square.Row*4+square.Col], mask)
+		}
+	}
+	return r
+}
+
+// rulesFor retourne les règles de la variante
+func rulesFor(variant string) *rules {
+	if r, ok := variantRules[variant]; ok {
+		return r
+	}
+	return newRules(variant)
+}
+
+// NewBitboard convertit un GameState en Bitboard
+func NewBitboard(state GameState) Bitboard {
+	b := Bitboard{
+		Selected:   state.SelectedPiece,
+		IsGameOver: state.IsGameOver,
+		Winner:     state.Winner,
+		rules:      rulesFor(state.Variant),
+	}
+	for row := range 4 {
+		for col := range 4 {
+			if piece := state.Board[row][col]; piece != game.PieceEmpty {
+				b.set(row*4+col, piece)
+			}
+		}
+	}
+	for _, piece := range state.AvailablePieces {
+		b.Available |= 1 << piece
+	}
+	b.Hash = b.ComputeHash()
+	return b
+}
+
+// GameState reconvertit le Bitboard en GameState
+func (b Bitboard) GameState() GameState {
+	state := GameState{
+		Board:           game.GetEmptyBoard(),
+		AvailablePieces: []game.Piece{},
+		SelectedPiece:   b.Selected,
+		IsGameOver:      b.IsGameOver,
+		Winner:          b.Winner,
+		Variant:         b.rules.variant,
+		Hash:            b.Hash,
+	}
+	for square := range 16 {
+		state.Board[square/4][square%4] = b.PieceAt(square)
+	}
+	for available := b.Available; available != 0; available &= available - 1 {
+		state.AvailablePieces = append(state.AvailablePieces, game.Piece(bits.TrailingZeros16(available)))
+	}
+	return state
+}
+
+// set pose une pièce sur une case vide
+func (b *Bitboard) set(square int, piece game.Piece) {
+	b.Occupied |= 1 << square
+	for attribute := range b.Attributes {
+		b.Attributes[attribute] |= uint16(piece>>attribute&1) << square
+	}
+}
+
+// PieceAt retourne la pièce posée sur la case, PieceEmpty si elle est vide
+func (b *Bitboard) PieceAt(square int) game.Piece {
+	if b.Occupied>>square&1 == 0 {
+		return game.PieceEmpty
+	}
+	var piece game.Piece
+	for attribute, mask := range b.Attributes {
+		piece |= game.Piece(mask>>square&1) << attribute
+	}
+	return piece
+}
+
+// squares retourne la pièce de chaque case
+func (b *Bitboard) squares() (squares [16]game.Piece) {
+	for square := range squares {
+		squares[square] = b.PieceAt(square)
+	}
+	return
+}
+
+// ComputeHash calcule la clé de Zobrist complète ; Play la met ensuite à jour de façon incrémentale
+func (b *Bitboard) ComputeHash() uint64 {
+	hash := selectedKey(b.Selected) ^ b.rules.key
+	for occupied := b.Occupied; occupied != 0; occupied &= occupied - 1 {
+		square := bits.TrailingZeros16(occupied)
+		hash ^= zobristSquares[square][b.PieceAt(square)]
+	}
+	return hash
+}
+
+// completesLine vérifie si la pièce posée sur la case forme un alignement gagnant
+func (b *Bitboard) completesLine(square int) bool {
+	for _, line := range b.rules.lines[square] {
+		if b.Occupied&line != line {
+			continue
+		}
+		for _, mask := range b.Attributes {
+			if common := mask & line; common == 0 || common == line {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// threats compte les alignements de trois pièces partageant une caractéristique, la quatrième case vide, et retourne
+// l'ensemble des pièces qui en complètent au moins un
+func (b *Bitboard) threats() (count int, poisoned uint16) {
+	for _, line := range b.rules.all {
+		occupied := b.Occupied & line
+		if bits.OnesCount16(occupied) != 3 {
+			continue
+		}
+		threat := false
+		for attribute, mask := range b.Attributes {
+			switch mask & line {
+			case 0:
+				poisoned |= piecesWith[attribute][0]
+				threat = true
+			case occupied:
+				poisoned |= piecesWith[attribute][1]
+				threat = true
+			}
+		}
+		if threat {
+			count++
+		}
+	}
+	return
+}
+
+// winningMove retourne un coup qui place la pièce en main sur une case où elle complète un alignement
+func (b *Bitboard) winningMove() (bitMove, bool) {
+	if b.Selected == game.PieceEmpty {
+		return bitMove{}, false
+	}
+	for _, line := range b.rules.all {
+		occupied := b.Occupied & line
+		if bits.OnesCount16(occupied) != 3 {
+			continue
+		}
+		for attribute, mask := range b.Attributes {
+			value := uint16(b.Selected>>attribute) & 1
+			if common := mask & line; (common == 0 && value == 0) || (common == occupied && value == 1) {
+				// La pièce donnée n'a plus d'importance, la partie s'arrête
+				piece := int8(game.PieceEmpty)
+				if b.Available != 0 {
+					piece = int8(bits.TrailingZeros16(b.Available))
+				}
+				return bitMove{square: int8(bits.TrailingZeros16(line &^ occupied)), piece: piece}, true
+			}
+		}
+	}
+	return bitMove{}, false
+}
+
+// appendMoves ajoute les coups de la position à moves, sans autre allocation que celle éventuelle de moves
+func (b *Bitboard) appendMoves(moves []bitMove) []bitMove {
+	empty := ^b.Occupied
+	if b.Available == 0 {
+		// Dernier placement : rien à donner ensuite
+		if bits.OnesCount16(empty) == 1 {
+			moves = append(moves, bitMove{square: int8(bits.TrailingZeros16(empty)), piece: int8(game.PieceEmpty)})
+		}
+		return moves
+	}
+	for ; empty != 0; empty &= empty - 1 {
+		square := int8(bits.TrailingZeros16(empty))
+		for available := b.Available; available != 0; available &= available - 1 {
+			moves = append(moves, bitMove{square: square, piece: int8(bits.TrailingZeros16(available))})
+		}
+	}
+	return moves
+}
+
+// appendSafeMoves ajoute les coups de la position, sauf ceux qui donnent à l'adversaire une pièce complétant un
+// alignement : ils perdent au coup suivant. Si toutes les pièces sont dans ce cas après un placement, un seul de ces
+// coups perdants est conservé. La position ne doit pas offrir de placement gagnant (voir winningMove).
+func (b *Bitboard) appendSafeMoves(moves []bitMove) []bitMove {
+	if b.Available == 0 {
+		return b.appendMoves(moves)
+	}
+	for empty := ^b.Occupied; empty != 0; empty &= empty - 1 {
+		square := bits.TrailingZeros16(empty)
+		placed := *b
+		placed.set(square, b.Selected)
+		_, poisoned := placed.threats()
+
+		gifts := b.Available &^ poisoned
+		if gifts == 0 {
+			gifts = b.Available & -b.Available
+		}
+		for ; gifts != 0; gifts &= gifts - 1 {
+			moves = append(moves, bitMove{square: int8(square), piece: int8(bits.TrailingZeros16(gifts))})
+		}
+	}
+	return moves
+}
+
+// Play joue un coup et retourne la nouvelle position, avec les mêmes règles que GameState.ApplyMove
+func (b Bitboard) Play(move bitMove) Bitboard {
+	square := int(move.square)
+	piece := game.Piece(move.piece)
+	b.set(square, b.Selected)
+	b.Hash ^= zobristSquares[square][b.Selected] ^ selectedKey(b.Selected) ^ selectedKey(piece)
+
+	win := b.completesLine(square)
+	if win || b.Available == 0 {
+		b.IsGameOver = true
+		if win {
+			// Le joueur 1 place quand il reste un nombre pair de pièces à donner
+			b.Winner = 1 - bits.OnesCount16(b.Available)%2*2
+		}
+	}
+
+	if piece != game.PieceEmpty {
+		b.Available &^= 1 << piece
+	}
+	b.Selected = piece
+	return b
+}
+
+// aiMove convertit un coup de la recherche joué depuis la position
+func (b *Bitboard) aiMove(move bitMove) AIMove {
+	return AIMove{
+		Move: game.Move{
+			Piece:    b.Selected,
+			Position: game.Position{Row: int(move.square) / 4, Col: int(move.square) % 4},
+		},
+		SelectedPiece: game.Piece(move.piece),
+	}
+}
+
+// bitMoveOf convertit un AIMove en coup de la recherche
+func bitMoveOf(move AIMove) bitMove {
+	return bitMove{square: int8(move.Move.Position.Row*4 + move.Move.Position.Col), piece: int8(move.SelectedPiece)}
+}
+
+// aiMoves convertit une continuation jouée depuis la position
+func (b Bitboard) aiMoves(moves []bitMove) []AIMove {
+	result := make([]AIMove, 0, len(moves))
+	for _, move := range moves {
+		result = append(result, b.aiMove(move))
+		b = b.Play(move)
+	}
+	return result
+}
@@ -0,0 +1,95 @@
+package ai
+
+import (
+	"math/rand"
+	"quarto/models/game"
+	"slices"
+	"testing"
+)
+
+// sameState compare deux états, l'ordre des pièces disponibles mis à part
+func sameState(a, b GameState) bool {
+	sorted := func(pieces []game.Piece) []game.Piece {
+		pieces = slices.Clone(pieces)
+		slices.Sort(pieces)
+		return pieces
+	}
+	return a.Board == b.Board && a.SelectedPiece == b.SelectedPiece && a.IsGameOver == b.IsGameOver &&
+		a.Winner == b.Winner && a.Hash == b.Hash && slices.Equal(sorted(a.AvailablePieces), sorted(b.AvailablePieces))
+}
+
+func TestBitboardMatchesGameState(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	for _, variant := range []string{game.VariantStandard, game.VariantSquares, game.VariantSquaresTorus} {
+		for range 100 {
+			state := openingState()
+			state.Variant = variant
+			state.Hash = state.ComputeHash()
+			b := NewBitboard(state)
+
+			for !state.IsGameOver {
+				if !sameState(b.GameState(), state) {
+					t.Fatalf("%s: bitboard %+v differs from %+v", variant, b.GameState(), state)
+				}
+
+				// Mêmes coups, dans le même ordre
+				expected := GetValidMoves(state)
+				moves := b.appendMoves(nil)
+				if len(moves) != len(expected) {
+					t.Fatalf("%s: %d moves instead of %d", variant, len(moves), len(expected))
+				}
+				for i, move := range moves {
+					if b.aiMove(move) != expected[i] {
+						t.Fatalf("%s: move %d is %+v instead of %+v", variant, i, b.aiMove(move), expected[i])
+					}
+				}
+				if len(moves) == 0 {
+					break
+				}
+
+				i := rng.Intn(len(moves))
+				state = state.ApplyMove(expected[i])
+				b = b.Play(moves[i])
+			}
+			if !sameState(b.GameState(), state) {
+				t.Fatalf("%s: final bitboard %+v differs from %+v", variant, b.GameState(), state)
+			}
+		}
+	}
+}
+
+func TestBitboardAllocations(t *testing.T) {
+	b := NewBitboard(openingState())
+	allocations := testing.AllocsPerRun(100, func() {
+		var buffer [maxMoves]bitMove
+		for _, move := range b.appendMoves(buffer[:0]) {
+			child := b.Play(move)
+			if child.IsGameOver {
+				break
+			}
+		}
+	})
+	if allocations != 0 {
+		t.Errorf("expected no allocation, got %.0f", allocations)
+	}
+}
+
+func BenchmarkGameStateMoves(b *testing.B) {
+	state := openingState()
+	state.Hash = state.ComputeHash()
+	for range b.N {
+		for _, move := range GetValidMoves(state) {
+			state.ApplyMove(move)
+		}
+	}
+}
+
+func BenchmarkBitboardMoves(b *testing.B) {
+	board := NewBitboard(openingState())
+	for range b.N {
+		var buffer [maxMoves]bitMove
+		for _, move := range board.appendMoves(buffer[:0]) {
+			board.Play(move)
+		}
+	}
+}
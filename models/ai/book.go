@@ -0,0 +1,299 @@
+package ai
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+	"math/rand"
+	"os"
+	"quarto/models/game"
+	"slices"
+	"time"
+)
+
+// Livre d'ouvertures. Les premiers coups d'une partie sont trop loin de la fin pour être cherchés utilement en temps
+// réel : le livre conserve, pour chaque position d'ouverture sous sa forme canonique, les meilleurs coups trouvés par
+// des recherches profondes hors ligne. Le moteur tire l'un d'eux au hasard, selon son poids, pour varier les parties.
+
+// bookMagic identifie les fichiers de livre d'ouvertures
+const bookMagic = "QOB1"
+
+// bookMove est un coup du livre, dans l'orientation canonique de la position
+type bookMove struct {
+	move   bitMove
+	weight int // Poids du coup dans le tirage, au moins 1
+	score  int // Score de la recherche, du point de vue du joueur au trait
+}
+
+// Book est un livre d'ouvertures, indexé par clé canonique des positions
+type Book struct {
+	Depth     int // Profondeur des recherches qui ont évalué les coups
+	positions map[uint64][]bookMove
+}
+
+// OpeningBook est le livre d'ouvertures des nouveaux moteurs (nil = aucun)
+var OpeningBook *Book
+
+// BookOptions paramètre la construction d'un livre d'ouvertures
+type BookOptions struct {
+	Variant   string        // Variante de règles (vide = standard)
+	Plies     int           // Nombre de placements couverts par le livre, depuis le plateau vide
+	Depth     int           // Profondeur des recherches évaluant chaque coup
+	TimeLimit time.Duration // Durée maximale de chaque recherche (0 = sans limite)
+	Margin    int           // Écart de score maximal avec le meilleur coup pour figurer dans le livre
+}
+
+// Len retourne le nombre de positions du livre
+func (book *Book) Len() int {
+	return len(book.positions)
+}
+
+// BuildBook construit un livre d'ouvertures : chaque coup des positions d'ouverture, aux symétries près, est évalué
+// par une recherche, et ceux qui s'écartent du meilleur d'au plus Margin sont retenus, d'autant plus probables qu'ils
+// en sont proches. Les positions qu'ils atteignent sont développées à leur tour. progress, s'il est donné, reçoit
+// le nombre de positions déjà traitées.
+func BuildBook(options BookOptions, progress func(positions int)) *Book {
+	book := &Book{Depth: options.Depth, positions: make(map[uint64][]bookMove)}
+
+	engine := NewEngine(options.Depth)
+	engine.TimeLimit = options.TimeLimit
+	engine.Book = nil
+
+	// Toutes les pièces se valent au premier coup : le livre commence avec la pièce 0 à placer
+	state := GameState{Board: game.GetEmptyBoard(), SelectedPiece: 0, Variant: options.Variant}
+	for piece := game.Piece(1); piece < PieceCount; piece++ {
+		state.AvailablePieces = append(state.AvailablePieces, piece)
+	}
+	root := NewBitboard(state)
+	book.expand(engine, &root, 0, options, progress)
+	return book
+}
+
+// expand ajoute une position au livre, puis les positions atteintes par ses coups retenus
+func (book *Book) expand(engine *Engine, b *Bitboard, placed int, options BookOptions, progress func(int)) {
+	if placed >= options.Plies || b.IsGameOver {
+		return
+	}
+	key, symmetry := b.canonical()
+	if _, ok := book.positions[key]; ok {
+		return
+	}
+
+	var buffer [maxMoves]bitMove
+	moves := b.uniqueMoves(b.appendMoves(buffer[:0]))
+	isMaximizing := bits.OnesCount16(b.Available)%2 == 0
+	scores := make([]int, len(moves))
+	best := LOSS_SCORE
+	for i, move := range moves {
+		child := b.Play(move)
+		if child.IsGameOver {
+			scores[i] = engine.evaluate(&child)
+		} else {
+			scores[i] = engine.Search(child.GameState()).Score
+		}
+		if !isMaximizing {
+			scores[i] = -scores[i]
+		}
+		best = max(best, scores[i])
+	}
+
+	var entries []bookMove
+	var kept []bitMove
+	for i, move := range moves {
+		if gap := best - scores[i]; gap <= options.Margin {
+			entries = append(entries, bookMove{move: symmetry.bitMove(move, false), weight: options.Margin - gap + 1, score: scores[i]})
+			kept = append(kept, move)
+		}
+	}
+	book.positions[key] = entries
+	if progress != nil {
+		progress(len(book.positions))
+	}
+
+	for _, move := range kept {
+		child := b.Play(move)
+		book.expand(engine, &child, placed+1, options, progress)
+	}
+}
+
+// moves retourne les coups du livre pour une position, dans son orientation
+func (book *Book) moves(b *Bitboard) []bookMove {
+	key, symmetry := b.canonical()
+	entries := slices.Clone(book.positions[key])
+	for i := range entries {
+		entries[i].move = symmetry.bitMove(entries[i].move, true)
+	}
+	return entries
+}
+
+// pick tire un coup du livre pour une position, selon les poids
+func (book *Book) pick(b *Bitboard, intn func(int) int) (bookMove, bool) {
+	entries := book.moves(b)
+	total := 0
+	for _, entry := range entries {
+		total += entry.weight
+	}
+	if total == 0 {
+		return bookMove{}, false
+	}
+
+	r := intn(total)
+	for _, entry := range entries {
+		if r < entry.weight {
+			return entry, true
+		}
+		r -= entry.weight
+	}
+	return bookMove{}, false
+}
+
+// Write écrit le livre compressé : l'en-tête, puis chaque position par clé croissante avec ses coups
+func (book *Book) Write(w io.Writer) error {
+	zw := gzip.NewWriter(w)
+	bw := bufio.NewWriter(zw)
+
+	keys := make([]uint64, 0, len(book.positions))
+	for key := range book.positions {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	var buffer [binary.MaxVarintLen64]byte
+	bw.WriteString(bookMagic)
+	bw.Write(binary.AppendUvarint(buffer[:0], uint64(book.Depth)))
+	bw.Write(binary.AppendUvarint(buffer[:0], uint64(len(keys))))
+	previous := uint64(0)
+	for _, key := range keys {
+		entries := book.positions[key]
+		bw.Write(binary.AppendUvarint(buffer[:0], key-previous))
+		bw.Write(binary.AppendUvarint(buffer[:0], uint64(len(entries))))
+		for _, entry := range entries {
+			bw.WriteByte(byte(entry.move.square))
+			bw.WriteByte(byte(entry.move.piece))
+			bw.Write(binary.AppendUvarint(buffer[:0], uint64(entry.weight)))
+			bw.Write(binary.AppendVarint(buffer[:0], int64(entry.score)))
+		}
+		previous = key
+	}
+
+	// Les erreurs d'écriture sont conservées par bw jusqu'à Flush
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// ReadBook lit un livre écrit par Write
+func ReadBook(r io.Reader) (*Book, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("livre d'ouvertures illisible : %w", err)
+	}
+	defer zr.Close()
+	br := bufio.NewReader(zr)
+
+	magic := make([]byte, len(bookMagic))
+	if _, err := io.ReadFull(br, magic); err != nil || string(magic) != bookMagic {
+		return nil, errors.New("le fichier n'est pas un livre d'ouvertures")
+	}
+	depth, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, fmt.Errorf("en-tête du livre invalide : %w", err)
+	}
+	count, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, fmt.Errorf("en-tête du livre invalide : %w", err)
+	}
+
+	book := &Book{Depth: int(depth), positions: make(map[uint64][]bookMove)}
+	truncated := func(err error) (*Book, error) {
+		return nil, fmt.Errorf("livre tronqué : %w", err)
+	}
+	key := uint64(0)
+	for range count {
+		delta, err := binary.ReadUvarint(br)
+		if err != nil {
+			return truncated(err)
+		}
+		moves, err := binary.ReadUvarint(br)
+		if err != nil {
+			return truncated(err)
+		}
+		key += delta
+
+		entries := make([]bookMove, 0, min(int(moves), maxMoves))
+		for range moves {
+			var move [2]byte
+			if _, err := io.ReadFull(br, move[:]); err != nil {
+				return truncated(err)
+			}
+			weight, err := binary.ReadUvarint(br)
+			if err != nil {
+				return truncated(err)
+			}
+			score, err := binary.ReadVarint(br)
+			if err != nil {
+				return truncated(err)
+			}
+			if move[0] >= 16 || (int8(move[1]) != int8(game.PieceEmpty) && move[1] >= PieceCount) {
+				return nil, fmt.Errorf("coup invalide dans le livre : case %d, pièce %d", move[0], int8(move[1]))
+			}
+			entries = append(entries, bookMove{move: bitMove{square: int8(move[0]), piece: int8(move[1])}, weight: int(weight), score: int(score)})
+		}
+		book.positions[key] = entries
+	}
+	return book, nil
+}
+
+// LoadBook charge un livre depuis un fichier
+func LoadBook(path string) (*Book, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadBook(file)
+}
+
+// Save enregistre le livre dans un fichier
+func (book *Book) Save(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := book.Write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// bookIntn tire un entier dans [0, n) avec le générateur du moteur
+func (e *Engine) bookIntn(n int) int {
+	if e.BookRand != nil {
+		return e.BookRand.Intn(n)
+	}
+	return rand.Intn(n)
+}
+
+// probeBook tire un coup du livre d'ouvertures du moteur pour la racine, si elle y figure
+func (e *Engine) probeBook(state GameState) (SearchResult, bool) {
+	if e.Book == nil {
+		return SearchResult{}, false
+	}
+	root := NewBitboard(state)
+	entry, ok := e.Book.pick(&root, e.bookIntn)
+	if !ok {
+		return SearchResult{}, false
+	}
+
+	score := entry.score
+	if len(state.AvailablePieces)%2 != 0 {
+		score = -score
+	}
+	return SearchResult{BestMoves: []AIMove{root.aiMove(entry.move)}, Score: score, Depth: e.Book.Depth}, true
+}
@@ -0,0 +1,112 @@
+package ai
+
+import (
+	"bytes"
+	"math/rand"
+	"quarto/models/game"
+	"slices"
+	"testing"
+)
+
+// testBook construit un petit livre, partagé par les tests
+var testBook = func() func() *Book {
+	var book *Book
+	return func() *Book {
+		if book == nil {
+			book = BuildBook(BookOptions{Variant: game.VariantStandard, Plies: 2, Depth: 2, Margin: 20}, nil)
+		}
+		return book
+	}
+}()
+
+func TestBuildBook(t *testing.T) {
+	book := testBook()
+	if book.Len() < 2 {
+		t.Fatalf("expected the first two plies in the book, got %d positions", book.Len())
+	}
+
+	for key, entries := range book.positions {
+		if len(entries) == 0 {
+			t.Fatalf("position %x has no move", key)
+		}
+		best := slices.MaxFunc(entries, func(a, b bookMove) int { return a.score - b.score })
+		for _, entry := range entries {
+			if entry.weight < 1 || best.score-entry.score > 20 || entry.weight != 20-(best.score-entry.score)+1 {
+				t.Errorf("move %+v should not be weighted %d next to a best score of %d", entry.move, entry.weight, best.score)
+			}
+		}
+	}
+}
+
+func TestBookProbe(t *testing.T) {
+	book := testBook()
+	var buffer bytes.Buffer
+	if err := book.Write(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadBook(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.Depth != book.Depth || len(read.positions) != len(book.positions) {
+		t.Fatalf("read %d positions at depth %d, expected %d at depth %d", read.Len(), read.Depth, book.Len(), book.Depth)
+	}
+	for key, entries := range book.positions {
+		if !slices.Equal(read.positions[key], entries) {
+			t.Fatalf("position %x: read %+v, expected %+v", key, read.positions[key], entries)
+		}
+	}
+
+	// Le premier placement, quelle que soit la pièce donnée et l'orientation, est joué depuis le livre
+	rng := rand.New(rand.NewSource(9))
+	engine := NewEngine(DefaultMaxDepth)
+	engine.Book = read
+	engine.BookRand = rng
+	for range 50 {
+		selected := game.Piece(rng.Intn(PieceCount))
+		state := GameState{Board: game.GetEmptyBoard(), SelectedPiece: selected, Variant: game.VariantStandard}
+		state.AvailablePieces = slices.DeleteFunc(game.GetAllPieces(), func(piece game.Piece) bool { return piece == selected })
+
+		result := engine.Search(state)
+		if result.Nodes != 0 || result.Depth != book.Depth || len(result.BestMoves) != 1 {
+			t.Fatalf("expected a book move, got %+v", result)
+		}
+		if !slices.Contains(GetValidMoves(state), result.BestMoves[0]) {
+			t.Fatalf("book move %+v is not legal", result.BestMoves[0])
+		}
+	}
+
+	// Le tirage varie entre les coups du livre
+	state := GameState{Board: game.GetEmptyBoard(), SelectedPiece: 0, AvailablePieces: game.GetAllPieces()[1:]}
+	root := NewBitboard(state)
+	var expected []AIMove
+	for _, entry := range read.moves(&root) {
+		expected = append(expected, root.aiMove(entry.move))
+	}
+	drawn := make(map[AIMove]bool)
+	for range 100 {
+		move := engine.Search(state).BestMoves[0]
+		if !slices.Contains(expected, move) {
+			t.Fatalf("move %+v is not in the book", move)
+		}
+		drawn[move] = true
+	}
+	if len(expected) > 1 && len(drawn) < 2 {
+		t.Errorf("always drew the same move among %d book moves", len(expected))
+	}
+}
+
+func TestBookFirstPiece(t *testing.T) {
+	state := GameState{Board: game.GetEmptyBoard(), SelectedPiece: game.PieceEmpty, AvailablePieces: game.GetAllPieces()}
+	engine := NewEngine(DefaultMaxDepth)
+	engine.Book = &Book{}
+	engine.BookRand = rand.New(rand.NewSource(10))
+
+	pieces := make(map[game.Piece]bool)
+	for range 20 {
+		pieces[engine.Search(state).BestMoves[0].SelectedPiece] = true
+	}
+	if len(pieces) < 2 {
+		t.Errorf("expected varied first pieces with an opening book, got %v", pieces)
+	}
+}
@@ -0,0 +1,79 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"quarto/models/game"
+	"time"
+)
+
+// BotMove représente le coup du bot : le placement de la pièce en main (phase de placement), puis la pièce à donner
+type BotMove struct {
+	Place    bool
+	Position game.Position
+	Piece    game.Piece // Pièce à donner (-1 si la partie se termine)
+}
+
+// NewBotSearcher crée le moteur choisi dans les options du bot, avec son temps de réflexion par coup
+func NewBotSearcher(options game.BotOptions) (Searcher, error) {
+	timeLimit := DefaultTimeLimit
+	if options.TimeLimitMs > 0 {
+		timeLimit = time.Duration(options.TimeLimitMs) * time.Millisecond
+	}
+	nodeLimit := 0
+	if options.Engine == SearcherMCTS {
+		nodeLimit = DefaultMCTSIterations
+	}
+	return NewSearcher(options.Engine, DefaultMaxDepth, timeLimit, nodeLimit)
+}
+
+// FindBotMove cherche le coup du bot au trait avec le moteur de la partie
+func FindBotMove(ctx context.Context, g game.Game) (move BotMove, err error) {
+	move.Piece = game.PieceEmpty
+	if !g.IsBotTurn() {
+		return move, game.ErrNotYourTurn.With("player", g.CurrentTurn)
+	}
+
+	options := *g.Options.Bot
+	state := ConvertGameToState(g)
+
+	// Plateau vide : toutes les pièces se valent
+	if g.GamePhase == game.GamePhaseSelectPiece && len(state.AvailablePieces) == 16 {
+		move.Piece = state.AvailablePieces[0]
+		return move, nil
+	}
+
+	if g.GamePhase == game.GamePhaseSelectPiece {
+		// Le temps de réflexion est partagé entre les pièces candidates
+		if options.TimeLimitMs <= 0 {
+			options.TimeLimitMs = int(DefaultTimeLimit.Milliseconds())
+		}
+		options.TimeLimitMs = max(options.TimeLimitMs/len(state.AvailablePieces), 1)
+		searcher, err := NewBotSearcher(options)
+		if err != nil {
+			return move, err
+		}
+		move.Piece, _, _ = givePiece(ctx, searcher, state)
+		return move, nil
+	}
+
+	searcher, err := NewBotSearcher(options)
+	if err != nil {
+		return move, err
+	}
+	result := searcher.SearchContext(ctx, state)
+	if len(result.BestMoves) == 0 {
+		return move, fmt.Errorf("aucun coup trouvé pour la partie %s", g.ID)
+	}
+
+	best := result.BestMoves[0]
+	move.Place = true
+	move.Position = game.Position{Row: best.Move.Position.Row, Col: best.Move.Position.Col}
+	move.Piece = best.SelectedPiece
+
+	// Une recherche interrompue peut ne pas proposer de pièce alors que la partie continue
+	if move.Piece == game.PieceEmpty && len(state.AvailablePieces) > 0 {
+		move.Piece = state.AvailablePieces[0]
+	}
+	return move, nil
+}
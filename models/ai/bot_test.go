@@ -0,0 +1,46 @@
+package ai
+
+import (
+	"context"
+	"quarto/models/game"
+	"testing"
+)
+
+func TestFindBotMove(t *testing.T) {
+	for _, engine := range []string{SearcherMinimax, SearcherMCTS} {
+		g := game.InitializeGame(1, 2)
+		g.Options.Bot = &game.BotOptions{PlayerID: 1, Engine: engine, TimeLimitMs: 500}
+		g.Board = [4][4]game.Piece{
+			{0, 1, 4, game.PieceEmpty},
+			{game.PieceEmpty, game.PieceEmpty, game.PieceEmpty, game.PieceEmpty},
+			{game.PieceEmpty, game.PieceEmpty, game.PieceEmpty, game.PieceEmpty},
+			{game.PieceEmpty, game.PieceEmpty, game.PieceEmpty, game.PieceEmpty},
+		}
+		g.GamePhase = game.GamePhasePlacePiece
+		g.SelectedPiece = 2
+		g.AvailablePieces = []game.Piece{3, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+		// Placement : la pièce 2 complète la rangée, quel que soit le moteur
+		move, err := FindBotMove(context.Background(), g)
+		if err != nil {
+			t.Fatalf("%s: %v", engine, err)
+		}
+		if !move.Place || move.Position != (game.Position{Row: 0, Col: 3}) {
+			t.Errorf("%s: coup gagnant attendu en d1, obtenu %+v", engine, move)
+		}
+
+		// Ce n'est pas au bot de jouer
+		g.CurrentTurn = 2
+		if _, err := FindBotMove(context.Background(), g); err == nil {
+			t.Errorf("%s: le bot a joué hors de son tour", engine)
+		}
+	}
+
+	// Le bot qui commence donne une pièce sans recherche
+	g := game.InitializeGame(1, 2)
+	g.Options.Bot = &game.BotOptions{PlayerID: 1, Engine: SearcherMCTS}
+	move, err := FindBotMove(context.Background(), g)
+	if err != nil || move.Place || move.Piece == game.PieceEmpty {
+		t.Errorf("première pièce attendue, obtenu %+v (%v)", move, err)
+	}
+}
@@ -0,0 +1,60 @@
+package ai
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Tables de transposition conservées par partie. Les recherches successives d'une partie explorent des positions
+// voisines : leurs moteurs reprennent la table de la partie au lieu d'en allouer une à chaque requête. Au-delà de
+// GameTables tables, celle de la partie consultée le moins récemment est libérée. La table est lue et écrite sans
+// verrou : plusieurs recherches d'une même partie peuvent la partager simultanément.
+
+// GameTables est le nombre maximal de tables de parties conservées, chacune occupant TTSizeMB mégaoctets
+var GameTables = DefaultGameTables
+
+const DefaultGameTables = 8
+
+// gameTable associe une table de transposition à sa partie
+type gameTable struct {
+	gameID string
+	tt     *TranspositionTable
+}
+
+var gameTables = struct {
+	sync.Mutex
+	order   *list.List // Tables de la plus récemment consultée à la plus ancienne
+	entries map[string]*list.Element
+}{order: list.New(), entries: make(map[string]*list.Element)}
+
+// NewGameEngine crée un moteur dont la table de transposition est conservée entre les recherches de la partie
+func NewGameEngine(gameID string, maxDepth int) *Engine {
+	engine := newEngine(maxDepth, tableOf(gameID))
+	engine.KeepTT = true
+	return engine
+}
+
+// tableOf retourne la table de transposition d'une partie, créée au besoin ; une position hors partie a sa propre table
+func tableOf(gameID string) *TranspositionTable {
+	if gameID == "" || GameTables <= 0 {
+		return NewTranspositionTable(TTSizeMB)
+	}
+
+	gameTables.Lock()
+	defer gameTables.Unlock()
+
+	if element, ok := gameTables.entries[gameID]; ok {
+		gameTables.order.MoveToFront(element)
+		return element.Value.(*gameTable).tt
+	}
+
+	for gameTables.order.Len() >= GameTables {
+		oldest := gameTables.order.Back()
+		gameTables.order.Remove(oldest)
+		delete(gameTables.entries, oldest.Value.(*gameTable).gameID)
+	}
+
+	tt := NewTranspositionTable(TTSizeMB)
+	gameTables.entries[gameID] = gameTables.order.PushFront(&gameTable{gameID: gameID, tt: tt})
+	return tt
+}
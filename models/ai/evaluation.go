@@ -0,0 +1,123 @@
+package ai
+
+import (
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// Weights pondère les critères de l'évaluation heuristique des positions non terminales, du point de vue du joueur
+// au trait (celui qui doit placer la pièce en main)
+type Weights struct {
+	WinningPiece int // La pièce en main complète un alignement : le joueur au trait gagne au coup suivant
+	Threat       int // Par alignement de trois pièces partageant une caractéristique, la quatrième case vide
+	SafePiece    int // Par pièce restant à donner qui ne complète aucun alignement
+	NoSafePiece  int // Plus aucune pièce sûre à donner après le placement
+	Parity       int // Nombre impair de pièces sûres : le joueur au trait donnera la dernière
+}
+
+// DefaultWeights sont les poids utilisés en l'absence de configuration
+var DefaultWeights = Weights{
+	WinningPiece: 2000,
+	Threat:       -10,
+	SafePiece:    10,
+	NoSafePiece:  -500,
+	Parity:       100,
+}
+
+// EvalWeights sont les poids de l'évaluation des nouveaux moteurs
+var EvalWeights = DefaultWeights
+
+// maxHeuristicScore borne l'évaluation heuristique, qui doit rester distincte d'une victoire ou d'une défaite
+const maxHeuristicScore = WIN_SCORE / 2
+
+// ParseWeights lit des poids au format "winning_piece=2000,threat=-10,...", les poids absents gardant leur valeur
+// par défaut
+func ParseWeights(s string) (Weights, error) {
+	weights := DefaultWeights
+	fields := map[string]*int{
+		"winning_piece": &weights.WinningPiece,
+		"threat":        &weights.Threat,
+		"safe_piece":    &weights.SafePiece,
+		"no_safe_piece": &weights.NoSafePiece,
+		"parity":        &weights.Parity,
+	}
+
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(pair, "=")
+		field, known := fields[strings.TrimSpace(name)]
+		if !ok || !known {
+			return DefaultWeights, fmt.Errorf("poids inconnu ou mal formé : %q", pair)
+		}
+		weight, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return DefaultWeights, fmt.Errorf("poids %s invalide : %w", name, err)
+		}
+		*field = weight
+	}
+	return weights, nil
+}
+
+// piecesWith[attribute][value] est l'ensemble des pièces dont la caractéristique vaut value
+var piecesWith = func() (sets [4][2]uint16) {
+	for piece := range 16 {
+		for attribute := range 4 {
+			sets[attribute][piece>>attribute&1] |= 1 << piece
+		}
+	}
+	return
+}()
+
+// evaluatePosition évalue une position de jeu
+func (e *Engine) evaluatePosition(state GameState) int {
+	b := NewBitboard(state)
+	return e.evaluate(&b)
+}
+
+// evaluate évalue une position de la recherche, du point de vue du joueur 1
+func (e *Engine) evaluate(b *Bitboard) int {
+	if b.IsGameOver {
+		switch b.Winner {
+		case 1:
+			return WIN_SCORE
+		case -1:
+			return LOSS_SCORE
+		default:
+			return DRAW_SCORE
+		}
+	}
+
+	// Le joueur 1 place quand il reste un nombre pair de pièces à donner
+	score := e.heuristic(b)
+	if bits.OnesCount16(b.Available)%2 != 0 {
+		score = -score
+	}
+	return score
+}
+
+// heuristic évalue une position non terminale du point de vue du joueur au trait
+func (e *Engine) heuristic(b *Bitboard) int {
+	w := e.Weights
+
+	// Alignements de trois pièces partageant une caractéristique, et pièces qui les complètent
+	threats, poisoned := b.threats()
+
+	var score int
+	if b.Selected >= 0 && poisoned>>b.Selected&1 == 1 {
+		score = w.WinningPiece
+	} else {
+		safe := bits.OnesCount16(b.Available &^ poisoned)
+		score = threats*w.Threat + safe*w.SafePiece
+		if safe == 0 && b.Available != 0 {
+			score += w.NoSafePiece
+		}
+		if safe%2 == 1 {
+			score += w.Parity
+		}
+	}
+	return max(-maxHeuristicScore, min(score, maxHeuristicScore))
+}
@@ -0,0 +1,112 @@
+package ai
+
+import (
+	"math/rand"
+	"quarto/models/game"
+	"testing"
+)
+
+// threatState retourne une position où les pièces 0, 1 et 2 attendent une quatrième pièce petite ou blanche en d1
+func threatState(selected game.Piece, available ...game.Piece) GameState {
+	state := GameState{Board: game.GetEmptyBoard(), SelectedPiece: selected, AvailablePieces: available}
+	state.Board[0][0], state.Board[0][1], state.Board[0][2] = 0, 1, 2
+	return state
+}
+
+// moverScore ramène l'évaluation au point de vue du joueur au trait
+func moverScore(engine *Engine, state GameState) int {
+	score := engine.evaluatePosition(state)
+	if len(state.AvailablePieces)%2 != 0 {
+		score = -score
+	}
+	return score
+}
+
+func TestHeuristicOrdering(t *testing.T) {
+	engine := NewEngine(1)
+	engine.Weights = DefaultWeights
+
+	// Du meilleur au pire pour le joueur au trait
+	positions := []struct {
+		name  string
+		state GameState
+	}{
+		{"winning piece in hand", threatState(5, 12, 13, 14, 3, 4)},
+		{"odd number of safe pieces", threatState(15, 12, 13, 14, 3, 4)},
+		{"even number of safe pieces", threatState(15, 12, 13, 3, 4, 6)},
+		{"no safe piece", threatState(15, 3, 4, 5, 6, 7)},
+	}
+	for i := 1; i < len(positions); i++ {
+		better, worse := moverScore(engine, positions[i-1].state), moverScore(engine, positions[i].state)
+		if better <= worse {
+			t.Errorf("%q (%d) should score above %q (%d)", positions[i-1].name, better, positions[i].name, worse)
+		}
+	}
+
+	for _, position := range positions {
+		if score := engine.evaluatePosition(position.state); score <= LOSS_SCORE/2 || score >= WIN_SCORE/2 {
+			t.Errorf("%q: heuristic score %d should stay away from terminal scores", position.name, score)
+		}
+	}
+}
+
+func TestHeuristicPerspective(t *testing.T) {
+	engine := NewEngine(1)
+
+	// Une pièce empoisonnée de plus ne change rien pour le joueur au trait, mais c'est l'autre joueur qui place
+	player2 := threatState(15, 12, 13, 14, 3, 4)
+	player1 := threatState(15, 12, 13, 14, 3, 4, 5)
+	if engine.evaluatePosition(player1) != -engine.evaluatePosition(player2) || engine.evaluatePosition(player1) <= 0 {
+		t.Errorf("expected opposite scores, got %d for player 1 and %d for player 2",
+			engine.evaluatePosition(player1), engine.evaluatePosition(player2))
+	}
+}
+
+func TestHeuristicSymmetry(t *testing.T) {
+	engine := NewEngine(1)
+	rng := rand.New(rand.NewSource(5))
+	for _, variant := range []string{game.VariantStandard, game.VariantSquares} {
+		for range 200 {
+			state := randomState(rng, variant, rng.Intn(12))
+			if state.IsGameOver {
+				continue
+			}
+			image := randomSymmetry(rng, variant).apply(state)
+			if engine.evaluatePosition(state) != engine.evaluatePosition(image) {
+				t.Fatalf("%s: equivalent positions evaluated %d and %d", variant, engine.evaluatePosition(state), engine.evaluatePosition(image))
+			}
+		}
+	}
+}
+
+func TestHeuristicSearch(t *testing.T) {
+	// Une recherche d'un demi-coup voit désormais qu'il ne faut pas donner une pièce gagnante
+	state := threatState(15, 3, 4, 5, 6, 12, 13)
+	result := NewEngine(1).Search(state)
+	if len(result.BestMoves) == 0 {
+		t.Fatal("expected a move")
+	}
+
+	child := NewBitboard(state.ApplyMove(result.BestMoves[0]))
+	if engine := NewEngine(1); engine.heuristic(&child) == engine.Weights.WinningPiece {
+		t.Errorf("the search gave away a winning piece: %+v", result.BestMoves[0])
+	}
+}
+
+func TestParseWeights(t *testing.T) {
+	weights, err := ParseWeights("threat=-20, parity=50")
+	expected := DefaultWeights
+	expected.Threat, expected.Parity = -20, 50
+	if err != nil || weights != expected {
+		t.Errorf("got %+v (%v), expected %+v", weights, err, expected)
+	}
+
+	if weights, err := ParseWeights(""); err != nil || weights != DefaultWeights {
+		t.Errorf("empty configuration: got %+v (%v)", weights, err)
+	}
+	for _, invalid := range []string{"threat", "unknown=1", "parity=high"} {
+		if _, err := ParseWeights(invalid); err == nil {
+			t.Errorf("%q should be rejected", invalid)
+		}
+	}
+}
@@ -16,24 +16,29 @@ func ConvertGameToState(g game.Game) GameState {
 		winner = -1
 	}
 
-	return GameState{
+	state := GameState{
 		Board:           g.Board,
 		AvailablePieces: g.AvailablePieces,
 		SelectedPiece:   g.SelectedPiece,
 		IsGameOver:      g.Status == game.StatusFinished,
 		Winner:          winner,
+		Variant:         g.Options.Variant,
 	}
+	state.Hash = state.ComputeHash()
+	return state
 }
 
 // ConvertHistoryToGameState reconstruit un GameState à partir d'un historique de mouvements
-func ConvertHistoryToGameState(moves []game.Move) GameState {
+func ConvertHistoryToGameState(moves []game.Move, variant string) GameState {
 	// Initialiser l'état du jeu au début
 	state := GameState{
 		Board:           game.GetEmptyBoard(),
 		AvailablePieces: game.GetAllPieces(),
 		SelectedPiece:   game.PieceEmpty,
 		IsGameOver:      false,
+		Variant:         variant,
 	}
+	state.Hash = state.ComputeHash()
 
 	// Appliquer chaque mouvement de l'historique
 	for _, move := range moves {
@@ -63,7 +68,7 @@ func ConvertHistoryToGameState(moves []game.Move) GameState {
 
 // CheckGameOver vérifie si le jeu est terminé et retourne si il y a un gagnant
 func (state GameState) CheckGameOver() (bool, bool) {
-	win := game.CheckWin(state.Board)
+	win := game.CheckWinVariant(state.Board, state.Variant)
 	return win || len(state.AvailablePieces) == 0, win
 }
 
@@ -98,6 +103,8 @@ func GetValidMoves(state GameState) []AIMove {
 func (state GameState) ApplyMove(move AIMove) GameState {
 
 	state.Board[move.Move.Position.Row][move.Move.Position.Col] = move.Move.Piece
+	state.Hash ^= zobristSquares[move.Move.Position.Row*4+move.Move.Position.Col][move.Move.Piece] ^
+		selectedKey(state.SelectedPiece) ^ selectedKey(move.SelectedPiece)
 	gameOver, winner := state.CheckGameOver()
 	if gameOver {
 		state.IsGameOver = true
@@ -0,0 +1,82 @@
+package ai
+
+import (
+	"context"
+	"math"
+	"quarto/models/game"
+	"slices"
+)
+
+// Hint recherche le coup à conseiller au joueur au trait : le placement de la pièce en main et la pièce à donner,
+// ou seulement la pièce à donner pendant la phase de sélection. Retourne aussi la continuation attendue.
+func Hint(g game.Game, depth int) (hint game.Hint, continuation []string) {
+	hint.Piece = game.PieceEmpty
+	continuation = []string{}
+	if g.Status != game.StatusPlaying {
+		return
+	}
+
+	// Les recherches d'un même indice, et des indices successifs de la partie, partagent leur table de transposition
+	engine := NewGameEngine(g.ID, depth)
+	state := ConvertGameToState(g)
+
+	if g.GamePhase == game.GamePhasePlacePiece {
+		result := engine.Search(state)
+		if len(result.BestMoves) == 0 {
+			return
+		}
+		best := result.BestMoves[0]
+		hint.Position = game.CoordsToPosition(best.Move.Position.Row, best.Move.Position.Col)
+		hint.Piece = best.SelectedPiece
+		hint.Evaluation = moverEvaluation(state, result.Score)
+		return hint, notations(result.BestMoves)
+	}
+
+	// Plateau vide : toutes les pièces se valent
+	if len(state.AvailablePieces) == 16 {
+		hint.Piece = state.AvailablePieces[0]
+		return hint, []string{game.PieceToNotation(hint.Piece)}
+	}
+
+	// Phase de sélection : chercher la pièce qui laisse la moins bonne position à l'adversaire
+	hint.Piece, hint.Evaluation, continuation = givePiece(context.Background(), engine, state)
+	return
+}
+
+// givePiece cherche la pièce à donner qui laisse la moins bonne position à l'adversaire, avec son évaluation du point
+// de vue du joueur qui la donne et la continuation attendue
+func givePiece(ctx context.Context, searcher Searcher, state GameState) (piece game.Piece, evaluation float64, continuation []string) {
+	piece = game.PieceEmpty
+	evaluation = math.Inf(-1)
+	for _, candidate := range state.AvailablePieces {
+		opponent := state
+		opponent.SelectedPiece = candidate
+		opponent.AvailablePieces = slices.DeleteFunc(slices.Clone(state.AvailablePieces), func(p game.Piece) bool { return p == candidate })
+
+		result := searcher.SearchContext(ctx, opponent)
+		if candidateEvaluation := -moverEvaluation(opponent, result.Score); candidateEvaluation > evaluation {
+			evaluation = candidateEvaluation
+			piece = candidate
+			continuation = append([]string{game.PieceToNotation(candidate)}, notations(result.BestMoves)...)
+		}
+	}
+	return
+}
+
+// moverEvaluation ramène le score du moteur au point de vue du joueur qui place la pièce en main (entre -1 et 1)
+func moverEvaluation(state GameState, score int) float64 {
+	evaluation := float64(score) / WIN_SCORE
+	if len(state.AvailablePieces)%2 != 0 {
+		evaluation = -evaluation
+	}
+	return evaluation
+}
+
+// notations convertit une continuation en notation algébrique
+func notations(moves []AIMove) []string {
+	result := make([]string, 0, len(moves))
+	for _, move := range moves {
+		result = append(result, game.CreateMoveNotation(move.Move.Piece, game.CoordsToPosition(move.Move.Position.Row, move.Move.Position.Col)))
+	}
+	return result
+}
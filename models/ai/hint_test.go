@@ -0,0 +1,32 @@
+package ai
+
+import (
+	"quarto/models/game"
+	"testing"
+)
+
+func TestHint(t *testing.T) {
+	g := game.InitializeGame(1, 2)
+	g.Board = [4][4]game.Piece{
+		{0, 1, 4, game.PieceEmpty},
+		{game.PieceEmpty, game.PieceEmpty, game.PieceEmpty, game.PieceEmpty},
+		{game.PieceEmpty, game.PieceEmpty, game.PieceEmpty, game.PieceEmpty},
+		{game.PieceEmpty, game.PieceEmpty, game.PieceEmpty, game.PieceEmpty},
+	}
+	g.AvailablePieces = []game.Piece{2, 3, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+	// Sélection : ne pas donner de pièce blanche ou grande
+	hint, continuation := Hint(g, HintDepth)
+	if color, _, size, _ := game.GetPieceCharacteristics(hint.Piece); color == 0 || size == 0 || hint.Position != "" {
+		t.Errorf("pièce conseillée %s, continuation %v", game.PieceToNotation(hint.Piece), continuation)
+	}
+
+	// Placement : la pièce 2 complète la rangée
+	g.GamePhase = game.GamePhasePlacePiece
+	g.SelectedPiece = 2
+	g.AvailablePieces = []game.Piece{3, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+	hint, _ = Hint(g, HintDepth)
+	if hint.Position != "d1" || hint.Evaluation != 1 {
+		t.Errorf("coup gagnant attendu en d1, obtenu %+v", hint)
+	}
+}
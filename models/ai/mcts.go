@@ -0,0 +1,220 @@
+package ai
+
+import (
+	"context"
+	"math"
+	"math/bits"
+	"math/rand"
+	"quarto/models/game"
+	"time"
+)
+
+// Recherche arborescente Monte-Carlo (UCT). Chaque itération descend l'arbre en choisissant l'enfant qui maximise
+// la borne UCT, ajoute un nœud, termine la partie par une partie aléatoire (playout) et remonte son résultat. Les
+// coups considérés, dans l'arbre comme dans les playouts, sont ceux de la recherche minimax : une victoire immédiate
+// est toujours jouée, et une pièce gagnante n'est donnée à l'adversaire que faute d'autre choix. L'arbre est borné :
+// une fois MaxNodes nœuds créés, les itérations suivantes ne font plus que des playouts depuis les feuilles atteintes.
+
+// MCTSExploration est la constante d'exploration UCT des nouveaux moteurs MCTS
+var MCTSExploration = math.Sqrt2
+
+// MCTSMaxNodes est le nombre maximal de nœuds de l'arbre des nouveaux moteurs MCTS
+var MCTSMaxNodes = DefaultMCTSMaxNodes
+
+// DefaultMCTSMaxNodes borne la mémoire d'une recherche à quelques dizaines de mégaoctets
+const DefaultMCTSMaxNodes = 100000
+
+// DefaultMCTSIterations est le budget d'itérations d'un moteur MCTS sans limite de temps ni d'itérations
+const DefaultMCTSIterations = 20000
+
+// mctsCheckInterval est le nombre d'itérations entre deux vérifications de l'horloge et du contexte
+const mctsCheckInterval = 16
+
+// MCTS est un moteur de recherche arborescente Monte-Carlo
+type MCTS struct {
+	Exploration float64       // Constante d'exploration UCT
+	TimeLimit   time.Duration // Durée maximale d'une recherche (0 = sans limite)
+	Iterations  int           // Nombre maximal d'itérations (0 = sans limite, DefaultMCTSIterations sans limite de temps)
+	MaxNodes    int           // Nombre maximal de nœuds de l'arbre (0 = sans limite)
+	Rand        *rand.Rand    // Générateur des playouts (nil = générateur initialisé à chaque recherche)
+}
+
+// NewMCTS crée un moteur MCTS
+func NewMCTS() *MCTS {
+	return &MCTS{Exploration: MCTSExploration, MaxNodes: MCTSMaxNodes}
+}
+
+// mctsNode est un nœud de l'arbre, atteint par move depuis son parent
+type mctsNode struct {
+	move     bitMove
+	player   int // Joueur qui a joué move : 1 ou -1
+	parent   *mctsNode
+	children []*mctsNode
+	untried  []bitMove
+	visits   int
+	reward   float64 // Somme des résultats des playouts pour player : 1 par victoire, 0,5 par nulle
+}
+
+// mctsMoves retourne les coups considérés dans une position en cours
+func mctsMoves(b *Bitboard) []bitMove {
+	if move, ok := b.winningMove(); ok {
+		return []bitMove{move}
+	}
+	return b.appendSafeMoves(nil)
+}
+
+// mover retourne le joueur qui place la pièce en main : 1 ou -1
+func mover(b *Bitboard) int {
+	if bits.OnesCount16(b.Available)%2 == 0 {
+		return 1
+	}
+	return -1
+}
+
+// uct retourne la borne UCT d'un enfant
+func (m *MCTS) uct(node *mctsNode, logParentVisits float64) float64 {
+	return node.reward/float64(node.visits) + m.Exploration*math.Sqrt(logParentVisits/float64(node.visits))
+}
+
+// Search effectue une recherche MCTS
+func (m *MCTS) Search(state GameState) SearchResult {
+	return m.SearchContext(context.Background(), state)
+}
+
+// SearchContext effectue une recherche MCTS interrompue par l'annulation du contexte, le temps imparti ou le budget
+// d'itérations. Le meilleur coup est le plus visité ; le score, du point de vue du joueur 1, reflète son taux de
+// victoire sans jamais atteindre celui d'une victoire forcée.
+func (m *MCTS) SearchContext(ctx context.Context, state GameState) SearchResult {
+	result := SearchResult{BestMoves: []AIMove{}}
+	rng := m.Rand
+	if rng == nil {
+		rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+
+	// Toutes les pièces se valent au premier coup (voir symmetry.go)
+	if len(state.AvailablePieces) == 16 {
+		result.BestMoves = []AIMove{{SelectedPiece: state.AvailablePieces[rng.Intn(len(state.AvailablePieces))]}}
+		return result
+	}
+	if state.SelectedPiece == game.PieceEmpty || state.IsGameOver {
+		return result
+	}
+
+	root := NewBitboard(state)
+	tree, iterations := m.grow(ctx, root, rng)
+	result.Nodes = iterations
+
+	// Suite des coups les plus visités
+	var pv []bitMove
+	for node := tree.mostVisited(); node != nil; node = node.mostVisited() {
+		pv = append(pv, node.move)
+	}
+	if len(pv) == 0 {
+		return result
+	}
+
+	best := tree.mostVisited()
+	rate := best.reward / float64(best.visits)
+	result.Score = int(math.Round((2*rate-1)*maxHeuristicScore)) * best.player
+	result.BestMoves = root.aiMoves(pv)
+	result.Depth = len(pv)
+	return result
+}
+
+// mostVisited retourne l'enfant le plus visité, nil pour une feuille
+func (node *mctsNode) mostVisited() *mctsNode {
+	var best *mctsNode
+	for _, child := range node.children {
+		if best == nil || child.visits > best.visits {
+			best = child
+		}
+	}
+	return best
+}
+
+// grow construit l'arbre de la position jusqu'à l'épuisement du budget et retourne le nombre d'itérations menées
+func (m *MCTS) grow(ctx context.Context, root Bitboard, rng *rand.Rand) (*mctsNode, int) {
+	iterations := m.Iterations
+	if iterations == 0 && m.TimeLimit == 0 {
+		iterations = DefaultMCTSIterations
+	}
+	var deadline time.Time
+	if m.TimeLimit > 0 {
+		deadline = time.Now().Add(m.TimeLimit)
+	}
+
+	tree := &mctsNode{untried: root.uniqueMoves(mctsMoves(&root))}
+	nodes := 1
+	i := 0
+	for ; iterations == 0 || i < iterations; i++ {
+		// La première itération est toujours menée, pour avoir un coup à jouer
+		if i > 0 && i%mctsCheckInterval == 0 && (ctx.Err() != nil || (!deadline.IsZero() && time.Now().After(deadline))) {
+			break
+		}
+		if m.iterate(tree, root, rng, m.MaxNodes == 0 || nodes < m.MaxNodes) {
+			nodes++
+		}
+	}
+	return tree, i
+}
+
+// iterate mène une itération depuis la racine, en ajoutant un nœud à l'arbre si expand est vrai ; retourne vrai si
+// un nœud a été ajouté
+func (m *MCTS) iterate(tree *mctsNode, root Bitboard, rng *rand.Rand, expand bool) bool {
+	// Sélection
+	node, b := tree, root
+	for len(node.untried) == 0 && len(node.children) > 0 {
+		logVisits := math.Log(float64(node.visits))
+		best := node.children[0]
+		bestValue := m.uct(best, logVisits)
+		for _, child := range node.children[1:] {
+			if value := m.uct(child, logVisits); value > bestValue {
+				best, bestValue = child, value
+			}
+		}
+		node = best
+		b = b.Play(node.move)
+	}
+
+	// Expansion
+	expanded := expand && len(node.untried) > 0
+	if expanded {
+		i := rng.Intn(len(node.untried))
+		move := node.untried[i]
+		node.untried[i] = node.untried[len(node.untried)-1]
+		node.untried = node.untried[:len(node.untried)-1]
+
+		child := &mctsNode{move: move, player: mover(&b), parent: node}
+		b = b.Play(move)
+		if !b.IsGameOver {
+			child.untried = mctsMoves(&b)
+		}
+		node.children = append(node.children, child)
+		node = child
+	}
+
+	// Simulation, puis rétropropagation
+	winner := playout(b, rng)
+	for ; node != nil; node = node.parent {
+		node.visits++
+		node.reward += float64(1+node.player*winner) / 2
+	}
+	return expanded
+}
+
+// playout termine la partie au hasard parmi les coups considérés et retourne le gagnant (1, -1 ou 0)
+func playout(b Bitboard, rng *rand.Rand) int {
+	var buffer [maxMoves]bitMove
+	for !b.IsGameOver {
+		if move, ok := b.winningMove(); ok {
+			b = b.Play(move)
+			continue
+		}
+		moves := b.appendSafeMoves(buffer[:0])
+		if len(moves) == 0 {
+			return 0 // Plus aucun coup possible : match nul, comme dans minimax
+		}
+		b = b.Play(moves[rng.Intn(len(moves))])
+	}
+	return b.Winner
+}
@@ -0,0 +1,93 @@
+package ai
+
+import (
+	"context"
+	"math/rand"
+	"quarto/models/game"
+	"testing"
+	"time"
+)
+
+func TestMCTSImmediateWin(t *testing.T) {
+	mcts := NewMCTS()
+	mcts.Iterations = 200
+	mcts.Rand = rand.New(rand.NewSource(11))
+
+	// La pièce 8 complète la première rangée en d1
+	result := mcts.Search(threatState(8, 3, 4, 12))
+	if len(result.BestMoves) == 0 || result.BestMoves[0].Move.Position != (game.Position{Row: 0, Col: 3}) {
+		t.Fatalf("expected the winning placement, got %+v", result.BestMoves)
+	}
+	// Le joueur 2 est au trait
+	if result.Score >= 0 || result.Score <= LOSS_SCORE {
+		t.Errorf("expected a non-terminal score favouring player 2, got %d", result.Score)
+	}
+}
+
+func TestMCTSSafePiece(t *testing.T) {
+	mcts := NewMCTS()
+	mcts.Iterations = 500
+	mcts.Rand = rand.New(rand.NewSource(12))
+
+	state := threatState(15, 3, 4, 5, 6, 12, 13)
+	result := mcts.Search(state)
+	if len(result.BestMoves) == 0 {
+		t.Fatal("expected a move")
+	}
+	child := NewBitboard(state.ApplyMove(result.BestMoves[0]))
+	if engine := NewEngine(1); engine.heuristic(&child) == engine.Weights.WinningPiece {
+		t.Errorf("MCTS gave away a winning piece: %+v", result.BestMoves[0])
+	}
+}
+
+func TestMCTSBudget(t *testing.T) {
+	mcts := NewMCTS()
+	mcts.Iterations = 100
+	if result := mcts.Search(openingState()); result.Nodes != 100 || len(result.BestMoves) == 0 {
+		t.Errorf("expected 100 iterations and a move, got %d iterations", result.Nodes)
+	}
+
+	mcts = NewMCTS()
+	mcts.TimeLimit = 50 * time.Millisecond
+	start := time.Now()
+	if result := mcts.Search(openingState()); len(result.BestMoves) == 0 || time.Since(start) > time.Second {
+		t.Errorf("search took %v with a limit of %v", time.Since(start), mcts.TimeLimit)
+	}
+}
+
+func TestMCTSMaxNodes(t *testing.T) {
+	mcts := NewMCTS()
+	mcts.Iterations = 2000
+	mcts.MaxNodes = 100
+	tree, iterations := mcts.grow(context.Background(), NewBitboard(openingState()), rand.New(rand.NewSource(13)))
+	if iterations != 2000 || tree.size() != 100 {
+		t.Errorf("expected 2000 iterations on a tree of 100 nodes, got %d iterations and %d nodes", iterations, tree.size())
+	}
+	if tree.visits != 2000 {
+		t.Errorf("every iteration should reach the root, got %d visits", tree.visits)
+	}
+}
+
+// size retourne le nombre de nœuds de l'arbre
+func (node *mctsNode) size() int {
+	size := 1
+	for _, child := range node.children {
+		size += child.size()
+	}
+	return size
+}
+
+func TestNewSearcher(t *testing.T) {
+	for _, kind := range []string{"", SearcherMinimax, SearcherMCTS} {
+		searcher, err := NewSearcher(kind, 2, 0, 200)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result := searcher.Search(openingState()); len(result.BestMoves) == 0 {
+			t.Errorf("%q: expected a move", kind)
+		}
+	}
+	if _, err := NewSearcher("random", 2, 0, 0); err == nil {
+		t.Error("unknown engines should be rejected")
+	}
+}
@@ -0,0 +1,103 @@
+package ai
+
+// Ordonnancement des coups. L'élagage alpha-beta coupe d'autant plus tôt que les meilleurs coups sont essayés en
+// premier : le coup enregistré dans la table de transposition (souvent celui de l'itération précédente) passe
+// d'abord, puis les coups qui ont provoqué une coupure au même ply (killers), puis ceux qui en ont provoqué ailleurs
+// dans l'arbre, pondérés par la profondeur restante (historique).
+
+// historyLimit borne les scores de l'historique, divisés par deux lorsqu'il est atteint
+const historyLimit = 1 << 24
+
+// Priorités du coup de la table de transposition et des killers, au-dessus de tout score d'historique
+const (
+	ttMovePriority = 1 << 30
+	killerPriority = 1 << 28
+)
+
+// noMove est un coup qui n'est jamais généré
+var noMove = bitMove{square: -1, piece: -1}
+
+// ordering contient les heuristiques d'ordonnancement d'un worker
+type ordering struct {
+	killers [PieceCount + 1][2]bitMove
+	history [16][PieceCount + 1]int32 // Indexé par case et pièce donnée + 1 (aucune pièce en fin de partie)
+}
+
+// reset oublie les coupures enregistrées
+func (o *ordering) reset() {
+	for ply := range o.killers {
+		o.killers[ply] = [2]bitMove{noMove, noMove}
+	}
+	o.history = [16][PieceCount + 1]int32{}
+}
+
+// prioritize attribue une priorité à chaque coup
+func (o *ordering) prioritize(moves []bitMove, priorities []int32, ttMove bitMove, ply int) {
+	killers := &o.killers[ply]
+	for i, move := range moves {
+		switch move {
+		case ttMove:
+			priorities[i] = ttMovePriority
+		case killers[0]:
+			priorities[i] = killerPriority + 1
+		case killers[1]:
+			priorities[i] = killerPriority
+		default:
+			priorities[i] = o.history[move.square][move.piece+1]
+		}
+	}
+}
+
+// cutoff enregistre un coup qui a provoqué une coupure
+func (o *ordering) cutoff(move bitMove, depth, ply int) {
+	if killers := &o.killers[ply]; killers[0] != move {
+		killers[1], killers[0] = killers[0], move
+	}
+
+	history := &o.history[move.square][move.piece+1]
+	if *history += int32(depth * depth); *history >= historyLimit {
+		for square := range o.history {
+			for piece := range o.history[square] {
+				o.history[square][piece] /= 2
+			}
+		}
+	}
+}
+
+// selectMove place en position i le coup restant de plus haute priorité ; le tri complet serait inutile quand une
+// coupure survient dès les premiers coups
+func selectMove(moves []bitMove, priorities []int32, i int) {
+	best := i
+	for j := i + 1; j < len(moves); j++ {
+		if priorities[j] > priorities[best] {
+			best = j
+		}
+	}
+	moves[i], moves[best] = moves[best], moves[i]
+	priorities[i], priorities[best] = priorities[best], priorities[i]
+}
+
+// generateMoves ajoute les coups à chercher d'une position sans placement gagnant : ceux qui donnent une pièce
+// gagnante à l'adversaire sont écartés, sauf en recherche simple
+func (e *Engine) generateMoves(node *Bitboard, moves []bitMove) []bitMove {
+	if e.plain {
+		return node.appendMoves(moves)
+	}
+	return node.appendSafeMoves(moves)
+}
+
+// immediateWin retourne le coup qui gagne immédiatement, s'il existe, sans attendre qu'il soit généré et cherché
+func (e *Engine) immediateWin(node *Bitboard) (bitMove, bool) {
+	if e.plain {
+		return bitMove{}, false
+	}
+	return node.winningMove()
+}
+
+// decisiveScore retourne le score d'une victoire du joueur au trait
+func decisiveScore(isMaximizing bool) int {
+	if isMaximizing {
+		return WIN_SCORE
+	}
+	return LOSS_SCORE
+}
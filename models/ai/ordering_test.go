@@ -0,0 +1,66 @@
+package ai
+
+import (
+	"math/rand"
+	"quarto/models/game"
+	"testing"
+)
+
+func TestOrderingReducesNodes(t *testing.T) {
+	rng := rand.New(rand.NewSource(6))
+	for _, variant := range []string{game.VariantStandard, game.VariantSquares} {
+		var plainNodes, orderedNodes int
+		for range 20 {
+			// Fin de partie résolue entièrement : les deux recherches doivent trouver la même valeur
+			state := randomState(rng, variant, 8)
+			if state.IsGameOver {
+				continue
+			}
+
+			plain := NewEngine(DefaultMaxDepth)
+			plain.plain = true
+			expected := plain.Search(state)
+			result := NewEngine(DefaultMaxDepth).Search(state)
+			if result.Score != expected.Score || len(result.BestMoves) == 0 {
+				t.Fatalf("%s: ordered search found score %d instead of %d", variant, result.Score, expected.Score)
+			}
+
+			// Le coup retenu doit atteindre la valeur annoncée
+			child := NewBitboard(state.ApplyMove(result.BestMoves[0]))
+			if child.IsGameOver {
+				if score := NewEngine(1).evaluate(&child); score != result.Score {
+					t.Fatalf("%s: best move ends the game with %d instead of %d", variant, score, result.Score)
+				}
+			} else if score := NewEngine(DefaultMaxDepth).Search(child.GameState()).Score; score != result.Score {
+				t.Fatalf("%s: best move leads to %d instead of %d", variant, score, result.Score)
+			}
+
+			plainNodes += expected.Nodes
+			orderedNodes += result.Nodes
+		}
+		t.Logf("%s: %d nodes instead of %d", variant, orderedNodes, plainNodes)
+		if orderedNodes >= plainNodes {
+			t.Errorf("%s: ordered search visited %d nodes, plain search %d", variant, orderedNodes, plainNodes)
+		}
+	}
+}
+
+func TestSafeMoves(t *testing.T) {
+	// Sauf si la pièce 15 est placée en d1, les pièces petites ou blanches complètent ensuite la première rangée
+	b := NewBitboard(threatState(15, 3, 4, 5, 6, 12, 13))
+	moves := b.appendSafeMoves(nil)
+	for _, move := range moves {
+		if move.square != 3 && move.piece != 12 && move.piece != 13 {
+			t.Errorf("move %+v gives away a winning piece", move)
+		}
+	}
+	if expected := 12*2 + 6; len(moves) != expected {
+		t.Errorf("expected %d moves, got %d", expected, len(moves))
+	}
+
+	// Placée en d1, la pièce 8 complète la première rangée
+	b = NewBitboard(threatState(8, 3, 4))
+	if move, ok := b.winningMove(); !ok || move.square != 3 {
+		t.Errorf("expected the winning move on square 3, got %+v (%v)", move, ok)
+	}
+}
@@ -0,0 +1,187 @@
+package ai
+
+import (
+	"context"
+	"math"
+	"quarto/models/ai/stats"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Recherche parallèle. Par défaut, elle suit le schéma Lazy SMP : des workers auxiliaires parcourent le même arbre
+// que le worker principal, dans un ordre différent, et partagent avec lui la table de transposition ; le résultat
+// est celui du worker principal, qui profite des positions déjà évaluées par les autres. En mode déterministe, les
+// coups de la racine sont répartis entre les workers et évalués chacun avec une table privée, si bien que le
+// résultat ne dépend ni du nombre de workers ni de l'ordonnancement (en l'absence de limite de temps ou de nœuds).
+
+// search contient l'état partagé par les workers d'une recherche
+type search struct {
+	engine   *Engine
+	ctx      context.Context
+	deadline time.Time
+	nodes    atomic.Int64 // Nœuds visités, reportés par lots par les workers
+	stopped  atomic.Bool  // Recherche terminée ou budget épuisé : les itérations en cours sont abandonnées
+	helpers  sync.WaitGroup
+}
+
+// worker parcourt l'arbre pour une recherche ; chaque goroutine a le sien
+type worker struct {
+	search    *search
+	tt        *TranspositionTable
+	stats     *stats.PerformanceStats // Statistiques, collectées par le seul worker principal
+	id        int
+	pending   int64 // Nœuds visités pas encore reportés dans le compteur partagé
+	abortable bool  // La première itération du worker principal est toujours menée à son terme
+	aborted   bool
+	order     ordering
+}
+
+// threads retourne le nombre de workers de la recherche
+func (e *Engine) threads() int {
+	return max(e.Threads, 1)
+}
+
+// newSearch démarre une recherche avec le budget du moteur
+func (e *Engine) newSearch(ctx context.Context) *search {
+	s := &search{engine: e, ctx: ctx}
+	if e.TimeLimit > 0 {
+		s.deadline = time.Now().Add(e.TimeLimit)
+	}
+	return s
+}
+
+// newWorker crée un worker utilisant la table de transposition donnée
+func (s *search) newWorker(id int, tt *TranspositionTable, perfStats *stats.PerformanceStats) *worker {
+	w := &worker{search: s, tt: tt, stats: perfStats, id: id}
+	w.order.reset()
+	return w
+}
+
+// expired indique si le contexte est annulé ou le temps imparti écoulé
+func (s *search) expired() bool {
+	return s.ctx.Err() != nil || (!s.deadline.IsZero() && time.Now().After(s.deadline))
+}
+
+// done indique si la recherche doit s'arrêter avant l'itération suivante
+func (s *search) done() bool {
+	return s.stopped.Load() || s.expired()
+}
+
+// stop arrête les workers auxiliaires et attend leur fin
+func (s *search) stop() {
+	s.stopped.Store(true)
+	s.helpers.Wait()
+}
+
+// shouldStop compte le nœud visité et indique si l'itération en cours doit être abandonnée
+func (w *worker) shouldStop() bool {
+	w.pending++
+	if w.aborted || !w.abortable {
+		return w.aborted
+	}
+
+	s := w.search
+	if s.stopped.Load() {
+		w.aborted = true
+	} else if limit := s.engine.NodeLimit; limit > 0 && s.nodes.Load()+w.pending >= int64(limit) {
+		// Avec plusieurs workers, la limite peut être dépassée des nœuds qu'ils n'ont pas encore reportés
+		w.aborted = true
+	} else if w.pending >= stopCheckInterval {
+		// Consulter l'horloge et le contexte à chaque nœud coûterait trop cher
+		w.flush()
+		w.aborted = s.expired()
+	}
+	if w.aborted {
+		s.stopped.Store(true)
+	}
+	return w.aborted
+}
+
+// flush reporte les nœuds visités dans le compteur partagé
+func (w *worker) flush() {
+	w.search.nodes.Add(w.pending)
+	w.pending = 0
+}
+
+// help fait travailler un worker auxiliaire jusqu'à la fin de la recherche ; un sur deux commence une profondeur plus
+// loin, pour que tous ne parcourent pas les mêmes nœuds au même moment
+func (s *search) help(id int, root *Bitboard, maxDepth int, isMaximizing bool) {
+	defer s.helpers.Done()
+	w := s.newWorker(id, s.engine.TT, nil)
+	w.abortable = true
+	for depth := 1 + id%2; depth <= maxDepth && !s.stopped.Load(); depth++ {
+		w.minimax(root, depth, 0, LOSS_SCORE-1, WIN_SCORE+1, isMaximizing)
+		if w.aborted {
+			break
+		}
+	}
+	w.flush()
+}
+
+// splitRoot mène une itération du mode déterministe : les coups de la racine sont distribués aux workers, qui les
+// évaluent avec une fenêtre complète, une table privée et un ordonnancement remis à zéro pour chaque coup. Retourne
+// aussi si l'itération a été abandonnée.
+func (s *search) splitRoot(root *Bitboard, depth int, isMaximizing bool, abortable bool) (int, []bitMove, bool) {
+	if move, ok := s.engine.immediateWin(root); ok {
+		return decisiveScore(isMaximizing), []bitMove{move}, false
+	}
+
+	var buffer [maxMoves]bitMove
+	moves := root.uniqueMoves(s.engine.generateMoves(root, buffer[:0]))
+	scores := make([]int, len(moves))
+	continuations := make([][]bitMove, len(moves))
+
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	workers := make([]*worker, s.engine.threads())
+	for id, tt := range s.engine.privateTables(len(workers)) {
+		w := s.newWorker(id, tt, nil)
+		w.abortable = abortable
+		workers[id] = w
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer w.flush()
+			for i := int(next.Add(1)) - 1; i < len(moves) && !w.aborted; i = int(next.Add(1)) - 1 {
+				w.tt.Clear()
+				w.order.reset()
+				child := root.Play(moves[i])
+				scores[i], continuations[i] = w.minimax(&child, depth-1, 1, LOSS_SCORE-1, WIN_SCORE+1, !isMaximizing)
+			}
+		}()
+	}
+	wg.Wait()
+	for _, w := range workers {
+		if w.aborted {
+			return 0, nil, true
+		}
+	}
+
+	// Choisir le meilleur coup dans l'ordre de génération, comme le ferait une recherche séquentielle
+	bestScore := math.MaxInt32
+	if isMaximizing {
+		bestScore = math.MinInt32
+	}
+	var bestMoves []bitMove
+	for i, move := range moves {
+		child := root.Play(move)
+		if improves(isMaximizing, scores[i], bestScore, continuations[i], bestMoves, &child) {
+			bestScore = scores[i]
+			bestMoves = append([]bitMove{move}, continuations[i]...)
+		}
+	}
+	if bestMoves == nil {
+		return DRAW_SCORE, nil, false
+	}
+	return bestScore, bestMoves, false
+}
+
+// privateTables retourne les tables privées des workers du mode déterministe, allouées à la première utilisation
+func (e *Engine) privateTables(count int) []*TranspositionTable {
+	for len(e.tables) < count {
+		e.tables = append(e.tables, NewTranspositionTable(privateTTSizeMB))
+	}
+	return e.tables[:count]
+}
@@ -0,0 +1,68 @@
+package ai
+
+import (
+	"math/rand"
+	"quarto/models/game"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParallelSearch(t *testing.T) {
+	rng := rand.New(rand.NewSource(4))
+	for range 10 {
+		// Fin de partie résolue entièrement : le score ne dépend pas du nombre de workers
+		state := randomState(rng, game.VariantStandard, 9)
+		if state.IsGameOver {
+			continue
+		}
+
+		sequential := NewEngine(DefaultMaxDepth).Search(state)
+		engine := NewEngine(DefaultMaxDepth)
+		engine.Threads = 4
+		parallel := engine.Search(state)
+
+		if parallel.Score != sequential.Score || len(parallel.BestMoves) == 0 {
+			t.Fatalf("parallel search found score %d instead of %d", parallel.Score, sequential.Score)
+		}
+		if parallel.Nodes == 0 {
+			t.Error("the nodes of the workers should be counted")
+		}
+	}
+}
+
+func TestParallelSearchTimeLimit(t *testing.T) {
+	engine := NewEngine(DefaultMaxDepth)
+	engine.Threads = 4
+	engine.TimeLimit = 50 * time.Millisecond
+
+	start := time.Now()
+	result := engine.Search(openingState())
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("search took %v with a limit of %v", elapsed, engine.TimeLimit)
+	}
+	if result.Depth < 1 || len(result.BestMoves) == 0 {
+		t.Errorf("expected the result of a completed iteration, got depth %d", result.Depth)
+	}
+}
+
+func TestDeterministicSearch(t *testing.T) {
+	search := func(threads int) SearchResult {
+		engine := NewEngine(3)
+		engine.Threads = threads
+		engine.Deterministic = true
+		result := engine.Search(openingState())
+		result.Nodes = 0 // Seul le nombre de nœuds peut varier, selon le partage des coups entre workers
+		return result
+	}
+
+	expected := search(1)
+	if expected.Depth != 3 || len(expected.BestMoves) == 0 {
+		t.Fatalf("expected a complete search, got depth %d", expected.Depth)
+	}
+	for _, threads := range []int{1, 2, 4} {
+		if result := search(threads); !reflect.DeepEqual(result, expected) {
+			t.Errorf("%d workers: got %+v, expected %+v", threads, result, expected)
+		}
+	}
+}
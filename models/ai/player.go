@@ -0,0 +1,43 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Searcher est un moteur qui cherche le meilleur coup d'une position : Engine (minimax) ou MCTS. Il est choisi par
+// /ai/solve, cmd/match et les options des parties contre le bot.
+type Searcher interface {
+	Search(state GameState) SearchResult
+	SearchContext(ctx context.Context, state GameState) SearchResult
+}
+
+// Moteurs disponibles
+const (
+	SearcherMinimax = "minimax"
+	SearcherMCTS    = "mcts"
+)
+
+// NewSearcher crée un moteur du type donné (vide = minimax). La profondeur ne concerne que minimax ; pour MCTS, la
+// limite de nœuds borne le nombre d'itérations.
+func NewSearcher(kind string, maxDepth int, timeLimit time.Duration, nodeLimit int) (Searcher, error) {
+	switch kind {
+	case "", SearcherMinimax:
+		engine := NewEngine(maxDepth)
+		engine.TimeLimit = timeLimit
+		engine.NodeLimit = nodeLimit
+		return engine, nil
+	case SearcherMCTS:
+		mcts := NewMCTS()
+		mcts.TimeLimit = timeLimit
+		mcts.Iterations = nodeLimit
+		return mcts, nil
+	}
+	return nil, fmt.Errorf("moteur inconnu : %q", kind)
+}
+
+var (
+	_ Searcher = (*Engine)(nil)
+	_ Searcher = (*MCTS)(nil)
+)
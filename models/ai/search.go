@@ -1,10 +1,13 @@
 package ai
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"quarto/models/ai/stats"
 	"quarto/models/game"
+	"slices"
+	"strconv"
 	"time"
 )
 
@@ -12,17 +15,25 @@ import (
 type SearchResult struct {
 	BestMoves []AIMove
 	Score     int
-	Depth     int
+	Depth     int // Profondeur de la dernière itération terminée
+	Nodes     int // Nombre de nœuds visités, toutes itérations confondues
 	Stats     *stats.PerformanceStats
 }
 
 // Search effectue une recherche minimax avec élagage alpha-beta
 func (e *Engine) Search(state GameState) SearchResult {
-	return e.SearchWithStats(state, nil)
+	return e.SearchContext(context.Background(), state)
 }
 
-// SearchWithStats effectue une recherche avec suivi des statistiques
-func (e *Engine) SearchWithStats(state GameState, perfStats *stats.PerformanceStats) SearchResult {
+// SearchContext effectue une recherche interrompue par l'annulation du contexte
+func (e *Engine) SearchContext(ctx context.Context, state GameState) SearchResult {
+	return e.SearchWithStats(ctx, state, nil)
+}
+
+// SearchWithStats effectue une recherche par approfondissement itératif avec suivi des statistiques. Chaque itération
+// augmente la profondeur d'un demi-coup jusqu'à MaxDepth, l'épuisement du budget de temps ou de nœuds, ou l'annulation
+// du contexte ; le résultat est celui de la dernière itération terminée (la première l'est toujours).
+func (e *Engine) SearchWithStats(ctx context.Context, state GameState, perfStats *stats.PerformanceStats) SearchResult {
 
 	result := SearchResult{
 		Score:     0,
@@ -33,11 +44,13 @@ func (e *Engine) SearchWithStats(state GameState, perfStats *stats.PerformanceSt
 
 	searchStart := time.Now()
 	if len(state.AvailablePieces) == 16 {
-		result.BestMoves = []AIMove{
-			{
-				SelectedPiece: state.AvailablePieces[0], // Sélectionne la première pièce disponible
-			},
+		// Toutes les pièces se valent au premier coup (voir symmetry.go) : avec un livre d'ouvertures, la pièce est
+		// tirée au hasard pour varier les parties
+		piece := state.AvailablePieces[0]
+		if e.Book != nil {
+			piece = state.AvailablePieces[e.bookIntn(len(state.AvailablePieces))]
 		}
+		result.BestMoves = []AIMove{{SelectedPiece: piece}}
 		return result
 	}
 
@@ -45,50 +58,135 @@ func (e *Engine) SearchWithStats(state GameState, perfStats *stats.PerformanceSt
 		return result
 	}
 
-	// Vider la table de transposition pour chaque nouvelle recherche
-	e.TT.Clear()
+	// Une position du livre d'ouvertures ou une fin de partie résolue n'a pas besoin d'être cherchée
+	if bookResult, ok := e.probeBook(state); ok {
+		bookResult.Stats = perfStats
+		return bookResult
+	}
+	if tablebaseResult, ok := e.probeTablebase(state); ok {
+		tablebaseResult.Stats = perfStats
+		return tablebaseResult
+	}
+
+	// Vider la table de transposition, sauf si elle est conservée entre les recherches de la partie
+	if e.KeepTT {
+		e.TT.NewSearch()
+	} else {
+		e.TT.Clear()
+	}
+	search := e.newSearch(ctx)
+
+	// La recherche travaille sur un Bitboard, dont la clé est recalculée : l'état a pu être construit à la main
+	root := NewBitboard(state)
 
 	isMaximizing := len(state.AvailablePieces)%2 == 0 // Maximiser si c'est le tour du joueur 1
 	fmt.Printf("Starting search: Maximizing=%v, AvailablePieces=%d, SelectedPiece=%d\n", isMaximizing, len(state.AvailablePieces), state.SelectedPiece)
 
-	// Recherche avec élagage alpha-beta et table de transposition
-	score, bestMoves := e.minimax(state, e.MaxDepth, LOSS_SCORE-1, WIN_SCORE+1, isMaximizing, perfStats)
+	// Au-delà du nombre de placements restants, une itération plus profonde n'apporte rien
+	maxDepth := min(e.MaxDepth, len(state.AvailablePieces)+1)
+
+	// Les workers auxiliaires remplissent la table partagée en parallèle du worker principal
+	main := search.newWorker(0, e.TT, perfStats)
+	if !e.Deterministic {
+		for id := 1; id < e.threads(); id++ {
+			search.helpers.Add(1)
+			go search.help(id, &root, maxDepth, isMaximizing)
+		}
+	}
+
+	for depth := 1; depth <= maxDepth; depth++ {
+		abortable := depth > 1
+		if abortable && search.done() {
+			break
+		}
+
+		// Recherche avec élagage alpha-beta et table de transposition
+		var score int
+		var bestMoves []bitMove
+		var aborted bool
+		if e.Deterministic {
+			score, bestMoves, aborted = search.splitRoot(&root, depth, isMaximizing, abortable)
+		} else {
+			main.abortable = abortable
+			score, bestMoves = main.minimax(&root, depth, 0, LOSS_SCORE-1, WIN_SCORE+1, isMaximizing)
+			aborted = main.aborted
+		}
+		if aborted {
+			break
+		}
+
+		result.Score = score
+		result.BestMoves = root.aiMoves(bestMoves)
+		result.Depth = depth
+
+		// Une victoire ou une défaite forcée ne changera plus
+		if score == WIN_SCORE || score == LOSS_SCORE {
+			break
+		}
+	}
+	main.flush()
+	search.stop()
+	result.Nodes = int(search.nodes.Load())
 
 	if perfStats != nil {
 		searchDuration := time.Since(searchStart)
-		stateHash := e.hashGameState(state)
-		perfStats.RecordOperation("total_search", searchDuration, stateHash)
+		perfStats.RecordOperation("total_search", searchDuration, hashString(root.Hash))
 	}
 
-	result.Score = score
-	result.BestMoves = bestMoves
-	result.Depth = e.MaxDepth
-
 	return result
 }
 
-// minimax implémente l'algorithme minimax avec élagage alpha-beta, table de transposition et retourne la continuation
-func (e *Engine) minimax(node GameState, depth int, alpha, beta int, isMaximizing bool, perfStats *stats.PerformanceStats) (int, []AIMove) {
-	nodeStart := time.Now()
-	stateHash := e.hashGameState(node)
+// tableKey retourne la clé du nœud dans la table de transposition. Au-delà de canonicalMinDepth, c'est la clé de sa
+// forme canonique, partagée par les positions équivalentes, et la symétrie permet de convertir les continuations ;
+// plus près des feuilles, la canonisation coûterait plus cher que la recherche qu'elle épargne.
+func tableKey(node *Bitboard, depth int) (uint64, *Symmetry) {
+	if depth < canonicalMinDepth {
+		return node.Hash, nil
+	}
+	key, symmetry := node.canonical()
+	return key, &symmetry
+}
 
-	// Enregistrer la visite du nœud
+// minimax implémente l'algorithme minimax avec élagage alpha-beta, table de transposition et ordonnancement des coups
+// (voir ordering.go), et retourne la continuation ; ply est la distance à la racine
+func (w *worker) minimax(node *Bitboard, depth, ply int, alpha, beta int, isMaximizing bool) (int, []bitMove) {
+	if w.shouldStop() {
+		return 0, nil
+	}
+	perfStats := w.stats
+
+	// La clé de Zobrist est tenue à jour par Play, la chaîne n'est construite que pour les statistiques
+	var stateHash string
 	if perfStats != nil {
-		perfStats.RecordOperation("hash", time.Since(nodeStart), stateHash)
+		stateHash = hashString(node.Hash)
+		perfStats.RecordOperation("node_visit", 0, stateHash)
 	}
 
-	// Vérifier la table de transposition
-	if entry, exists := e.TT.Lookup(stateHash, depth, alpha, beta); exists {
+	// Vérifier la table de transposition, dont les continuations sont enregistrées dans l'orientation canonique ; une
+	// entrée inutilisable fournit tout de même le premier coup à essayer
+	key, symmetry := tableKey(node, depth)
+	ttMove := noMove
+	entry, exists := w.tt.Lookup(key, depth, alpha, beta)
+	if exists {
 		if perfStats != nil {
 			perfStats.RecordOperation("tt_hit", 0, stateHash)
 		}
+		if symmetry != nil {
+			return entry.Score, symmetry.bitMoves(entry.BestMoves, true)
+		}
 		return entry.Score, entry.BestMoves
 	}
+	if entry != nil && len(entry.BestMoves) > 0 {
+		ttMove = entry.BestMoves[0]
+		if symmetry != nil {
+			ttMove = symmetry.bitMove(ttMove, true)
+		}
+	}
 
 	// Condition d'arrêt : jeu terminé ou profondeur maximale atteinte
 	if node.IsGameOver || depth == 0 {
 		evalStart := time.Now()
-		score := e.evaluatePosition(node)
+		score := w.search.engine.evaluate(node)
 
 		if perfStats != nil {
 			evalDuration := time.Since(evalStart)
@@ -98,24 +196,51 @@ func (e *Engine) minimax(node GameState, depth int, alpha, beta int, isMaximizin
 				perfStats.RecordOperation("terminal_eval", evalDuration, stateHash)
 			}
 		}
-		e.TT.Store(stateHash, score, depth, EXACT, []AIMove{})
-		return score, []AIMove{}
+		w.tt.Store(key, score, depth, EXACT, nil)
+		return score, nil
+	}
+
+	// Une victoire immédiate n'a pas besoin d'être cherchée, et aucun autre coup ne fait mieux
+	if move, ok := w.search.engine.immediateWin(node); ok {
+		bestMoves := []bitMove{move}
+		stored := bestMoves
+		if symmetry != nil {
+			stored = symmetry.bitMoves(bestMoves, false)
+		}
+		w.tt.Store(key, decisiveScore(isMaximizing), depth, EXACT, stored)
+		return decisiveScore(isMaximizing), bestMoves
 	}
 
+	// Les coups sont générés dans un tampon sur la pile
 	movesStart := time.Now()
-	validMoves := GetValidMoves(node)
+	var buffer [maxMoves]bitMove
+	validMoves := w.search.engine.generateMoves(node, buffer[:0])
+	if ply == 0 {
+		// À la racine, les coups menant à des positions équivalentes ont la même valeur
+		validMoves = node.uniqueMoves(validMoves)
+
+		// Chaque worker auxiliaire commence par des coups différents, pour ne pas doubler le worker principal
+		if k := w.id % len(validMoves); k > 0 {
+			validMoves = slices.Concat(validMoves[k:], validMoves[:k])
+		}
+	}
+	plain := w.search.engine.plain
+	var priorities [maxMoves]int32
+	if !plain {
+		w.order.prioritize(validMoves, priorities[:len(validMoves)], ttMove, ply)
+	}
 	if perfStats != nil {
 		perfStats.RecordOperation("generate_moves", time.Since(movesStart), stateHash)
 	}
 
 	// Si aucun mouvement valide, c'est un match nul
 	if len(validMoves) == 0 {
-		e.TT.Store(stateHash, DRAW_SCORE, depth, EXACT, []AIMove{})
-		return DRAW_SCORE, []AIMove{}
+		w.tt.Store(key, DRAW_SCORE, depth, EXACT, nil)
+		return DRAW_SCORE, nil
 	}
 
-	var bestMoves []AIMove
-	originalAlpha := alpha
+	var bestMoves []bitMove
+	originalAlpha, originalBeta := alpha, beta
 
 	// Initialiser le meilleur score selon le type de joueur
 	var bestScore int
@@ -124,40 +249,33 @@ func (e *Engine) minimax(node GameState, depth int, alpha, beta int, isMaximizin
 	} else {
 		bestScore = math.MaxInt32
 	}
-	for _, move := range validMoves {
+	for i := range validMoves {
+		if !plain {
+			selectMove(validMoves, priorities[:len(validMoves)], i)
+		}
+		move := validMoves[i]
+
 		moveStart := time.Now()
-		newState := node.ApplyMove(move)
+		child := node.Play(move)
 		if perfStats != nil {
-			moveHash := e.hashMove(move, stateHash)
-			perfStats.RecordOperation("apply_move", time.Since(moveStart), moveHash+"-"+stateHash)
+			perfStats.RecordOperation("apply_move", time.Since(moveStart), hashString(child.Hash))
 		}
 
-		score, moves := e.minimax(newState, depth-1, alpha, beta, !isMaximizing, perfStats)
-
-		// Vérifier si ce mouvement améliore le score ou si c'est un meilleur chemin vers la victoire
-		isBetter := (isMaximizing && score > bestScore) || (!isMaximizing && score < bestScore)
-
-		// Prioriser les chemins plus courts vers la victoire à score égal
-		isSameScoreButShorterPath := false
-		if score == bestScore && score != 0 { // Score non nul (victoire ou défaite)
-			currentPathLength := len(moves) + 1 // +1 pour le coup actuel
-			bestPathLength := len(bestMoves)
-
-			// Si c'est une victoire immédiate (continuation vide), c'est toujours prioritaire
-			if len(moves) == 0 && newState.IsGameOver && newState.Winner != 0 {
-				isSameScoreButShorterPath = true
-			} else if currentPathLength < bestPathLength && bestPathLength > 1 {
-				// Chemin plus court vers la même conclusion
-				isSameScoreButShorterPath = true
-			}
+		var score int
+		var moves []bitMove
+		if plain || i == 0 {
+			score, moves = w.minimax(&child, depth-1, ply+1, alpha, beta, !isMaximizing)
+		} else {
+			score, moves = w.scout(&child, depth, ply, alpha, beta, isMaximizing, bestScore)
+		}
+		if w.aborted {
+			// Le résultat d'une itération abandonnée ne doit pas polluer la table de transposition
+			return 0, nil
 		}
 
-		if isBetter || isSameScoreButShorterPath {
+		if improves(isMaximizing, score, bestScore, moves, bestMoves, &child) {
 			bestScore = score
-			bestMoves = []AIMove{move}
-			if len(moves) > 0 {
-				bestMoves = append(bestMoves, moves...)
-			}
+			bestMoves = append(append(make([]bitMove, 0, len(moves)+1), move), moves...)
 		}
 
 		// Mise à jour des bornes alpha-beta
@@ -172,64 +290,84 @@ func (e *Engine) minimax(node GameState, depth int, alpha, beta int, isMaximizin
 			if perfStats != nil {
 				perfStats.RecordOperation("alpha_beta_prune", 0, stateHash)
 			}
+			if !plain {
+				w.order.cutoff(move, depth, ply)
+			}
 			break
 		}
 	}
 
-	// Déterminer le flag pour la table de transposition
+	// Déterminer le flag pour la table de transposition, par rapport à la fenêtre reçue
 	var flag TTFlag
 	if bestScore <= originalAlpha {
 		flag = UPPER_BOUND
-	} else if bestScore >= beta {
+	} else if bestScore >= originalBeta {
 		flag = LOWER_BOUND
 	} else {
 		flag = EXACT
 	}
 
-	e.TT.Store(stateHash, bestScore, depth, flag, bestMoves)
+	stored := bestMoves
+	if symmetry != nil {
+		stored = symmetry.bitMoves(bestMoves, false)
+	}
+	w.tt.Store(key, bestScore, depth, flag, stored)
 	return bestScore, bestMoves
 }
 
-// hashGameState génère un hash pour un état de jeu
-func (e *Engine) hashGameState(state GameState) string {
-	var hash uint64 = 0
-
-	// Hacher le plateau de jeu (4x4 board)
-	for row := 0; row < 4; row++ {
-		for col := 0; col < 4; col++ {
-			piece := state.Board[row][col]
-			if piece != game.PieceEmpty {
-				// Position sur 4 bits (0-15)
-				position := uint64(row*4 + col)
-				// Utiliser directement la valeur de la pièce (plus efficace que d'extraire les caractéristiques)
-				hash ^= uint64(piece) << (position * 4)
-			}
-		}
+// scout cherche un coup après le premier avec une fenêtre nulle, qui établit seulement s'il fait mieux que les
+// précédents (principal variation search). Il est cherché de nouveau avec la fenêtre complète s'il fait mieux, ou
+// s'il égale une victoire ou une défaite déjà trouvée : improves doit alors comparer la longueur des chemins exacts.
+func (w *worker) scout(child *Bitboard, depth, ply int, alpha, beta int, isMaximizing bool, bestScore int) (int, []bitMove) {
+	low, high := alpha, alpha+1
+	if !isMaximizing {
+		low, high = beta-1, beta
+	}
+	score, moves := w.minimax(child, depth-1, ply+1, low, high, !isMaximizing)
+	if w.aborted {
+		return 0, nil
 	}
 
-	// Optimiser la concaténation en évitant sprintf pour la pièce sélectionnée
-	return fmt.Sprintf("%016x-%d", hash, state.SelectedPiece)
+	decisive := score == bestScore && isDecisive(score)
+	if score > alpha && score < beta {
+		return w.minimax(child, depth-1, ply+1, alpha, beta, !isMaximizing)
+	}
+	if decisive && isMaximizing {
+		return w.minimax(child, depth-1, ply+1, bestScore-1, beta, !isMaximizing)
+	}
+	if decisive {
+		return w.minimax(child, depth-1, ply+1, alpha, bestScore+1, !isMaximizing)
+	}
+	return score, moves
 }
 
-// hashMove génère un hash pour un mouvement
-func (e *Engine) hashMove(move AIMove, stateHash string) string {
-	return fmt.Sprintf("%d-%d-%d-%s", move.Move.Piece, move.Move.Position.Row, move.Move.Position.Col, stateHash)
-}
+// improves indique si un coup, de score score et suivi de continuation, est préférable au meilleur coup trouvé
+func improves(isMaximizing bool, score, bestScore int, continuation, best []bitMove, child *Bitboard) bool {
+	if (isMaximizing && score > bestScore) || (!isMaximizing && score < bestScore) {
+		return true
+	}
 
-// evaluatePosition évalue une position de jeu
-func (e *Engine) evaluatePosition(state GameState) int {
-	if state.IsGameOver {
-		switch state.Winner {
-		case 1:
-			return WIN_SCORE
-		case -1:
-			return LOSS_SCORE
-		default:
-			return DRAW_SCORE
-		}
+	// Prioriser les chemins plus courts vers une même victoire ou défaite ; deux scores heuristiques égaux ne sont
+	// pas départagés
+	if score != bestScore || !isDecisive(score) {
+		return false
+	}
+	// Une victoire immédiate (continuation vide) est toujours prioritaire
+	if len(continuation) == 0 && child.IsGameOver && child.Winner != 0 {
+		return true
 	}
+	// Sinon, le chemin le plus court vers la même conclusion (+1 pour le coup lui-même)
+	return len(continuation)+1 < len(best) && len(best) > 1
+}
+
+// isDecisive indique si un score est celui d'une victoire ou d'une défaite certaine, et non une estimation heuristique
+func isDecisive(score int) bool {
+	return score == WIN_SCORE || score == LOSS_SCORE
+}
 
-	return 0
+// hashString formate une clé de Zobrist pour les statistiques
+func hashString(hash uint64) string {
+	return strconv.FormatUint(hash, 16)
 }
 
 // Fonctions utilitaires pour min/max
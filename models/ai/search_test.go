@@ -0,0 +1,63 @@
+package ai
+
+import (
+	"context"
+	"quarto/models/game"
+	"testing"
+	"time"
+)
+
+// openingState retourne une position de début de partie, trop vaste pour être résolue
+func openingState() GameState {
+	state := ConvertHistoryToGameState([]game.Move{
+		{Piece: 0, Position: game.Position{Row: 0, Col: 0}},
+		{Piece: 15, Position: game.Position{Row: 1, Col: 1}},
+	}, game.VariantStandard)
+	state.SelectedPiece = 5
+	state.AvailablePieces = state.AvailablePieces[:0:0]
+	for _, piece := range game.GetAllPieces() {
+		if piece != 0 && piece != 15 && piece != 5 {
+			state.AvailablePieces = append(state.AvailablePieces, piece)
+		}
+	}
+	return state
+}
+
+func TestSearchNodeLimit(t *testing.T) {
+	engine := NewEngine(DefaultMaxDepth)
+	engine.NodeLimit = 5000
+
+	result := engine.Search(openingState())
+	if result.Depth < 1 || result.Depth >= DefaultMaxDepth || len(result.BestMoves) == 0 {
+		t.Fatalf("expected a partial search with a move, got depth %d and %d moves", result.Depth, len(result.BestMoves))
+	}
+	// Seule la première itération peut dépasser le budget
+	if result.Depth > 1 && result.Nodes > engine.NodeLimit {
+		t.Errorf("visited %d nodes with a limit of %d", result.Nodes, engine.NodeLimit)
+	}
+}
+
+func TestSearchTimeLimit(t *testing.T) {
+	engine := NewEngine(DefaultMaxDepth)
+	engine.TimeLimit = 50 * time.Millisecond
+
+	start := time.Now()
+	result := engine.Search(openingState())
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("search took %v with a limit of %v", elapsed, engine.TimeLimit)
+	}
+	if result.Depth < 1 || len(result.BestMoves) == 0 {
+		t.Errorf("expected the result of a completed iteration, got depth %d", result.Depth)
+	}
+}
+
+func TestSearchCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// La première itération est toujours terminée, même si le contexte est annulé
+	result := NewEngine(DefaultMaxDepth).SearchContext(ctx, openingState())
+	if result.Depth != 1 || len(result.BestMoves) == 0 {
+		t.Errorf("expected a depth 1 result, got depth %d with %d moves", result.Depth, len(result.BestMoves))
+	}
+}
@@ -0,0 +1,338 @@
+package ai
+
+import (
+	"quarto/models/game"
+	"slices"
+)
+
+// Symétries de Quarto : une position et ses images par une symétrie du plateau combinée à une permutation et une
+// inversion des caractéristiques des pièces ont la même valeur. Le plateau standard en compte 32 (rotations,
+// réflexions, échange des rangées et colonnes intérieures et extérieures...) ; les variantes à carrés ne
+// conservent que les 8 rotations et réflexions, les seules à préserver les carrés de cases adjacentes.
+
+// boardSymmetry associe à chaque case (rangée*4 + colonne) son image, et inversement
+type boardSymmetry struct {
+	image  [16]int8
+	source [16]int8
+}
+
+// boardSymmetries liste les symétries du plateau standard, les 8 rotations et réflexions en premier
+var boardSymmetries = newBoardSymmetries()
+
+// newBoardSymmetries construit les symétries du plateau
+func newBoardSymmetries() (symmetries []boardSymmetry) {
+	reverse := [4]int{3, 2, 1, 0}
+	compose := func(a, b [4]int) (c [4]int) {
+		for i := range 4 {
+			c[i] = a[b[i]]
+		}
+		return
+	}
+
+	// Permutations des indices compatibles avec les diagonales : celles qui commutent avec le retournement
+	var permutations [][4]int
+	for _, p := range permutationsOf([4]int{0, 1, 2, 3}) {
+		if compose(p, reverse) == compose(reverse, p) {
+			permutations = append(permutations, p)
+		}
+	}
+	identity := [4]int{0, 1, 2, 3}
+	slices.SortStableFunc(permutations, func(a, b [4]int) int {
+		dihedral := func(p [4]int) bool { return p == identity || p == reverse }
+		switch {
+		case dihedral(a) && !dihedral(b):
+			return -1
+		case !dihedral(a) && dihedral(b):
+			return 1
+		}
+		return 0
+	})
+
+	// Une permutation des rangées, la même éventuellement retournée pour les colonnes, suivie ou non d'une transposition
+	for pass := range 2 {
+		for _, rows := range permutations {
+			if (pass == 0) != (rows == identity || rows == reverse) {
+				continue
+			}
+			for _, cols := range [][4]int{rows, compose(reverse, rows)} {
+				for _, transpose := range []bool{false, true} {
+					var symmetry boardSymmetry
+					for row := range 4 {
+						for col := range 4 {
+							r, c := rows[row], cols[col]
+							if transpose {
+								r, c = c, r
+							}
+							symmetry.image[row*4+col] = int8(r*4 + c)
+							symmetry.source[r*4+c] = int8(row*4 + col)
+						}
+					}
+					symmetries = append(symmetries, symmetry)
+				}
+			}
+		}
+	}
+	return
+}
+
+// permutationsOf retourne les permutations d'un quadruplet
+func permutationsOf(values [4]int) (result [][4]int) {
+	var generate func(k int)
+	generate = func(k int) {
+		if k == len(values) {
+			result = append(result, values)
+			return
+		}
+		for i := k; i < len(values); i++ {
+			values[k], values[i] = values[i], values[k]
+			generate(k + 1)
+			values[k], values[i] = values[i], values[k]
+		}
+	}
+	generate(0)
+	return
+}
+
+// symmetriesFor retourne les symétries du plateau valides pour la variante
+func symmetriesFor(variant string) []boardSymmetry {
+	if variant == game.VariantSquares || variant == game.VariantSquaresTorus {
+		return boardSymmetries[:8]
+	}
+	return boardSymmetries
+}
+
+// Symmetry transforme une position en sa forme canonique : symétrie du plateau, puis inversion (masque) et
+// permutation des caractéristiques des pièces
+type Symmetry struct {
+	board      *boardSymmetry
+	mask       game.Piece
+	attributes [4]uint8 // Bit de destination de chaque caractéristique
+}
+
+// piece applique la symétrie à une pièce
+func (s Symmetry) piece(piece game.Piece) game.Piece {
+	if piece == game.PieceEmpty {
+		return piece
+	}
+	piece ^= s.mask
+	var result game.Piece
+	for bit, destination := range s.attributes {
+		result |= (piece >> bit & 1) << destination
+	}
+	return result
+}
+
+// inversePiece annule la symétrie sur une pièce
+func (s Symmetry) inversePiece(piece game.Piece) game.Piece {
+	if piece == game.PieceEmpty {
+		return piece
+	}
+	var result game.Piece
+	for bit, destination := range s.attributes {
+		result |= (piece >> destination & 1) << bit
+	}
+	return result ^ s.mask
+}
+
+// Moves applique la symétrie à une suite de coups
+func (s Symmetry) Moves(moves []AIMove) []AIMove {
+	return s.mapMoves(moves, s.piece, s.board.image)
+}
+
+// InverseMoves annule la symétrie sur une suite de coups
+func (s Symmetry) InverseMoves(moves []AIMove) []AIMove {
+	return s.mapMoves(moves, s.inversePiece, s.board.source)
+}
+
+func (s Symmetry) mapMoves(moves []AIMove, piece func(game.Piece) game.Piece, squares [16]int8) []AIMove {
+	mapped := make([]AIMove, len(moves))
+	for i, move := range moves {
+		square := squares[move.Move.Position.Row*4+move.Move.Position.Col]
+		mapped[i] = AIMove{
+			Move: game.Move{
+				Piece:    piece(move.Move.Piece),
+				Position: game.Position{Row: int(square) / 4, Col: int(square) % 4},
+			},
+			SelectedPiece: piece(move.SelectedPiece),
+		}
+	}
+	return mapped
+}
+
+// bitMoves applique la symétrie, ou son inverse, à une continuation de la recherche
+func (s Symmetry) bitMoves(moves []bitMove, inverse bool) []bitMove {
+	mapped := make([]bitMove, len(moves))
+	for i, move := range moves {
+		mapped[i] = s.bitMove(move, inverse)
+	}
+	return mapped
+}
+
+// bitMove convertit un coup de la recherche par la symétrie ou son inverse
+func (s Symmetry) bitMove(move bitMove, inverse bool) bitMove {
+	if inverse {
+		return bitMove{square: s.board.source[move.square], piece: int8(s.inversePiece(game.Piece(move.piece)))}
+	}
+	return bitMove{square: s.board.image[move.square], piece: int8(s.piece(game.Piece(move.piece)))}
+}
+
+// canonicalCandidate représente l'image d'une position par une symétrie du plateau, ses pièces normalisées
+type canonicalCandidate struct {
+	occupancy uint16 // Cases occupées
+	pieces    uint64 // Pièces dans l'ordre des cases, suivies de la pièce en main, 4 bits chacune
+}
+
+func (a canonicalCandidate) less(b canonicalCandidate) bool {
+	return a.occupancy < b.occupancy || (a.occupancy == b.occupancy && a.pieces < b.pieces)
+}
+
+// canonicalSymmetry retourne la symétrie qui mène la position (pièce de chaque case et pièce en main) à sa forme
+// canonique
+func canonicalSymmetry(squares *[16]game.Piece, selected game.Piece, symmetries []boardSymmetry) Symmetry {
+	var best canonicalCandidate
+	var bestSymmetry Symmetry
+
+	for i := range symmetries {
+		board := &symmetries[i]
+
+		// Pièces dans l'ordre des cases de l'image, puis la pièce en main
+		var sequence [16]game.Piece
+		count := 0
+		var occupancy uint16
+		for square := range 16 {
+			if piece := squares[board.source[square]]; piece != game.PieceEmpty {
+				occupancy |= 1 << (15 - square)
+				sequence[count] = piece
+				count++
+			}
+		}
+		if selected != game.PieceEmpty {
+			sequence[count] = selected
+			count++
+		}
+
+		// La première pièce devient 0 ; les caractéristiques sont ensuite triées par leurs valeurs successives,
+		// ce qui donne la plus petite suite possible
+		symmetry := Symmetry{board: board}
+		if count > 0 {
+			symmetry.mask = sequence[0]
+		}
+		var columns [4]uint32
+		for i := range count {
+			piece := sequence[i] ^ symmetry.mask
+			for bit := range 4 {
+				columns[bit] = columns[bit]<<1 | uint32(piece>>bit&1)
+			}
+		}
+		order := [4]uint8{0, 1, 2, 3}
+		for i := 1; i < 4; i++ {
+			for j := i; j > 0 && columns[order[j]] < columns[order[j-1]]; j-- {
+				order[j], order[j-1] = order[j-1], order[j]
+			}
+		}
+		// La plus petite colonne occupe le bit de poids fort
+		for rank, bit := range order {
+			symmetry.attributes[bit] = uint8(3 - rank)
+		}
+
+		candidate := canonicalCandidate{occupancy: occupancy}
+		for i := range count {
+			candidate.pieces = candidate.pieces<<4 | uint64(symmetry.piece(sequence[i]))
+		}
+
+		if i == 0 || candidate.less(best) {
+			best, bestSymmetry = candidate, symmetry
+		}
+	}
+	return bestSymmetry
+}
+
+// hash calcule la clé de Zobrist de l'image de la position par la symétrie
+func (s Symmetry) hash(squares *[16]game.Piece, selected game.Piece, variantKey uint64) uint64 {
+	hash := selectedKey(s.piece(selected)) ^ variantKey
+	for square, piece := range squares {
+		if piece != game.PieceEmpty {
+			hash ^= zobristSquares[s.board.image[square]][s.piece(piece)]
+		}
+	}
+	return hash
+}
+
+// Canonical retourne la forme canonique de l'état : le plus petit représentant parmi ses images par les symétries
+// du plateau et des caractéristiques, et la symétrie qui y mène. Deux positions équivalentes ont la même forme
+// canonique, et donc la même clé de Zobrist.
+func (state GameState) Canonical() (GameState, Symmetry) {
+	squares := state.squares()
+	symmetry := canonicalSymmetry(&squares, state.SelectedPiece, symmetriesFor(state.Variant))
+	return symmetry.apply(state), symmetry
+}
+
+// apply retourne l'image de l'état par la symétrie
+func (s Symmetry) apply(state GameState) GameState {
+	image := state
+	image.Board = game.GetEmptyBoard()
+	for square := range 16 {
+		if piece := state.Board[square/4][square%4]; piece != game.PieceEmpty {
+			target := s.board.image[square]
+			image.Board[target/4][target%4] = s.piece(piece)
+		}
+	}
+	image.SelectedPiece = s.piece(state.SelectedPiece)
+	image.AvailablePieces = make([]game.Piece, len(state.AvailablePieces))
+	for i, piece := range state.AvailablePieces {
+		image.AvailablePieces[i] = s.piece(piece)
+	}
+	image.Hash = image.ComputeHash()
+	return image
+}
+
+// CanonicalKey retourne la clé de Zobrist de la forme canonique de l'état, commune à toutes les positions équivalentes
+func (state GameState) CanonicalKey() uint64 {
+	b := NewBitboard(state)
+	key, _ := b.canonical()
+	return key
+}
+
+// squares retourne la pièce de chaque case
+func (state GameState) squares() (squares [16]game.Piece) {
+	for square := range squares {
+		squares[square] = state.Board[square/4][square%4]
+	}
+	return
+}
+
+// canonical retourne la clé de la forme canonique de la position et la symétrie qui y mène
+func (b *Bitboard) canonical() (uint64, Symmetry) {
+	squares := b.squares()
+	symmetry := canonicalSymmetry(&squares, b.Selected, b.rules.symmetries)
+	return symmetry.hash(&squares, b.Selected, b.rules.key), symmetry
+}
+
+// UniqueMoves ne conserve qu'un coup parmi ceux menant à des positions équivalentes
+func UniqueMoves(state GameState, moves []AIMove) []AIMove {
+	b := NewBitboard(state)
+	seen := make(map[uint64]bool, len(moves))
+	unique := moves[:0:0]
+	for _, move := range moves {
+		child := b.Play(bitMoveOf(move))
+		if key, _ := child.canonical(); !seen[key] {
+			seen[key] = true
+			unique = append(unique, move)
+		}
+	}
+	return unique
+}
+
+// uniqueMoves est l'équivalent de UniqueMoves pour la recherche ; moves est filtré sur place
+func (b *Bitboard) uniqueMoves(moves []bitMove) []bitMove {
+	seen := make(map[uint64]bool, len(moves))
+	unique := moves[:0]
+	for _, move := range moves {
+		child := b.Play(move)
+		if key, _ := child.canonical(); !seen[key] {
+			seen[key] = true
+			unique = append(unique, move)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,203 @@
+package ai
+
+import (
+	"math/rand"
+	"quarto/models/game"
+	"testing"
+)
+
+// winsAfter vérifie que quatre pièces de caractéristique commune posées sur les cases forment encore un Quarto après
+// la symétrie du plateau
+func winsAfter(board boardSymmetry, squares [4]int, variant string) bool {
+	state := GameState{Board: game.GetEmptyBoard()}
+	for i, square := range squares {
+		target := board.image[square]
+		state.Board[target/4][target%4] = game.Piece(i)
+	}
+	return game.CheckWinVariant(state.Board, variant)
+}
+
+func TestBoardSymmetries(t *testing.T) {
+	if len(boardSymmetries) != 32 {
+		t.Fatalf("expected 32 board symmetries, got %d", len(boardSymmetries))
+	}
+	seen := map[[16]int8]bool{}
+	for _, symmetry := range boardSymmetries {
+		seen[symmetry.image] = true
+		for square := range 16 {
+			if symmetry.source[symmetry.image[square]] != int8(square) {
+				t.Fatal("source is not the inverse of image")
+			}
+		}
+	}
+	if len(seen) != 32 {
+		t.Errorf("expected 32 distinct symmetries, got %d", len(seen))
+	}
+
+	lines := [][4]int{{0, 5, 10, 15}, {3, 6, 9, 12}}
+	for i := range 4 {
+		lines = append(lines, [4]int{i * 4, i*4 + 1, i*4 + 2, i*4 + 3}, [4]int{i, i + 4, i + 8, i + 12})
+	}
+	for k, symmetry := range boardSymmetries {
+		for _, line := range lines {
+			if !winsAfter(symmetry, line, game.VariantStandard) {
+				t.Errorf("symmetry %d breaks line %v", k, line)
+			}
+		}
+	}
+
+	// Seules les 8 premières préservent les carrés de cases adjacentes
+	for k, symmetry := range boardSymmetries {
+		preserved := true
+		for row := range 3 {
+			for col := range 3 {
+				square := [4]int{row*4 + col, row*4 + col + 1, row*4 + col + 4, row*4 + col + 5}
+				preserved = preserved && winsAfter(symmetry, square, game.VariantSquares)
+			}
+		}
+		if preserved != (k < 8) {
+			t.Errorf("symmetry %d: squares preserved = %v", k, preserved)
+		}
+	}
+}
+
+// randomSymmetry tire une symétrie quelconque parmi celles de la variante
+func randomSymmetry(rng *rand.Rand, variant string) Symmetry {
+	boards := symmetriesFor(variant)
+	symmetry := Symmetry{board: &boards[rng.Intn(len(boards))], mask: game.Piece(rng.Intn(16))}
+	for i, destination := range rng.Perm(4) {
+		symmetry.attributes[i] = uint8(destination)
+	}
+	return symmetry
+}
+
+// randomState joue des coups au hasard depuis le début de partie
+func randomState(rng *rand.Rand, variant string, plies int) GameState {
+	state := openingState()
+	state.Variant = variant
+	state.Hash = state.ComputeHash()
+	for range plies {
+		if state.IsGameOver || state.SelectedPiece == game.PieceEmpty {
+			break
+		}
+		moves := GetValidMoves(state)
+		state = state.ApplyMove(moves[rng.Intn(len(moves))])
+	}
+	return state
+}
+
+func TestCanonicalKey(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, variant := range []string{game.VariantStandard, game.VariantSquares, game.VariantSquaresTorus} {
+		for range 200 {
+			state := randomState(rng, variant, rng.Intn(12))
+			symmetry := randomSymmetry(rng, variant)
+			image := symmetry.apply(state)
+
+			if image.CanonicalKey() != state.CanonicalKey() {
+				t.Fatalf("%s: equivalent positions have different keys", variant)
+			}
+			canonical, _ := state.Canonical()
+			if imageCanonical, _ := image.Canonical(); canonical.Board != imageCanonical.Board {
+				t.Fatalf("%s: equivalent positions have different canonical forms", variant)
+			}
+			if canonical.Hash != state.CanonicalKey() {
+				t.Fatalf("%s: canonical key differs from the canonical state hash", variant)
+			}
+
+			// Les coups reviennent à l'identique après un aller-retour
+			moves := GetValidMoves(state)
+			if len(moves) > 0 {
+				move := moves[rng.Intn(len(moves))]
+				if back := symmetry.InverseMoves(symmetry.Moves([]AIMove{move}))[0]; back != move {
+					t.Fatalf("round trip changed %+v into %+v", move, back)
+				}
+				if symmetry.apply(state.ApplyMove(move)).Hash != image.ApplyMove(symmetry.Moves([]AIMove{move})[0]).Hash {
+					t.Fatal("a transformed move does not lead to the transformed position")
+				}
+			}
+		}
+	}
+}
+
+// candidateOf lit une position dans l'ordre utilisé pour comparer les formes canoniques
+func candidateOf(state GameState) (candidate canonicalCandidate) {
+	for square := range 16 {
+		if piece := state.Board[square/4][square%4]; piece != game.PieceEmpty {
+			candidate.occupancy |= 1 << (15 - square)
+			candidate.pieces = candidate.pieces<<4 | uint64(piece)
+		}
+	}
+	if state.SelectedPiece != game.PieceEmpty {
+		candidate.pieces = candidate.pieces<<4 | uint64(state.SelectedPiece)
+	}
+	return
+}
+
+func TestCanonicalMinimal(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	permutations := permutationsOf([4]int{0, 1, 2, 3})
+	for range 20 {
+		state := randomState(rng, game.VariantStandard, rng.Intn(10))
+		canonical, _ := state.Canonical()
+		best := candidateOf(canonical)
+
+		// Toutes les images de la position, par force brute
+		for i := range boardSymmetries {
+			for mask := range 16 {
+				for _, permutation := range permutations {
+					symmetry := Symmetry{board: &boardSymmetries[i], mask: game.Piece(mask)}
+					for bit, destination := range permutation {
+						symmetry.attributes[bit] = uint8(destination)
+					}
+					if candidateOf(symmetry.apply(state)).less(best) {
+						t.Fatalf("found an image smaller than the canonical form of %v", state.Board)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestCanonicalKeyDistinct(t *testing.T) {
+	board := func(pieces map[int]game.Piece, variant string) GameState {
+		state := GameState{Board: game.GetEmptyBoard(), SelectedPiece: game.PieceEmpty, Variant: variant}
+		for square, piece := range pieces {
+			state.Board[square/4][square%4] = piece
+		}
+		return state
+	}
+
+	// Deux pièces voisines partageant trois caractéristiques ou aucune
+	if board(map[int]game.Piece{0: 0, 1: 1}, "").CanonicalKey() == board(map[int]game.Piece{0: 0, 1: 15}, "").CanonicalKey() {
+		t.Error("pieces sharing three or no characteristics should give different keys")
+	}
+	// Une pièce sur un coin ou au centre : équivalentes en standard (échange intérieur/extérieur), pas avec les carrés
+	corner, center := map[int]game.Piece{0: 3}, map[int]game.Piece{5: 3}
+	if board(corner, "").CanonicalKey() != board(center, "").CanonicalKey() {
+		t.Error("corner and center should be equivalent on the standard board")
+	}
+	if board(corner, game.VariantSquares).CanonicalKey() == board(center, game.VariantSquares).CanonicalKey() {
+		t.Error("corner and center should not be equivalent in the squares variant")
+	}
+}
+
+func TestUniqueMoves(t *testing.T) {
+	// Plateau vide : coins et centre d'une part, bords d'autre part se valent, la pièce donnée ne compte que par ses
+	// différences avec la pièce posée
+	state := GameState{Board: game.GetEmptyBoard(), SelectedPiece: 5, AvailablePieces: []game.Piece{}}
+	for _, piece := range game.GetAllPieces() {
+		if piece != 5 {
+			state.AvailablePieces = append(state.AvailablePieces, piece)
+		}
+	}
+	if moves := UniqueMoves(state, GetValidMoves(state)); len(moves) != 8 {
+		t.Errorf("expected 8 distinct first moves, got %d", len(moves))
+	}
+
+	// Avec les carrés, coins, bords et centre se distinguent
+	state.Variant = game.VariantSquares
+	if moves := UniqueMoves(state, GetValidMoves(state)); len(moves) != 12 {
+		t.Errorf("expected 12 distinct first moves in the squares variant, got %d", len(moves))
+	}
+}
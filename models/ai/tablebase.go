@@ -0,0 +1,310 @@
+package ai
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+	"os"
+	"quarto/models/game"
+	"slices"
+)
+
+// Fins de partie résolues. Avec peu de cases vides, l'arbre d'une position est assez petit pour être parcouru en
+// entier : le solveur en calcule le résultat exact et la distance jusqu'à la fin de la partie. Les positions résolues
+// sont rangées par clé canonique dans une table de fins de partie, enregistrée sur disque et consultée par le moteur
+// avant toute recherche.
+
+// tablebaseMagic identifie les fichiers de table de fins de partie
+const tablebaseMagic = "QTB1"
+
+// Outcome est le résultat exact d'une position pour le joueur au trait, en jouant au mieux des deux côtés
+type Outcome struct {
+	Result   int // 1 = victoire, 0 = nulle, -1 = défaite
+	Distance int // Nombre de demi-coups jusqu'à la fin de la partie
+}
+
+// better indique si le résultat est préférable à other pour le joueur au trait : le gagnant cherche la victoire la
+// plus rapide, le perdant la défaite la plus lente
+func (o Outcome) better(other Outcome) bool {
+	if o.Result != other.Result {
+		return o.Result > other.Result
+	}
+	if o.Result < 0 {
+		return o.Distance > other.Distance
+	}
+	return o.Distance < other.Distance
+}
+
+// Score convertit le résultat en score de la recherche, du point de vue du joueur 1
+func (o Outcome) Score(isMaximizing bool) int {
+	score := o.Result * WIN_SCORE
+	if !isMaximizing {
+		score = -score
+	}
+	return score
+}
+
+// encode range le résultat dans un octet : le résultat sur 2 bits, la distance au-dessus
+func (o Outcome) encode() uint8 {
+	return uint8(o.Distance)<<2 | uint8(o.Result+1)
+}
+
+// decodeOutcome lit un résultat rangé par encode
+func decodeOutcome(value uint8) Outcome {
+	return Outcome{Result: int(value&3) - 1, Distance: int(value >> 2)}
+}
+
+// Solver résout exactement les positions d'au plus MaxEmpty cases vides, en mémorisant les positions résolues
+type Solver struct {
+	MaxEmpty int
+	known    map[uint64]Outcome
+}
+
+// NewSolver crée un solveur des positions d'au plus maxEmpty cases vides
+func NewSolver(maxEmpty int) *Solver {
+	return &Solver{MaxEmpty: maxEmpty, known: make(map[uint64]Outcome)}
+}
+
+// Solve retourne le résultat exact d'une position en cours, dont la pièce à placer est choisie
+func (s *Solver) Solve(state GameState) (Outcome, error) {
+	if state.IsGameOver || state.SelectedPiece == game.PieceEmpty {
+		return Outcome{}, errors.New("la position doit être en cours, avec une pièce à placer")
+	}
+	b := NewBitboard(state)
+	if empty := bits.OnesCount16(^b.Occupied); empty > s.MaxEmpty {
+		return Outcome{}, fmt.Errorf("la position compte %d cases vides, le solveur en accepte au plus %d", empty, s.MaxEmpty)
+	}
+	return s.solve(&b), nil
+}
+
+// Len retourne le nombre de positions résolues
+func (s *Solver) Len() int {
+	return len(s.known)
+}
+
+// solve résout une position en cours. Les mêmes coups que ceux de la recherche sont essayés : une victoire immédiate
+// dispense du reste, et donner une pièce gagnante à l'adversaire n'est jamais préférable à une autre pièce.
+func (s *Solver) solve(b *Bitboard) Outcome {
+	key, _ := b.canonical()
+	if outcome, ok := s.known[key]; ok {
+		return outcome
+	}
+
+	best := Outcome{Result: 1, Distance: 1}
+	if _, ok := b.winningMove(); !ok {
+		var buffer [maxMoves]bitMove
+		for i, move := range b.appendSafeMoves(buffer[:0]) {
+			child := b.Play(move)
+			outcome := Outcome{Distance: 1}
+			if !child.IsGameOver {
+				reply := s.solve(&child)
+				outcome = Outcome{Result: -reply.Result, Distance: reply.Distance + 1}
+			}
+			if i == 0 || outcome.better(best) {
+				best = outcome
+			}
+		}
+	}
+
+	s.known[key] = best
+	return best
+}
+
+// Tablebase construit la table des positions résolues
+func (s *Solver) Tablebase() *Tablebase {
+	t := &Tablebase{MaxEmpty: s.MaxEmpty, keys: make([]uint64, 0, len(s.known)), values: make([]uint8, len(s.known))}
+	for key := range s.known {
+		t.keys = append(t.keys, key)
+	}
+	slices.Sort(t.keys)
+	for i, key := range t.keys {
+		t.values[i] = s.known[key].encode()
+	}
+	return t
+}
+
+// Tablebase est une table de fins de partie résolues, triée par clé canonique
+type Tablebase struct {
+	MaxEmpty int // Nombre maximal de cases vides des positions de la table
+	keys     []uint64
+	values   []uint8
+}
+
+// EndgameTablebase est la table de fins de partie des nouveaux moteurs (nil = aucune)
+var EndgameTablebase *Tablebase
+
+// Len retourne le nombre de positions de la table
+func (t *Tablebase) Len() int {
+	return len(t.keys)
+}
+
+// Probe cherche une position dans la table
+func (t *Tablebase) Probe(state GameState) (Outcome, bool) {
+	b := NewBitboard(state)
+	return t.probe(&b)
+}
+
+// probe cherche une position de la recherche dans la table
+func (t *Tablebase) probe(b *Bitboard) (Outcome, bool) {
+	if b.IsGameOver || bits.OnesCount16(^b.Occupied) > t.MaxEmpty {
+		return Outcome{}, false
+	}
+	key, _ := b.canonical()
+	i, found := slices.BinarySearch(t.keys, key)
+	if !found {
+		return Outcome{}, false
+	}
+	return decodeOutcome(t.values[i]), true
+}
+
+// bestMove retourne le meilleur coup d'une position de la table et son résultat, si tous les coups à considérer y
+// figurent
+func (t *Tablebase) bestMove(b *Bitboard) (bitMove, Outcome, bool) {
+	if move, ok := b.winningMove(); ok {
+		return move, Outcome{Result: 1, Distance: 1}, true
+	}
+
+	var best bitMove
+	var bestOutcome Outcome
+	var buffer [maxMoves]bitMove
+	for i, move := range b.appendSafeMoves(buffer[:0]) {
+		child := b.Play(move)
+		outcome := Outcome{Distance: 1}
+		if !child.IsGameOver {
+			reply, ok := t.probe(&child)
+			if !ok {
+				return bitMove{}, Outcome{}, false
+			}
+			outcome = Outcome{Result: -reply.Result, Distance: reply.Distance + 1}
+		}
+		if i == 0 || outcome.better(bestOutcome) {
+			best, bestOutcome = move, outcome
+		}
+	}
+	return best, bestOutcome, true
+}
+
+// principalVariation retourne la suite de meilleurs coups d'une position de la table, jusqu'à la fin de la partie
+func (t *Tablebase) principalVariation(root *Bitboard) []bitMove {
+	var pv []bitMove
+	for node := *root; !node.IsGameOver; {
+		move, _, ok := t.bestMove(&node)
+		if !ok {
+			break
+		}
+		pv = append(pv, move)
+		node = node.Play(move)
+	}
+	return pv
+}
+
+// Write écrit la table compressée : l'en-tête, puis les écarts entre clés successives et les résultats
+func (t *Tablebase) Write(w io.Writer) error {
+	zw := gzip.NewWriter(w)
+	bw := bufio.NewWriter(zw)
+
+	var buffer [binary.MaxVarintLen64]byte
+	bw.WriteString(tablebaseMagic)
+	bw.Write(binary.AppendUvarint(buffer[:0], uint64(t.MaxEmpty)))
+	bw.Write(binary.AppendUvarint(buffer[:0], uint64(len(t.keys))))
+	previous := uint64(0)
+	for i, key := range t.keys {
+		bw.Write(binary.AppendUvarint(buffer[:0], key-previous))
+		bw.WriteByte(t.values[i])
+		previous = key
+	}
+
+	// Les erreurs d'écriture sont conservées par bw jusqu'à Flush
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// ReadTablebase lit une table écrite par Write
+func ReadTablebase(r io.Reader) (*Tablebase, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("table de fins de partie illisible : %w", err)
+	}
+	defer zr.Close()
+	br := bufio.NewReader(zr)
+
+	magic := make([]byte, len(tablebaseMagic))
+	if _, err := io.ReadFull(br, magic); err != nil || string(magic) != tablebaseMagic {
+		return nil, errors.New("le fichier n'est pas une table de fins de partie")
+	}
+	maxEmpty, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, fmt.Errorf("en-tête de la table invalide : %w", err)
+	}
+	count, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, fmt.Errorf("en-tête de la table invalide : %w", err)
+	}
+
+	// Le nombre de positions annoncé ne sert qu'à réserver la mémoire, dans une limite raisonnable
+	t := &Tablebase{MaxEmpty: int(maxEmpty), keys: make([]uint64, 0, min(int(count), 1<<20)), values: make([]uint8, 0, min(int(count), 1<<20))}
+	key := uint64(0)
+	for range count {
+		delta, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, fmt.Errorf("table tronquée : %w", err)
+		}
+		value, err := br.ReadByte()
+		if err != nil {
+			return nil, fmt.Errorf("table tronquée : %w", err)
+		}
+		key += delta
+		t.keys = append(t.keys, key)
+		t.values = append(t.values, value)
+	}
+	return t, nil
+}
+
+// LoadTablebase charge une table depuis un fichier
+func LoadTablebase(path string) (*Tablebase, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadTablebase(file)
+}
+
+// Save enregistre la table dans un fichier
+func (t *Tablebase) Save(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := t.Write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// probeTablebase cherche la racine dans la table de fins de partie du moteur, et retourne le résultat exact et la
+// suite de meilleurs coups si elle y figure
+func (e *Engine) probeTablebase(state GameState) (SearchResult, bool) {
+	if e.Tablebase == nil {
+		return SearchResult{}, false
+	}
+	root := NewBitboard(state)
+	outcome, ok := e.Tablebase.probe(&root)
+	if !ok {
+		return SearchResult{}, false
+	}
+	pv := e.Tablebase.principalVariation(&root)
+	if len(pv) == 0 {
+		return SearchResult{}, false
+	}
+
+	isMaximizing := len(state.AvailablePieces)%2 == 0
+	return SearchResult{BestMoves: root.aiMoves(pv), Score: outcome.Score(isMaximizing), Depth: outcome.Distance}, true
+}
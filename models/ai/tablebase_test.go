@@ -0,0 +1,86 @@
+package ai
+
+import (
+	"bytes"
+	"math/rand"
+	"quarto/models/game"
+	"testing"
+)
+
+func TestSolverMatchesSearch(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	solver := NewSolver(6)
+	for _, variant := range []string{game.VariantStandard, game.VariantSquares} {
+		for range 20 {
+			state := randomState(rng, variant, 8)
+			if state.IsGameOver {
+				continue
+			}
+			outcome, err := solver.Solve(state)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			isMaximizing := len(state.AvailablePieces)%2 == 0
+			if score := NewEngine(DefaultMaxDepth).Search(state).Score; outcome.Score(isMaximizing) != score {
+				t.Fatalf("%s: solver found %+v, search found score %d", variant, outcome, score)
+			}
+			if outcome.Distance < 1 || outcome.Distance > 16-2-8 {
+				t.Errorf("%s: distance %d out of range", variant, outcome.Distance)
+			}
+		}
+	}
+
+	if _, err := solver.Solve(openingState()); err == nil {
+		t.Error("positions with too many empty squares should be rejected")
+	}
+}
+
+func TestTablebase(t *testing.T) {
+	rng := rand.New(rand.NewSource(8))
+	solver := NewSolver(6)
+	var states []GameState
+	for len(states) < 10 {
+		if state := randomState(rng, game.VariantStandard, 8); !state.IsGameOver {
+			if _, err := solver.Solve(state); err != nil {
+				t.Fatal(err)
+			}
+			states = append(states, state)
+		}
+	}
+
+	// Aller-retour par le format compressé
+	var buffer bytes.Buffer
+	if err := solver.Tablebase().Write(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	tablebase, err := ReadTablebase(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tablebase.Len() != solver.Len() || tablebase.MaxEmpty != 6 {
+		t.Fatalf("read %d positions up to %d empty squares, expected %d up to 6", tablebase.Len(), tablebase.MaxEmpty, solver.Len())
+	}
+
+	for _, state := range states {
+		expected, _ := solver.Solve(state)
+		if outcome, ok := tablebase.Probe(randomSymmetry(rng, state.Variant).apply(state)); !ok || outcome != expected {
+			t.Fatalf("probe returned %+v (%v), expected %+v", outcome, ok, expected)
+		}
+
+		// Le moteur répond depuis la table, sans recherche, et sa suite de coups mène au résultat annoncé
+		engine := NewEngine(DefaultMaxDepth)
+		engine.Tablebase = tablebase
+		result := engine.Search(state)
+		if result.Nodes != 0 || result.Score != NewEngine(DefaultMaxDepth).Search(state).Score {
+			t.Fatalf("tablebase search returned score %d after %d nodes", result.Score, result.Nodes)
+		}
+		end := state
+		for _, move := range result.BestMoves {
+			end = end.ApplyMove(move)
+		}
+		if !end.IsGameOver || len(result.BestMoves) != expected.Distance || end.Winner*WIN_SCORE != result.Score {
+			t.Errorf("principal variation of %d moves ends with winner %d, expected %+v", len(result.BestMoves), end.Winner, expected)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package ai
+
+import (
+	"math/bits"
+	"sync/atomic"
+	"unsafe"
+)
+
+// TTEntry représente une entrée dans la table de transposition ; une entrée publiée n'est plus modifiée
+type TTEntry struct {
+	Key        uint64    // Clé de Zobrist de l'état du jeu
+	Score      int       // Score évalué
+	Depth      int       // Profondeur de recherche
+	Flag       TTFlag    // Type de valeur
+	BestMoves  []bitMove // Continuation
+	generation uint32    // Recherche ayant produit l'entrée
+}
+
+// ttBucket regroupe deux entrées : la première est remplacée par une recherche plus profonde (ou plus récente),
+// la seconde à chaque écriture
+type ttBucket [2]atomic.Pointer[TTEntry]
+
+// TranspositionTable représente la table de transposition : un tableau de taille fixe, lu et écrit sans verrou
+// (chaque case est un pointeur atomique vers une entrée immuable)
+type TranspositionTable struct {
+	buckets    []ttBucket
+	mask       uint64
+	generation atomic.Uint32
+	oldest     atomic.Uint32 // Génération la plus ancienne encore valide
+	hits       atomic.Int64  // Compteur de cache hits pour les statistiques
+	misses     atomic.Int64  // Compteur de cache misses pour les statistiques
+}
+
+// NewTranspositionTable crée une table de transposition occupant au plus sizeMB mégaoctets une fois remplie
+func NewTranspositionTable(sizeMB int) *TranspositionTable {
+	bucketBytes := uint64(unsafe.Sizeof(ttBucket{}) + 2*unsafe.Sizeof(TTEntry{}))
+	count := uint64(sizeMB) << 20 / bucketBytes
+	if count < minTTBuckets {
+		count = minTTBuckets
+	}
+	count = 1 << (bits.Len64(count) - 1) // Puissance de deux, pour indexer par masque
+
+	return &TranspositionTable{
+		buckets: make([]ttBucket, count),
+		mask:    count - 1,
+	}
+}
+
+// NewSearch démarre une nouvelle génération : les entrées des recherches précédentes restent lisibles
+// mais cèdent leur place en priorité
+func (tt *TranspositionTable) NewSearch() {
+	tt.generation.Add(1)
+}
+
+// Store stocke une entrée dans la table de transposition
+func (tt *TranspositionTable) Store(key uint64, score int, depth int, flag TTFlag, bestMoves []bitMove) {
+	entry := &TTEntry{
+		Key:        key,
+		Score:      score,
+		Depth:      depth,
+		Flag:       flag,
+		BestMoves:  bestMoves,
+		generation: tt.generation.Load(),
+	}
+
+	bucket := &tt.buckets[key&tt.mask]
+	preferred := bucket[0].Load()
+	if preferred == nil || preferred.Key == key || preferred.generation != entry.generation || depth >= preferred.Depth {
+		bucket[0].Store(entry)
+		return
+	}
+	bucket[1].Store(entry)
+}
+
+// Lookup cherche une entrée utilisable dans la table de transposition : calculée à une profondeur au moins égale,
+// et dont le score est exact ou une borne suffisante pour la fenêtre alpha-beta. Une entrée de la position trop
+// peu profonde ou hors fenêtre est tout de même retournée, son meilleur coup pouvant ordonner la recherche.
+func (tt *TranspositionTable) Lookup(key uint64, depth int, alpha int, beta int) (*TTEntry, bool) {
+	var found *TTEntry
+	bucket := &tt.buckets[key&tt.mask]
+	for i := range bucket {
+		entry := bucket[i].Load()
+		if entry == nil || entry.Key != key || entry.generation < tt.oldest.Load() {
+			continue
+		}
+		if entry.Depth >= depth && (entry.Flag == EXACT || (entry.Flag == LOWER_BOUND && entry.Score >= beta) || (entry.Flag == UPPER_BOUND && entry.Score <= alpha)) {
+			tt.hits.Add(1)
+			return entry, true
+		}
+		found = entry
+	}
+	tt.misses.Add(1)
+	return found, false
+}
+
+// GetStats retourne les statistiques de la table de transposition
+func (tt *TranspositionTable) GetStats() (int, int) {
+	return int(tt.hits.Load()), int(tt.misses.Load())
+}
+
+// Clear vide la table de transposition sans la parcourir : les entrées des générations précédentes sont ignorées
+// puis remplacées au fil des écritures
+func (tt *TranspositionTable) Clear() {
+	tt.oldest.Store(tt.generation.Add(1))
+	tt.hits.Store(0)
+	tt.misses.Store(0)
+}
@@ -1,6 +1,10 @@
 package ai
 
-import "quarto/models/game"
+import (
+	"math/rand"
+	"quarto/models/game"
+	"time"
+)
 
 type AIMove struct {
 	Move          game.Move  // Le coup à jouer
@@ -14,20 +18,43 @@ type GameState struct {
 	SelectedPiece   game.Piece       // Pièce sélectionnée pour placement
 	IsGameOver      bool             // Jeu terminé
 	Winner          int              // 0 = pas de gagnant, 1 = joueur 1, -1 = joueur 2
+	Variant         string           // Variante de règles (vide = standard)
+	Hash            uint64           // Clé de Zobrist, mise à jour par ApplyMove
 }
 
 // Engine représente le moteur d'IA Quarto
 type Engine struct {
-	MaxDepth int                 // Profondeur maximale de recherche
-	TT       *TranspositionTable // Table de transposition
+	MaxDepth  int                 // Profondeur maximale de recherche
+	TimeLimit time.Duration       // Durée maximale d'une recherche (0 = sans limite)
+	NodeLimit int                 // Nombre maximal de nœuds visités par recherche (0 = sans limite)
+	TT        *TranspositionTable // Table de transposition
+	KeepTT    bool                // Conserver la table entre les recherches d'une même partie (voir NewGameEngine)
+	Weights   Weights             // Poids de l'évaluation heuristique
+	Tablebase *Tablebase          // Table de fins de partie consultée avant la recherche (nil = aucune)
+	Book      *Book               // Livre d'ouvertures consulté avant la recherche (nil = aucun)
+	BookRand  *rand.Rand          // Tirage des coups du livre (nil = générateur global)
+
+	Threads       int  // Nombre de workers de la recherche (0 ou 1 = recherche séquentielle)
+	Deterministic bool // Résultat indépendant du nombre de workers et de l'ordonnancement, au prix de la vitesse
+
+	tables []*TranspositionTable // Tables privées des workers du mode déterministe
+	plain  bool                  // Recherche sans ordonnancement, élagage des coups perdants ni fenêtre nulle
 }
 
 // NewEngine crée un nouveau moteur d'IA
 func NewEngine(maxDepth int) *Engine {
+	return newEngine(maxDepth, NewTranspositionTable(TTSizeMB))
+}
 
+// newEngine crée un moteur d'IA utilisant la table de transposition donnée
+func newEngine(maxDepth int, tt *TranspositionTable) *Engine {
 	return &Engine{
-		MaxDepth: maxDepth,
-		TT:       NewTranspositionTable(),
+		MaxDepth:  maxDepth,
+		TT:        tt,
+		Weights:   EvalWeights,
+		Threads:   Threads,
+		Tablebase: EndgameTablebase,
+		Book:      OpeningBook,
 	}
 }
 
@@ -45,69 +72,3 @@ const (
 	LOWER_BOUND               // Borne inférieure (beta cutoff)
 	UPPER_BOUND               // Borne supérieure (alpha cutoff)
 )
-
-// TTEntry représente une entrée dans la table de transposition
-type TTEntry struct {
-	Hash      string   // Hash de l'état du jeu
-	Score     int      // Score évalué
-	Depth     int      // Profondeur de recherche
-	Flag      TTFlag   // Type de valeur
-	BestMoves []AIMove // Liste des coups
-}
-
-// TranspositionTable représente la table de transposition
-type TranspositionTable struct {
-	entries map[string]*TTEntry
-	hits    int // Compteur de cache hits pour les statistiques
-	misses  int // Compteur de cache misses pour les statistiques
-}
-
-// NewTranspositionTable crée une nouvelle table de transposition
-func NewTranspositionTable() *TranspositionTable {
-	return &TranspositionTable{
-		entries: make(map[string]*TTEntry),
-		hits:    0,
-		misses:  0,
-	}
-}
-
-// Store stocke une entrée dans la table de transposition
-func (tt *TranspositionTable) Store(hash string, score int, depth int, flag TTFlag, bestMoves []AIMove) {
-	tt.entries[hash] = &TTEntry{
-		Hash:      hash,
-		Score:     score,
-		Depth:     depth,
-		Flag:      flag,
-		BestMoves: bestMoves,
-	}
-}
-
-// Lookup cherche une entrée dans la table de transposition
-func (tt *TranspositionTable) Lookup(hash string, depth int, alpha int, beta int) (entry *TTEntry, exists bool) {
-	entry, exists = tt.entries[hash]
-	if !exists {
-		tt.misses++
-		return
-	}
-
-	// L'entrée doit avoir été calculée à une profondeur au moins égale
-	if entry.Depth < depth {
-		tt.misses++
-		return
-	}
-	exists = true
-	tt.hits++
-	return
-}
-
-// GetStats retourne les statistiques de la table de transposition
-func (tt *TranspositionTable) GetStats() (int, int) {
-	return tt.hits, tt.misses
-}
-
-// Clear vide la table de transposition
-func (tt *TranspositionTable) Clear() {
-	tt.entries = make(map[string]*TTEntry)
-	tt.hits = 0
-	tt.misses = 0
-}
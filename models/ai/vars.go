@@ -1,5 +1,7 @@
 package ai
 
+import "time"
+
 // BoardSize représente la taille du plateau (4x4 pour Quarto)
 const BoardSize = 4
 
@@ -8,3 +10,31 @@ const PieceCount = 16
 
 // DefaultMaxDepth est la profondeur par défaut pour la recherche
 const DefaultMaxDepth = 16
+
+// DefaultTimeLimit et MaxTimeLimit bornent la durée des recherches demandées par l'API
+const (
+	DefaultTimeLimit = 5 * time.Second
+	MaxTimeLimit     = 30 * time.Second
+)
+
+// TTSizeMB est la taille de la table de transposition des nouveaux moteurs, en mégaoctets
+var TTSizeMB = DefaultTTSizeMB
+
+const (
+	DefaultTTSizeMB = 64
+	minTTBuckets    = 1024
+	privateTTSizeMB = 16 // Taille des tables privées du mode déterministe, une par worker
+)
+
+// Threads est le nombre de workers des recherches des nouveaux moteurs
+var Threads = 1
+
+// stopCheckInterval est le nombre de nœuds entre deux vérifications de l'horloge et du contexte
+const stopCheckInterval = 1024
+
+// HintDepth est la profondeur de recherche des indices donnés aux joueurs
+const HintDepth = 2
+
+// canonicalMinDepth est la profondeur restante à partir de laquelle les positions sont canonisées dans la table de
+// transposition
+const canonicalMinDepth = 2
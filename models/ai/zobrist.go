@@ -0,0 +1,55 @@
+package ai
+
+import "quarto/models/game"
+
+// Clés de Zobrist : une par pièce et par case, une par pièce en main (la dernière pour aucune) et une par variante.
+// Elles sont tirées d'un générateur à graine fixe pour rester identiques d'une exécution à l'autre.
+var zobristSquares, zobristSelected, zobristVariants = zobristKeys()
+
+// zobristKeys tire les clés de Zobrist
+func zobristKeys() (squares [16][16]uint64, selected [17]uint64, variants map[string]uint64) {
+	seed := uint64(0x9e3779b97f4a7c15)
+	next := func() uint64 {
+		// splitmix64
+		seed += 0x9e3779b97f4a7c15
+		z := seed
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		return z ^ (z >> 31)
+	}
+
+	for square := range squares {
+		for piece := range squares[square] {
+			squares[square][piece] = next()
+		}
+	}
+	for i := range selected {
+		selected[i] = next()
+	}
+	variants = map[string]uint64{}
+	for _, variant := range []string{game.VariantSquares, game.VariantSquaresTorus} {
+		variants[variant] = next()
+	}
+	return
+}
+
+// selectedKey retourne la clé de la pièce en main
+func selectedKey(piece game.Piece) uint64 {
+	if piece == game.PieceEmpty {
+		return zobristSelected[16]
+	}
+	return zobristSelected[piece]
+}
+
+// ComputeHash calcule la clé de Zobrist complète de l'état ; ApplyMove la met ensuite à jour de façon incrémentale
+func (state GameState) ComputeHash() uint64 {
+	hash := selectedKey(state.SelectedPiece) ^ zobristVariants[state.Variant]
+	for row := range 4 {
+		for col := range 4 {
+			if piece := state.Board[row][col]; piece != game.PieceEmpty {
+				hash ^= zobristSquares[row*4+col][piece]
+			}
+		}
+	}
+	return hash
+}
@@ -0,0 +1,103 @@
+package ai
+
+import (
+	"math/rand"
+	"quarto/models/game"
+	"testing"
+)
+
+func TestZobristIncremental(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for range 20 {
+		state := openingState()
+		state.Hash = state.ComputeHash()
+		for !state.IsGameOver && state.SelectedPiece != game.PieceEmpty {
+			moves := GetValidMoves(state)
+			state = state.ApplyMove(moves[rng.Intn(len(moves))])
+			if state.Hash != state.ComputeHash() {
+				t.Fatalf("incremental hash %x differs from full hash %x", state.Hash, state.ComputeHash())
+			}
+		}
+	}
+
+	// La variante fait partie de la clé
+	squares := openingState()
+	squares.Variant = game.VariantSquares
+	if squares.ComputeHash() == openingState().ComputeHash() {
+		t.Error("expected different keys for different variants")
+	}
+}
+
+func TestTranspositionTable(t *testing.T) {
+	tt := NewTranspositionTable(1)
+
+	tt.Store(42, 10, 3, EXACT, nil)
+	if _, ok := tt.Lookup(42, 4, LOSS_SCORE, WIN_SCORE); ok {
+		t.Error("an entry searched at depth 3 cannot answer a depth 4 search")
+	}
+	if entry, ok := tt.Lookup(42, 2, LOSS_SCORE, WIN_SCORE); !ok || entry.Score != 10 {
+		t.Errorf("expected a hit, got %+v", entry)
+	}
+
+	// Une borne n'est utilisable que si elle sort de la fenêtre
+	tt.Store(7, 50, 3, LOWER_BOUND, nil)
+	if _, ok := tt.Lookup(7, 3, 0, 100); ok {
+		t.Error("a lower bound inside the window should not be used")
+	}
+	if _, ok := tt.Lookup(7, 3, 0, 40); !ok {
+		t.Error("a lower bound above beta should be used")
+	}
+
+	// Une entrée moins profonde ne chasse pas l'entrée préférée du même seau
+	colliding := uint64(42) + tt.mask + 1
+	tt.Store(colliding, 20, 1, EXACT, nil)
+	if _, ok := tt.Lookup(42, 3, LOSS_SCORE, WIN_SCORE); !ok {
+		t.Error("the deeper entry should have been kept")
+	}
+	if _, ok := tt.Lookup(colliding, 1, LOSS_SCORE, WIN_SCORE); !ok {
+		t.Error("the new entry should be in the always-replace slot")
+	}
+
+	tt.Clear()
+	if _, ok := tt.Lookup(42, 0, LOSS_SCORE, WIN_SCORE); ok {
+		t.Error("the table should be empty after Clear")
+	}
+}
+
+func TestGameTables(t *testing.T) {
+	defer func(tables, size int) { GameTables, TTSizeMB = tables, size }(GameTables, TTSizeMB)
+	GameTables, TTSizeMB = 2, 1
+
+	first := NewGameEngine("a", 3)
+	if !first.KeepTT || NewGameEngine("a", 3).TT != first.TT {
+		t.Fatal("the engines of a game should share its table")
+	}
+	if NewGameEngine("", 3).TT == NewGameEngine("", 3).TT {
+		t.Error("a position without a game should get its own table")
+	}
+
+	NewGameEngine("b", 3)
+	NewGameEngine("a", 3) // "b" devient la table consultée le moins récemment
+	NewGameEngine("c", 3)
+	if NewGameEngine("a", 3).TT != first.TT {
+		t.Error("the most recently used table should have been kept")
+	}
+	if _, ok := gameTables.entries["b"]; ok {
+		t.Error("the least recently used table should have been evicted")
+	}
+}
+
+func BenchmarkZobristHash(b *testing.B) {
+	state := openingState()
+	move := GetValidMoves(state)[0]
+	tt := NewTranspositionTable(1)
+	for i := 0; i < b.N; i++ {
+		tt.Store(state.ApplyMove(move).Hash, i, 0, EXACT, nil)
+	}
+}
+
+func BenchmarkSearch(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		NewEngine(3).Search(openingState())
+	}
+}
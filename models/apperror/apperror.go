@@ -0,0 +1,107 @@
+package apperror
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+)
+
+// Langues des messages d'erreur
+const (
+	LangFR = "fr"
+	LangEN = "en"
+
+	DefaultLang = LangFR
+)
+
+// Error représente une erreur métier avec un code stable, le statut HTTP associé et des détails.
+// Les erreurs sont déclarées une fois par package (sentinelles) puis complétées avec With ou Wrap.
+type Error struct {
+	Code     string
+	Status   int
+	Details  map[string]any
+	messages map[string]string // Modèles de message par langue, les détails s'insèrent via {clé}
+	cause    error
+	reason   *Error // Motif traduit inséré via {reason}
+}
+
+// New déclare une erreur métier et ses messages en français et en anglais
+func New(code string, status int, fr, en string) *Error {
+	return &Error{
+		Code:     code,
+		Status:   status,
+		messages: map[string]string{LangFR: fr, LangEN: en},
+	}
+}
+
+// With retourne une copie de l'erreur complétée d'un détail
+func (e *Error) With(key string, value any) *Error {
+	clone := *e
+	clone.Details = maps.Clone(e.Details)
+	if clone.Details == nil {
+		clone.Details = make(map[string]any)
+	}
+	clone.Details[key] = value
+	return &clone
+}
+
+// Because retourne une copie de l'erreur motivée par une autre erreur métier : le détail reason reçoit le code du
+// motif, indépendant de la langue, ses détails sont repris et {reason} est remplacé par son message traduit
+func (e *Error) Because(reason *Error) *Error {
+	clone := *e
+	clone.Details = maps.Clone(e.Details)
+	if clone.Details == nil {
+		clone.Details = make(map[string]any)
+	}
+	maps.Copy(clone.Details, reason.Details)
+	clone.Details["reason"] = reason.Code
+	clone.reason = reason
+	return &clone
+}
+
+// Wrap retourne une copie de l'erreur avec sa cause, journalisée mais jamais renvoyée au client
+func (e *Error) Wrap(err error) *Error {
+	clone := *e
+	clone.cause = err
+	return &clone
+}
+
+// Message retourne le message dans la langue demandée (français par défaut)
+func (e *Error) Message(lang string) string {
+	message, ok := e.messages[lang]
+	if !ok {
+		message = e.messages[DefaultLang]
+	}
+	if e.reason != nil {
+		message = strings.ReplaceAll(message, "{reason}", e.reason.Message(lang))
+	}
+
+	for key, value := range e.Details {
+		message = strings.ReplaceAll(message, "{"+key+"}", fmt.Sprint(value))
+	}
+	return message
+}
+
+func (e *Error) Error() string {
+	if e.cause != nil {
+		return e.Message(DefaultLang) + ": " + e.cause.Error()
+	}
+	return e.Message(DefaultLang)
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Is compare les codes, pour que errors.Is reconnaisse une sentinelle complétée de détails
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Response représente le corps JSON des réponses d'erreur de l'API
+type Response struct {
+	Code    string         `json:"code"`              // Code stable, exploitable par les clients
+	Message string         `json:"message"`           // Message dans la langue demandée par Accept-Language
+	Details map[string]any `json:"details,omitempty"` // Valeurs insérées dans le message
+}
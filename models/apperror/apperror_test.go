@@ -0,0 +1,62 @@
+package apperror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+var errTest = New("test.invalid_time", http.StatusUnprocessableEntity, "temps invalide: {seconds} secondes", "invalid time: {seconds} seconds")
+
+func TestError(t *testing.T) {
+	err := fmt.Errorf("options: %w", errTest.With("seconds", -1))
+
+	var appErr *Error
+	if !errors.As(err, &appErr) || !errors.Is(err, errTest) {
+		t.Fatalf("l'erreur métier devrait être retrouvée dans %v", err)
+	}
+	if appErr.Message(LangFR) != "temps invalide: -1 secondes" || appErr.Message(LangEN) != "invalid time: -1 seconds" {
+		t.Errorf("messages inattendus: %q, %q", appErr.Message(LangFR), appErr.Message(LangEN))
+	}
+	if appErr.Message("de") != appErr.Message(LangFR) {
+		t.Error("une langue inconnue devrait utiliser le français")
+	}
+	if errTest.Details != nil {
+		t.Error("With ne doit pas modifier la sentinelle")
+	}
+
+	cause := errors.New("connexion refusée")
+	if wrapped := errTest.Wrap(cause); !errors.Is(wrapped, cause) || wrapped.Message(LangFR) != "temps invalide: {seconds} secondes" {
+		t.Errorf("cause mal enveloppée: %v", wrapped)
+	}
+}
+
+func TestBecause(t *testing.T) {
+	invalid := New("test.invalid_options", http.StatusBadRequest, "options invalides: {reason}", "invalid options: {reason}")
+	err := invalid.Because(errTest.With("seconds", -1))
+
+	if err.Message(LangFR) != "options invalides: temps invalide: -1 secondes" || err.Message(LangEN) != "invalid options: invalid time: -1 seconds" {
+		t.Errorf("messages inattendus: %q, %q", err.Message(LangFR), err.Message(LangEN))
+	}
+	if err.Details["reason"] != errTest.Code || err.Details["seconds"] != -1 {
+		t.Errorf("détails inattendus: %v", err.Details)
+	}
+}
+
+func TestLanguage(t *testing.T) {
+	tests := map[string]string{
+		"":                        LangFR,
+		"en":                      LangEN,
+		"en-US,en;q=0.9":          LangEN,
+		"de-DE,en;q=0.8,fr;q=0.9": LangFR,
+		"fr-CA;q=0.5,en-GB;q=0.7": LangEN,
+		"de,es":                   LangFR,
+		"en;q=abc,fr;q=0.1":       LangFR,
+	}
+	for header, expected := range tests {
+		if lang := Language(header); lang != expected {
+			t.Errorf("Language(%q) = %s, attendu %s", header, lang, expected)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package apperror
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// supportedLangs liste les langues disposant de messages
+var supportedLangs = []string{LangFR, LangEN}
+
+// Language choisit la langue des messages à partir d'un en-tête Accept-Language
+func Language(acceptLanguage string) string {
+	best, bestWeight := DefaultLang, 0.0
+
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		lang, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if !slices.Contains(supportedLangs, lang) {
+			continue
+		}
+
+		weight := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			weight = parsed
+		}
+
+		if weight > bestWeight {
+			best, bestWeight = lang, weight
+		}
+	}
+
+	return best
+}
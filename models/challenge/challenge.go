@@ -2,26 +2,62 @@ package challenge
 
 import (
 	"fmt"
+	"quarto/models/friend"
 	"quarto/models/game"
+	"quarto/models/user"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 // SendChallenge envoie un défi à un autre joueur
-func SendChallenge(challengerID, challengedID int64, message string) (*Challenge, error) {
+func SendChallenge(challengerID, challengedID int64, message string, optionsRequest OptionsRequest) (*Challenge, error) {
 	// Vérifier que le joueur ne se défie pas lui-même
 	if challengerID == challengedID {
-		return nil, fmt.Errorf("vous ne pouvez pas vous défier vous-même")
+		return nil, ErrSelfChallenge
+	}
+
+	// Vérifier qu'aucun des deux joueurs n'a bloqué l'autre
+	blocked, err := friend.IsBlockedBetween(challengerID, challengedID)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la vérification des blocages: %w", err)
+	}
+	if blocked {
+		return nil, ErrPlayerBlocked
+	}
+
+	// Respecter la préférence "seuls mes amis peuvent me défier"
+	settings, err := user.GetUserSettings(challengedID)
+	if err != nil {
+		return nil, err
+	}
+	if settings.FriendsOnlyChallenges {
+		areFriends, err := friend.AreFriends(challengerID, challengedID)
+		if err != nil {
+			return nil, fmt.Errorf("erreur lors de la vérification des amis: %w", err)
+		}
+		if !areFriends {
+			return nil, ErrFriendsOnly
+		}
 	}
 
 	// Vérifier qu'il n'y a pas déjà un défi en attente entre ces joueurs
 	existingChallenge, err := GetPendingChallengeBetween(challengerID, challengedID)
 	if err != nil {
-		return nil, fmt.Errorf("erreur lors de la vérification des défis existants: %v", err)
+		return nil, fmt.Errorf("erreur lors de la vérification des défis existants: %w", err)
 	}
 	if existingChallenge != nil {
-		return nil, fmt.Errorf("un défi est déjà en attente entre ces joueurs")
+		return nil, ErrAlreadyPending
+	}
+
+	options, err := optionsRequest.ToOptions(true)
+	if err != nil {
+		return nil, err
+	}
+
+	expiry, err := optionsRequest.Expiry()
+	if err != nil {
+		return nil, err
 	}
 
 	// Créer le défi
@@ -33,85 +69,135 @@ func SendChallenge(challengerID, challengedID int64, message string) (*Challenge
 		Message:      message,
 		CreatedAt:    time.Now(),
 		UpdatedAt:    time.Now(),
-		ExpiresAt:    time.Now().Add(24 * time.Hour), // Expire dans 24h
+		ExpiresAt:    time.Now().Add(expiry),
+		Options:      options,
+		ProposedBy:   challengerID,
 	}
 
 	// Sauvegarder en base
 	err = CreateChallenge(challenge)
 	if err != nil {
-		return nil, fmt.Errorf("erreur lors de la création du défi: %v", err)
+		return nil, fmt.Errorf("erreur lors de la création du défi: %w", err)
 	}
 
 	return &challenge, nil
 }
 
-// AcceptChallenge accepte un défi et crée une partie
-func AcceptChallenge(challengeID string, challengedID int64) (*Challenge, *game.Game, error) {
+// CounterChallenge modifie les options d'un défi en attente ; l'autre joueur devra alors répondre
+func CounterChallenge(challengeID string, userID int64, optionsRequest OptionsRequest) (*Challenge, error) {
 	// Récupérer le défi
 	challenge, err := GetChallengeByID(challengeID)
 	if err != nil {
-		return nil, nil, fmt.Errorf("défi non trouvé: %v", err)
+		return nil, ErrChallengeNotFound.Wrap(err)
 	}
 
 	// Vérifier que c'est le bon joueur qui répond
-	if challenge.ChallengedID != challengedID {
-		return nil, nil, fmt.Errorf("vous n'êtes pas autorisé à répondre à ce défi")
+	if !challenge.ExpectsResponseFrom(userID) {
+		return nil, ErrChallengeForbidden
+	}
+
+	// Vérifier que le défi peut être modifié
+	if !challenge.CanRespond() {
+		return nil, ErrChallengeClosed
+	}
+
+	options, err := optionsRequest.ToOptions(challenge.ChallengerID == userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Conserver l'expiration actuelle sauf si une nouvelle durée est demandée
+	expiresAt := challenge.ExpiresAt
+	if optionsRequest.ExpiresInMinutes != 0 {
+		expiry, err := optionsRequest.Expiry()
+		if err != nil {
+			return nil, err
+		}
+		expiresAt = time.Now().Add(expiry)
+	}
+
+	// Mettre à jour le défi
+	err = UpdateChallengeOptions(challengeID, options, userID, expiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la contre-proposition: %w", err)
+	}
+
+	// Récupérer le défi mis à jour
+	updatedChallenge, err := GetChallengeByID(challengeID)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la récupération du défi mis à jour: %w", err)
+	}
+
+	return updatedChallenge, nil
+}
+
+// AcceptChallenge accepte un défi et crée une partie avec les options du défi
+func AcceptChallenge(challengeID string, userID int64) (*Challenge, *game.Game, error) {
+	// Récupérer le défi
+	challenge, err := GetChallengeByID(challengeID)
+	if err != nil {
+		return nil, nil, ErrChallengeNotFound.Wrap(err)
+	}
+
+	// Vérifier que c'est le bon joueur qui répond
+	if !challenge.ExpectsResponseFrom(userID) {
+		return nil, nil, ErrChallengeForbidden
 	}
 
 	// Vérifier que le défi peut être accepté
 	if !challenge.CanRespond() {
-		return nil, nil, fmt.Errorf("ce défi ne peut plus être accepté (expiré ou déjà traité)")
+		return nil, nil, ErrChallengeClosed
 	}
 
-	// Créer une nouvelle partie
-	newGame, err := game.CreateNewGame(challenge.ChallengerID, challenge.ChallengedID)
+	// Créer une nouvelle partie, le premier joueur commence
+	newGame, err := challenge.createGame()
 	if err != nil {
-		return nil, nil, fmt.Errorf("erreur lors de la création de la partie: %v", err)
+		return nil, nil, fmt.Errorf("erreur lors de la création de la partie: %w", err)
 	}
 
 	// Mettre à jour le défi
 	err = UpdateChallengeStatus(challengeID, "accepted", &newGame.ID)
 	if err != nil {
-		return nil, nil, fmt.Errorf("erreur lors de l'acceptation du défi: %v", err)
+		return nil, nil, fmt.Errorf("erreur lors de l'acceptation du défi: %w", err)
 	}
 
 	// Récupérer le défi mis à jour
 	updatedChallenge, err := GetChallengeByID(challengeID)
 	if err != nil {
-		return nil, nil, fmt.Errorf("erreur lors de la récupération du défi mis à jour: %v", err)
+		return nil, nil, fmt.Errorf("erreur lors de la récupération du défi mis à jour: %w", err)
 	}
 
 	return updatedChallenge, &newGame, nil
 }
 
-// DeclineChallenge refuse un défi
-func DeclineChallenge(challengeID string, challengedID int64) (*Challenge, error) {
+// DeclineChallenge refuse un défi (ou une contre-proposition)
+func DeclineChallenge(challengeID string, userID int64) (*Challenge, error) {
 	// Récupérer le défi
 	challenge, err := GetChallengeByID(challengeID)
 	if err != nil {
-		return nil, fmt.Errorf("défi non trouvé: %v", err)
+		return nil, ErrChallengeNotFound.Wrap(err)
 	}
 
 	// Vérifier que c'est le bon joueur qui répond
-	if challenge.ChallengedID != challengedID {
-		return nil, fmt.Errorf("vous n'êtes pas autorisé à répondre à ce défi")
+	if !challenge.ExpectsResponseFrom(userID) {
+		return nil, ErrChallengeForbidden
 	}
 
 	// Vérifier que le défi peut être refusé
 	if !challenge.CanRespond() {
-		return nil, fmt.Errorf("ce défi ne peut plus être refusé (expiré ou déjà traité)")
+		return nil, ErrChallengeClosed
 	}
 
 	// Mettre à jour le défi
 	err = UpdateChallengeStatus(challengeID, "declined", nil)
 	if err != nil {
-		return nil, fmt.Errorf("erreur lors du refus du défi: %v", err)
+		return nil, fmt.Errorf("erreur lors du refus du défi: %w", err)
 	}
 
 	// Récupérer le défi mis à jour
 	updatedChallenge, err := GetChallengeByID(challengeID)
 	if err != nil {
-		return nil, fmt.Errorf("erreur lors de la récupération du défi mis à jour: %v", err)
+		return nil, fmt.Errorf("erreur lors de la récupération du défi mis à jour: %w", err)
 	}
 
 	return updatedChallenge, nil
@@ -122,39 +208,53 @@ func CancelChallenge(challengeID string, challengerID int64) (*Challenge, error)
 	// Récupérer le défi
 	challenge, err := GetChallengeByID(challengeID)
 	if err != nil {
-		return nil, fmt.Errorf("défi non trouvé: %v", err)
+		return nil, ErrChallengeNotFound.Wrap(err)
 	}
 
 	// Vérifier que c'est le bon joueur qui annule
 	if challenge.ChallengerID != challengerID {
-		return nil, fmt.Errorf("vous n'êtes pas autorisé à annuler ce défi")
+		return nil, ErrCancelForbidden
 	}
 
 	// Vérifier que le défi peut être annulé
 	if challenge.Status != "pending" {
-		return nil, fmt.Errorf("ce défi ne peut plus être annulé")
+		return nil, ErrChallengeClosed
 	}
 
 	// Mettre à jour le défi
 	err = UpdateChallengeStatus(challengeID, "cancelled", nil)
 	if err != nil {
-		return nil, fmt.Errorf("erreur lors de l'annulation du défi: %v", err)
+		return nil, fmt.Errorf("erreur lors de l'annulation du défi: %w", err)
 	}
 
 	// Récupérer le défi mis à jour
 	updatedChallenge, err := GetChallengeByID(challengeID)
 	if err != nil {
-		return nil, fmt.Errorf("erreur lors de la récupération du défi mis à jour: %v", err)
+		return nil, fmt.Errorf("erreur lors de la récupération du défi mis à jour: %w", err)
 	}
 
 	return updatedChallenge, nil
 }
 
+// createGame crée la partie d'un défi accepté (rattachée à la série de la partie précédente pour une revanche)
+func (c Challenge) createGame() (game.Game, error) {
+	player1ID, player2ID := c.Players()
+	if c.RematchOf == "" {
+		return game.CreateNewGame(player1ID, player2ID, c.Options.GameOptions())
+	}
+
+	previous, err := game.GetGameByID(c.RematchOf)
+	if err != nil {
+		return game.Game{}, err
+	}
+	return game.CreateRematchGame(previous, player1ID, player2ID, c.Options.GameOptions())
+}
+
 // GetMyChallenges récupère tous les défis d'un utilisateur organisés par type
 func GetMyChallenges(userID int64) (*ChallengeListResponse, error) {
 	challenges, err := GetUserChallenges(userID)
 	if err != nil {
-		return nil, fmt.Errorf("erreur lors de la récupération des défis: %v", err)
+		return nil, fmt.Errorf("erreur lors de la récupération des défis: %w", err)
 	}
 
 	response := &ChallengeListResponse{
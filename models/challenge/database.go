@@ -2,13 +2,20 @@ package challenge
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"quarto/models/postgresql"
+	"quarto/models/user"
 	"time"
 
 	"github.com/jackc/pgx/v4"
 )
 
+// challengeColumns liste les colonnes lues par ScanChallenge, dans l'ordre
+const challengeColumns = `id, challenger_id, challenged_id, status, message, game_id,
+			created_at, updated_at, expires_at, responded_at, options, proposed_by,
+			visibility, allow_guest, rematch_of`
+
 // ScanChallenge scanne une ligne de résultat SQL en structure Challenge
 func ScanChallenge(row pgx.Row) (c Challenge, err error) {
 	var (
@@ -17,6 +24,10 @@ func ScanChallenge(row pgx.Row) (c Challenge, err error) {
 		status, message                              sql.NullString
 		gameID                                       sql.NullString
 		createdAt, updatedAt, expiresAt, respondedAt sql.NullTime
+		options                                      sql.NullString
+		proposedBy                                   sql.NullInt64
+		visibility, rematchOf                        sql.NullString
+		allowGuest                                   sql.NullBool
 	)
 
 	err = row.Scan(
@@ -30,12 +41,33 @@ func ScanChallenge(row pgx.Row) (c Challenge, err error) {
 		&updatedAt,
 		&expiresAt,
 		&respondedAt,
+		&options,
+		&proposedBy,
+		&visibility,
+		&allowGuest,
+		&rematchOf,
 	)
 
 	if err != nil {
 		return
 	}
 
+	var challengeOptions Options
+	if options.Valid && options.String != "" {
+		if err = json.Unmarshal([]byte(options.String), &challengeOptions); err != nil {
+			err = fmt.Errorf("erreur de parsing des options du défi: %w", err)
+			return
+		}
+	}
+	if challengeOptions.Starter == "" {
+		challengeOptions.Starter = StarterChallenger
+	}
+
+	// Les anciens défis n'ont pas d'auteur de proposition : c'est le challenger
+	if !proposedBy.Valid {
+		proposedBy.Int64 = challengerID.Int64
+	}
+
 	c = Challenge{
 		ID:           id.String,
 		ChallengerID: challengerID.Int64,
@@ -47,6 +79,14 @@ func ScanChallenge(row pgx.Row) (c Challenge, err error) {
 		UpdatedAt:    updatedAt.Time,
 		ExpiresAt:    expiresAt.Time,
 		RespondedAt:  respondedAt.Time,
+		Options:      challengeOptions,
+		ProposedBy:   proposedBy.Int64,
+		Visibility:   visibility.String,
+		AllowGuest:   allowGuest.Bool,
+		RematchOf:    rematchOf.String,
+	}
+	if c.Visibility == "" {
+		c.Visibility = VisibilityDirect
 	}
 
 	return
@@ -56,33 +96,87 @@ func ScanChallenge(row pgx.Row) (c Challenge, err error) {
 func CreateChallenge(challenge Challenge) error {
 	sqlCo, err := pgx.ConnectConfig(postgresql.SQLCtx, postgresql.SQLConn)
 	if err != nil {
-		return fmt.Errorf("erreur de connexion DB: %v", err)
+		return fmt.Errorf("erreur de connexion DB: %w", err)
 	}
 	defer sqlCo.Close(postgresql.SQLCtx)
 
+	optionsJSON, err := json.Marshal(challenge.Options)
+	if err != nil {
+		return fmt.Errorf("erreur de sérialisation des options du défi: %w", err)
+	}
+
+	// Un défi ouvert n'a pas encore d'adversaire
+	var challengedID any
+	if !challenge.IsOpen() {
+		challengedID = challenge.ChallengedID
+	}
+
 	query := `
-		INSERT INTO challenges (id, challenger_id, challenged_id, status, message, created_at, updated_at, expires_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+		INSERT INTO challenges (id, challenger_id, challenged_id, status, message, created_at, updated_at, expires_at,
+			options, proposed_by, visibility, allow_guest, rematch_of)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NULLIF($13, ''))`
 
 	_, err = sqlCo.Exec(postgresql.SQLCtx, query,
-		challenge.ID, challenge.ChallengerID, challenge.ChallengedID,
+		challenge.ID, challenge.ChallengerID, challengedID,
 		challenge.Status, challenge.Message, challenge.CreatedAt,
-		challenge.UpdatedAt, challenge.ExpiresAt)
+		challenge.UpdatedAt, challenge.ExpiresAt, string(optionsJSON), challenge.ProposedBy,
+		challenge.Visibility, challenge.AllowGuest, challenge.RematchOf)
 
 	return err
 }
 
+// CreateRematchChallenge insère une proposition de revanche si aucune n'est déjà en attente pour la partie ;
+// retourne faux si une autre proposition l'a devancée. Les propositions dépassées mais pas encore expirées par la
+// tâche planifiée sont expirées au passage, pour ne pas bloquer la nouvelle.
+func CreateRematchChallenge(challenge Challenge) (bool, error) {
+	sqlCo, err := pgx.ConnectConfig(postgresql.SQLCtx, postgresql.SQLConn)
+	if err != nil {
+		return false, fmt.Errorf("erreur de connexion DB: %w", err)
+	}
+	defer sqlCo.Close(postgresql.SQLCtx)
+
+	optionsJSON, err := json.Marshal(challenge.Options)
+	if err != nil {
+		return false, fmt.Errorf("erreur de sérialisation des options du défi: %w", err)
+	}
+
+	query := `
+		UPDATE challenges 
+		SET status = 'expired', updated_at = NOW()
+		WHERE rematch_of = $1 AND status = 'pending' AND expires_at <= NOW()`
+	if _, err = sqlCo.Exec(postgresql.SQLCtx, query, challenge.RematchOf); err != nil {
+		return false, err
+	}
+
+	// L'index unique partiel idx_challenges_pending_rematch départage les propositions simultanées
+	query = `
+		INSERT INTO challenges (id, challenger_id, challenged_id, status, message, created_at, updated_at, expires_at,
+			options, proposed_by, visibility, allow_guest, rematch_of)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (rematch_of) WHERE status = 'pending' DO NOTHING`
+
+	cmd, err := sqlCo.Exec(postgresql.SQLCtx, query,
+		challenge.ID, challenge.ChallengerID, challenge.ChallengedID,
+		challenge.Status, challenge.Message, challenge.CreatedAt,
+		challenge.UpdatedAt, challenge.ExpiresAt, string(optionsJSON), challenge.ProposedBy,
+		challenge.Visibility, challenge.AllowGuest, challenge.RematchOf)
+	if err != nil {
+		return false, err
+	}
+
+	return cmd.RowsAffected() == 1, nil
+}
+
 // GetChallengeByID récupère un défi par son ID
 func GetChallengeByID(challengeID string) (*Challenge, error) {
 	sqlCo, err := pgx.ConnectConfig(postgresql.SQLCtx, postgresql.SQLConn)
 	if err != nil {
-		return nil, fmt.Errorf("erreur de connexion DB: %v", err)
+		return nil, fmt.Errorf("erreur de connexion DB: %w", err)
 	}
 	defer sqlCo.Close(postgresql.SQLCtx)
 
 	query := `
-		SELECT id, challenger_id, challenged_id, status, message, game_id,
-			created_at, updated_at, expires_at, responded_at
+		SELECT ` + challengeColumns + `
 		FROM challenges WHERE id = $1`
 
 	row := sqlCo.QueryRow(postgresql.SQLCtx, query, challengeID)
@@ -98,13 +192,12 @@ func GetChallengeByID(challengeID string) (*Challenge, error) {
 func GetPendingChallengeBetween(challengerID, challengedID int64) (*Challenge, error) {
 	sqlCo, err := pgx.ConnectConfig(postgresql.SQLCtx, postgresql.SQLConn)
 	if err != nil {
-		return nil, fmt.Errorf("erreur de connexion DB: %v", err)
+		return nil, fmt.Errorf("erreur de connexion DB: %w", err)
 	}
 	defer sqlCo.Close(postgresql.SQLCtx)
 
 	query := `
-		SELECT id, challenger_id, challenged_id, status, message, game_id,
-			created_at, updated_at, expires_at, responded_at
+		SELECT ` + challengeColumns + `
 		FROM challenges 
 		WHERE ((challenger_id = $1 AND challenged_id = $2) OR (challenger_id = $2 AND challenged_id = $1))
 		AND status = 'pending'
@@ -122,17 +215,45 @@ func GetPendingChallengeBetween(challengerID, challengedID int64) (*Challenge, e
 	return &challenge, nil
 }
 
+// GetPendingRematch récupère la proposition de revanche en attente pour une partie (nil si aucune)
+func GetPendingRematch(gameID string) (*Challenge, error) {
+	sqlCo, err := pgx.ConnectConfig(postgresql.SQLCtx, postgresql.SQLConn)
+	if err != nil {
+		return nil, fmt.Errorf("erreur de connexion DB: %w", err)
+	}
+	defer sqlCo.Close(postgresql.SQLCtx)
+
+	query := `
+		SELECT ` + challengeColumns + `
+		FROM challenges 
+		WHERE rematch_of = $1
+		AND status = 'pending'
+		AND expires_at > NOW()
+		ORDER BY created_at DESC
+		LIMIT 1`
+
+	row := sqlCo.QueryRow(postgresql.SQLCtx, query, gameID)
+	challenge, err := ScanChallenge(row)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &challenge, nil
+}
+
 // GetUserChallenges récupère tous les défis d'un utilisateur (envoyés et reçus)
 func GetUserChallenges(userID int64) ([]Challenge, error) {
 	sqlCo, err := pgx.ConnectConfig(postgresql.SQLCtx, postgresql.SQLConn)
 	if err != nil {
-		return nil, fmt.Errorf("erreur de connexion DB: %v", err)
+		return nil, fmt.Errorf("erreur de connexion DB: %w", err)
 	}
 	defer sqlCo.Close(postgresql.SQLCtx)
 
 	query := `
-		SELECT id, challenger_id, challenged_id, status, message, game_id,
-			created_at, updated_at, expires_at, responded_at
+		SELECT ` + challengeColumns + `
 		FROM challenges 
 		WHERE challenger_id = $1 OR challenged_id = $1
 		ORDER BY created_at DESC`
@@ -159,7 +280,7 @@ func GetUserChallenges(userID int64) ([]Challenge, error) {
 func UpdateChallengeStatus(challengeID, status string, gameID *string) error {
 	sqlCo, err := pgx.ConnectConfig(postgresql.SQLCtx, postgresql.SQLConn)
 	if err != nil {
-		return fmt.Errorf("erreur de connexion DB: %v", err)
+		return fmt.Errorf("erreur de connexion DB: %w", err)
 	}
 	defer sqlCo.Close(postgresql.SQLCtx)
 
@@ -184,19 +305,181 @@ func UpdateChallengeStatus(challengeID, status string, gameID *string) error {
 	return err
 }
 
-// CleanupExpiredChallenges supprime ou marque comme expirés les défis anciens
-func CleanupExpiredChallenges() error {
+// GetOpenChallenges récupère les défis ouverts du lobby visibles par un utilisateur
+func GetOpenChallenges(userID int64) ([]Challenge, error) {
 	sqlCo, err := pgx.ConnectConfig(postgresql.SQLCtx, postgresql.SQLConn)
 	if err != nil {
-		return fmt.Errorf("erreur de connexion DB: %v", err)
+		return nil, fmt.Errorf("erreur de connexion DB: %w", err)
+	}
+	defer sqlCo.Close(postgresql.SQLCtx)
+
+	query := `
+		SELECT ` + challengeColumns + `
+		FROM challenges c
+		WHERE visibility = 'open'
+		AND status = 'pending'
+		AND expires_at > NOW()
+		AND NOT EXISTS (
+			SELECT 1 FROM blocks b
+			WHERE (b.blocker_id = c.challenger_id AND b.blocked_id = $1)
+			OR (b.blocker_id = $1 AND b.blocked_id = c.challenger_id)
+		)
+		ORDER BY created_at DESC`
+
+	rows, err := sqlCo.Query(postgresql.SQLCtx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	challenges := make([]Challenge, 0)
+	for rows.Next() {
+		challenge, err := ScanChallenge(rows)
+		if err != nil {
+			return nil, err
+		}
+		challenges = append(challenges, challenge)
+	}
+
+	return challenges, nil
+}
+
+// ClaimOpenChallenge attribue un défi ouvert à un adversaire ; retourne false si quelqu'un l'a déjà pris
+func ClaimOpenChallenge(challengeID string, challengedID int64) (bool, error) {
+	sqlCo, err := pgx.ConnectConfig(postgresql.SQLCtx, postgresql.SQLConn)
+	if err != nil {
+		return false, fmt.Errorf("erreur de connexion DB: %w", err)
 	}
 	defer sqlCo.Close(postgresql.SQLCtx)
 
 	query := `
 		UPDATE challenges 
-		SET status = 'expired', updated_at = NOW()
-		WHERE status = 'pending' AND expires_at <= NOW()`
+		SET challenged_id = $1, updated_at = NOW()
+		WHERE id = $2 AND challenged_id IS NULL AND status = 'pending' AND expires_at > NOW()`
+
+	cmd, err := sqlCo.Exec(postgresql.SQLCtx, query, challengedID, challengeID)
+	if err != nil {
+		return false, err
+	}
+
+	return cmd.RowsAffected() == 1, nil
+}
+
+// ClaimOpenChallengeAsGuest réserve un défi ouvert pour un nouveau compte invité, créé dans la même transaction :
+// aucun compte n'est créé si le défi n'est plus disponible. Retourne le compte si le défi a été réservé.
+func ClaimOpenChallengeAsGuest(challengeID string) (guest user.User, claimed bool, err error) {
+	sqlCo, err := pgx.ConnectConfig(postgresql.SQLCtx, postgresql.SQLConn)
+	if err != nil {
+		return user.User{}, false, fmt.Errorf("erreur de connexion DB: %w", err)
+	}
+	defer sqlCo.Close(postgresql.SQLCtx)
+
+	tx, err := sqlCo.Begin(postgresql.SQLCtx)
+	if err != nil {
+		return user.User{}, false, err
+	}
+	defer tx.Rollback(postgresql.SQLCtx)
+
+	// Verrouiller le défi tant qu'il est disponible, pour que personne ne le rejoigne avant la fin de la transaction
+	query := `
+		SELECT id FROM challenges
+		WHERE id = $1 AND challenged_id IS NULL AND status = 'pending' AND expires_at > NOW()
+		FOR UPDATE`
+	var id string
+	err = tx.QueryRow(postgresql.SQLCtx, query, challengeID).Scan(&id)
+	if err == pgx.ErrNoRows {
+		return user.User{}, false, nil
+	}
+	if err != nil {
+		return user.User{}, false, err
+	}
+
+	guest, err = user.InsertGuestAccount(tx)
+	if err != nil {
+		return user.User{}, false, fmt.Errorf("erreur lors de la création du compte invité: %w", err)
+	}
+
+	query = `
+		UPDATE challenges 
+		SET challenged_id = $1, updated_at = NOW()
+		WHERE id = $2`
+	if _, err = tx.Exec(postgresql.SQLCtx, query, guest.ID, challengeID); err != nil {
+		return user.User{}, false, err
+	}
+
+	if err = tx.Commit(postgresql.SQLCtx); err != nil {
+		return user.User{}, false, err
+	}
+	return guest, true, nil
+}
+
+// ReleaseOpenChallenge rend à nouveau disponible un défi ouvert dont la partie n'a pas pu être créée
+func ReleaseOpenChallenge(challengeID string) error {
+	sqlCo, err := pgx.ConnectConfig(postgresql.SQLCtx, postgresql.SQLConn)
+	if err != nil {
+		return fmt.Errorf("erreur de connexion DB: %w", err)
+	}
+	defer sqlCo.Close(postgresql.SQLCtx)
+
+	query := `
+		UPDATE challenges 
+		SET challenged_id = NULL, updated_at = NOW()
+		WHERE id = $1 AND status = 'pending'`
 
-	_, err = sqlCo.Exec(postgresql.SQLCtx, query)
+	_, err = sqlCo.Exec(postgresql.SQLCtx, query, challengeID)
 	return err
 }
+
+// UpdateChallengeOptions enregistre une contre-proposition d'options sur un défi
+func UpdateChallengeOptions(challengeID string, options Options, proposedBy int64, expiresAt time.Time) error {
+	sqlCo, err := pgx.ConnectConfig(postgresql.SQLCtx, postgresql.SQLConn)
+	if err != nil {
+		return fmt.Errorf("erreur de connexion DB: %w", err)
+	}
+	defer sqlCo.Close(postgresql.SQLCtx)
+
+	optionsJSON, err := json.Marshal(options)
+	if err != nil {
+		return fmt.Errorf("erreur de sérialisation des options du défi: %w", err)
+	}
+
+	query := `
+		UPDATE challenges 
+		SET options = $1, proposed_by = $2, expires_at = $3, updated_at = $4
+		WHERE id = $5 AND status = 'pending'`
+
+	_, err = sqlCo.Exec(postgresql.SQLCtx, query, string(optionsJSON), proposedBy, expiresAt, time.Now(), challengeID)
+	return err
+}
+
+// CleanupExpiredChallenges marque comme expirés les défis en attente dépassés et retourne les défis concernés
+func CleanupExpiredChallenges() ([]Challenge, error) {
+	sqlCo, err := pgx.ConnectConfig(postgresql.SQLCtx, postgresql.SQLConn)
+	if err != nil {
+		return nil, fmt.Errorf("erreur de connexion DB: %w", err)
+	}
+	defer sqlCo.Close(postgresql.SQLCtx)
+
+	query := `
+		UPDATE challenges 
+		SET status = 'expired', updated_at = NOW()
+		WHERE status = 'pending' AND expires_at <= NOW()
+		RETURNING ` + challengeColumns
+
+	rows, err := sqlCo.Query(postgresql.SQLCtx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var challenges []Challenge
+	for rows.Next() {
+		challenge, err := ScanChallenge(rows)
+		if err != nil {
+			return nil, err
+		}
+		challenges = append(challenges, challenge)
+	}
+
+	return challenges, rows.Err()
+}
@@ -0,0 +1,40 @@
+package challenge
+
+import (
+	"net/http"
+	"quarto/models/apperror"
+)
+
+// Erreurs métier des défis
+var (
+	ErrChallengeNotFound  = apperror.New("challenge.not_found", http.StatusNotFound, "défi non trouvé", "challenge not found")
+	ErrChallengeForbidden = apperror.New("challenge.forbidden", http.StatusForbidden, "vous n'êtes pas autorisé à répondre à ce défi", "you are not allowed to respond to this challenge")
+	ErrCancelForbidden    = apperror.New("challenge.cancel_forbidden", http.StatusForbidden, "vous n'êtes pas autorisé à annuler ce défi", "you are not allowed to cancel this challenge")
+	ErrChallengeClosed    = apperror.New("challenge.closed", http.StatusConflict, "ce défi a expiré ou a déjà été traité", "this challenge has expired or has already been handled")
+	ErrSelfChallenge      = apperror.New("challenge.self", http.StatusUnprocessableEntity, "vous ne pouvez pas vous défier vous-même", "you cannot challenge yourself")
+	ErrPlayerBlocked      = apperror.New("challenge.blocked", http.StatusForbidden, "vous ne pouvez pas défier ce joueur", "you cannot challenge this player")
+	ErrFriendsOnly        = apperror.New("challenge.friends_only", http.StatusForbidden, "ce joueur n'accepte que les défis de ses amis", "this player only accepts challenges from friends")
+	ErrAlreadyPending     = apperror.New("challenge.already_pending", http.StatusConflict, "un défi est déjà en attente entre ces joueurs", "a challenge is already pending between these players")
+
+	ErrNotOpen           = apperror.New("challenge.not_open", http.StatusConflict, "ce défi n'est pas ouvert", "this challenge is not open")
+	ErrOwnChallenge      = apperror.New("challenge.own", http.StatusUnprocessableEntity, "vous ne pouvez pas accepter votre propre défi", "you cannot accept your own challenge")
+	ErrAlreadyTaken      = apperror.New("challenge.already_taken", http.StatusConflict, "ce défi a déjà été accepté par un autre joueur", "this challenge has already been accepted by another player")
+	ErrLoginRequired     = apperror.New("challenge.login_required", http.StatusUnauthorized, "vous devez être connecté pour accepter ce défi", "you must be logged in to accept this challenge")
+	ErrInvalidInvite     = apperror.New("challenge.invalid_invite", http.StatusNotFound, "lien d'invitation invalide", "invalid invite link")
+	ErrExpiredInvite     = apperror.New("challenge.expired_invite", http.StatusGone, "ce lien d'invitation a expiré", "this invite link has expired")
+	ErrInviteUnavailable = apperror.New("challenge.invite_unavailable", http.StatusGone, "ce lien d'invitation a déjà été utilisé ou n'est plus valide", "this invite link has already been used or is no longer valid")
+
+	ErrGameNotFinished = apperror.New("challenge.game_not_finished", http.StatusConflict, "la partie n'est pas terminée", "the game is not finished")
+	ErrRematchPlayed   = apperror.New("challenge.rematch_played", http.StatusConflict, "la revanche de cette partie a déjà été jouée", "the rematch of this game has already been played")
+	ErrBotRematch      = apperror.New("challenge.bot_rematch", http.StatusConflict, "le bot ne joue pas de revanche : créez une nouvelle partie contre lui", "the bot does not play rematches: start a new game against it")
+	ErrRematchPending  = apperror.New("challenge.rematch_pending", http.StatusConflict, "une revanche est déjà proposée", "a rematch has already been offered")
+	ErrNoRematch       = apperror.New("challenge.no_rematch", http.StatusNotFound, "aucune revanche n'est proposée pour cette partie", "no rematch has been offered for this game")
+
+	ErrInvalidStarter       = apperror.New("challenge.invalid_starter", http.StatusUnprocessableEntity, "premier joueur invalide: {starter}", "invalid starting player: {starter}")
+	ErrInvalidInitialTime   = apperror.New("challenge.invalid_initial_time", http.StatusUnprocessableEntity, "temps initial invalide: {seconds} secondes", "invalid initial time: {seconds} seconds")
+	ErrInvalidIncrement     = apperror.New("challenge.invalid_increment", http.StatusUnprocessableEntity, "incrément invalide: {seconds} secondes", "invalid increment: {seconds} seconds")
+	ErrIncrementWithoutTime = apperror.New("challenge.increment_without_time", http.StatusUnprocessableEntity, "un incrément nécessite un temps initial", "an increment requires an initial time")
+	ErrInvalidHints         = apperror.New("challenge.invalid_hints", http.StatusUnprocessableEntity, "nombre d'indices invalide: entre 0 et {max}", "invalid number of hints: between 0 and {max}")
+	ErrHintsRated           = apperror.New("challenge.hints_rated", http.StatusUnprocessableEntity, "les indices ne sont pas autorisés dans les parties classées", "hints are not allowed in rated games")
+	ErrInvalidExpiry        = apperror.New("challenge.invalid_expiry", http.StatusUnprocessableEntity, "durée d'expiration invalide: entre {min} et {max} minutes", "invalid expiry: between {min} and {max} minutes")
+)
@@ -0,0 +1,52 @@
+package challenge
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NewInviteCode génère le code signé d'invitation d'un défi, valable jusqu'à son expiration
+func NewInviteCode(challengeID string, expiresAt time.Time) string {
+	payload := challengeID + "|" + strconv.FormatInt(expiresAt.Unix(), 10)
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(payload))
+	return encoded + "." + signInvite(encoded)
+}
+
+// ParseInviteCode vérifie la signature et l'expiration d'un code d'invitation et retourne l'ID du défi
+func ParseInviteCode(code string) (string, error) {
+	encoded, signature, found := strings.Cut(code, ".")
+	if !found || !hmac.Equal([]byte(signature), []byte(signInvite(encoded))) {
+		return "", ErrInvalidInvite
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", ErrInvalidInvite
+	}
+
+	challengeID, expiry, found := strings.Cut(string(payload), "|")
+	if !found || challengeID == "" {
+		return "", ErrInvalidInvite
+	}
+
+	expiresAt, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil {
+		return "", ErrInvalidInvite
+	}
+	if time.Now().Unix() > expiresAt {
+		return "", ErrExpiredInvite
+	}
+
+	return challengeID, nil
+}
+
+// signInvite calcule la signature HMAC d'un code d'invitation
+func signInvite(encoded string) string {
+	mac := hmac.New(sha256.New, InviteSecret)
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:16])
+}
@@ -0,0 +1,42 @@
+package challenge
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInviteCode(t *testing.T) {
+	InviteSecret = []byte("secret de test")
+
+	code := NewInviteCode("challenge-id", time.Now().Add(time.Hour))
+	challengeID, err := ParseInviteCode(code)
+	if err != nil {
+		t.Fatalf("code valide refusé: %v", err)
+	}
+	if challengeID != "challenge-id" {
+		t.Errorf("ID du défi = %q, attendu %q", challengeID, "challenge-id")
+	}
+
+	// Une signature modifiée doit être refusée
+	if _, err := ParseInviteCode(code + "x"); err != ErrInvalidInvite {
+		t.Errorf("signature altérée: erreur = %v, attendu %v", err, ErrInvalidInvite)
+	}
+
+	// Un code signé avec une autre clé doit être refusé
+	InviteSecret = []byte("autre secret")
+	if _, err := ParseInviteCode(code); err != ErrInvalidInvite {
+		t.Errorf("autre clé: erreur = %v, attendu %v", err, ErrInvalidInvite)
+	}
+
+	// Un code expiré doit être refusé
+	expired := NewInviteCode("challenge-id", time.Now().Add(-time.Minute))
+	if _, err := ParseInviteCode(expired); err != ErrExpiredInvite {
+		t.Errorf("code expiré: erreur = %v, attendu %v", err, ErrExpiredInvite)
+	}
+
+	for _, code := range []string{"", "abc", "abc.def", "." + signInvite("")} {
+		if _, err := ParseInviteCode(code); err == nil {
+			t.Errorf("code %q accepté", code)
+		}
+	}
+}
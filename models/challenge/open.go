@@ -0,0 +1,195 @@
+package challenge
+
+import (
+	"fmt"
+	"quarto/models/friend"
+	"quarto/models/game"
+	"quarto/models/user"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/google/uuid"
+)
+
+// CreateOpenChallenge crée un défi sans adversaire désigné, listé dans le lobby ou accessible uniquement par lien
+func CreateOpenChallenge(challengerID int64, req CreateOpenChallengeRequest) (*Challenge, error) {
+	options, err := req.Options.ToOptions(true)
+	if err != nil {
+		return nil, err
+	}
+
+	expiry, err := req.Options.Expiry()
+	if err != nil {
+		return nil, err
+	}
+
+	visibility := VisibilityOpen
+	if req.InviteOnly {
+		visibility = VisibilityInvite
+	}
+
+	challenge := Challenge{
+		ID:           uuid.New().String(),
+		ChallengerID: challengerID,
+		Status:       "pending",
+		Message:      req.Message,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+		ExpiresAt:    time.Now().Add(expiry),
+		Options:      options,
+		ProposedBy:   challengerID,
+		Visibility:   visibility,
+		AllowGuest:   req.AllowGuest,
+	}
+
+	err = CreateChallenge(challenge)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la création du défi: %w", err)
+	}
+
+	return &challenge, nil
+}
+
+// GetLobbyChallenges récupère les défis ouverts que l'utilisateur peut rejoindre
+func GetLobbyChallenges(userID int64) ([]Challenge, error) {
+	challenges, err := GetOpenChallenges(userID)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la récupération des défis ouverts: %w", err)
+	}
+	return challenges, nil
+}
+
+// JoinOpenChallenge rejoint un défi ouvert listé dans le lobby
+func JoinOpenChallenge(challengeID string, userID int64) (*Challenge, *game.Game, error) {
+	challenge, err := GetChallengeByID(challengeID)
+	if err != nil {
+		return nil, nil, ErrChallengeNotFound.Wrap(err)
+	}
+
+	// Un défi sur invitation ne peut être rejoint qu'avec son lien
+	if challenge.Visibility != VisibilityOpen {
+		return nil, nil, ErrNotOpen
+	}
+
+	return joinChallenge(challenge, userID)
+}
+
+// GetInvite retourne le défi correspondant à un code d'invitation
+func GetInvite(code string) (*Challenge, error) {
+	challengeID, err := ParseInviteCode(code)
+	if err != nil {
+		return nil, err
+	}
+
+	challenge, err := GetChallengeByID(challengeID)
+	if err != nil {
+		return nil, ErrChallengeNotFound.Wrap(err)
+	}
+
+	if !challenge.IsOpen() || !challenge.CanRespond() {
+		return nil, ErrInviteUnavailable
+	}
+
+	return challenge, nil
+}
+
+// AcceptInvite accepte un défi via son code d'invitation ; sans session, un compte invité est créé si le défi l'autorise
+func AcceptInvite(code string, userToken *user.UserToken) (*Challenge, *game.Game, *user.UserToken, error) {
+	challenge, err := GetInvite(code)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if userToken != nil {
+		updatedChallenge, newGame, err := joinChallenge(challenge, userToken.User.ID)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return updatedChallenge, newGame, nil, nil
+	}
+
+	if !challenge.AllowGuest {
+		return nil, nil, nil, ErrLoginRequired
+	}
+
+	// Le compte invité n'est créé qu'avec la réservation du défi, dans la même transaction
+	guest, claimed, err := ClaimOpenChallengeAsGuest(challenge.ID)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("erreur lors de l'acceptation du défi: %w", err)
+	}
+	if !claimed {
+		return nil, nil, nil, ErrAlreadyTaken
+	}
+
+	// En cas d'échec, le défi est libéré sans partie : le compte invité n'a plus de raison d'être
+	updatedChallenge, newGame, err := challenge.startClaimed(guest.ID)
+	if err != nil {
+		_ = user.DeleteGuestAccount(guest.ID)
+		return nil, nil, nil, err
+	}
+
+	guestToken := user.GuestSession(guest)
+	return updatedChallenge, newGame, &guestToken, nil
+}
+
+// joinChallenge attribue un défi ouvert à l'utilisateur et crée la partie ; un défi ne peut être rejoint qu'une fois
+func joinChallenge(challenge *Challenge, userID int64) (*Challenge, *game.Game, error) {
+	if challenge.ChallengerID == userID {
+		return nil, nil, ErrOwnChallenge
+	}
+
+	if !challenge.IsOpen() || !challenge.CanRespond() {
+		return nil, nil, ErrChallengeClosed
+	}
+
+	blocked, err := friend.IsBlockedBetween(challenge.ChallengerID, userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("erreur lors de la vérification des blocages: %w", err)
+	}
+	if blocked {
+		return nil, nil, ErrPlayerBlocked
+	}
+
+	// Réserver le défi de manière atomique pour qu'un seul joueur puisse le rejoindre
+	claimed, err := ClaimOpenChallenge(challenge.ID, userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("erreur lors de l'acceptation du défi: %w", err)
+	}
+	if !claimed {
+		return nil, nil, ErrAlreadyTaken
+	}
+
+	return challenge.startClaimed(userID)
+}
+
+// startClaimed crée la partie d'un défi ouvert réservé par l'utilisateur et accepte le défi. En cas d'échec, le défi
+// est libéré et aucune partie n'est conservée ; une fois le défi accepté, la fonction ne peut plus échouer.
+func (challenge *Challenge) startClaimed(userID int64) (*Challenge, *game.Game, error) {
+	challenge.ChallengedID = userID
+
+	newGame, err := challenge.createGame()
+	if err != nil {
+		_ = ReleaseOpenChallenge(challenge.ID)
+		return nil, nil, fmt.Errorf("erreur lors de la création de la partie: %w", err)
+	}
+
+	err = UpdateChallengeStatus(challenge.ID, "accepted", &newGame.ID)
+	if err != nil {
+		// Une partie sans défi accepté serait orpheline : elle est supprimée et le défi rendu disponible
+		_ = game.DeleteGame(newGame.ID)
+		_ = ReleaseOpenChallenge(challenge.ID)
+		return nil, nil, fmt.Errorf("erreur lors de l'acceptation du défi: %w", err)
+	}
+
+	// Le défi est accepté : à défaut de pouvoir le relire, il est complété localement
+	updatedChallenge, err := GetChallengeByID(challenge.ID)
+	if err != nil {
+		log.Warn("Relecture du défi accepté impossible", "challenge", challenge.ID, "error", err)
+		challenge.Status = "accepted"
+		challenge.GameID = newGame.ID
+		challenge.RespondedAt = time.Now()
+		updatedChallenge = challenge
+	}
+
+	return updatedChallenge, &newGame, nil
+}
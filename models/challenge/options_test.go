@@ -0,0 +1,63 @@
+package challenge
+
+import (
+	"quarto/models/game"
+	"testing"
+)
+
+func TestOptionsRequestToOptions(t *testing.T) {
+	tests := []struct {
+		name                 string
+		request              OptionsRequest
+		proposerIsChallenger bool
+		expectedStarter      string
+		expectError          bool
+	}{
+		{"Default starter is the proposer (challenger)", OptionsRequest{}, true, StarterChallenger, false},
+		{"Default starter is the proposer (challenged)", OptionsRequest{}, false, StarterChallenged, false},
+		{"Opponent starts (challenger proposes)", OptionsRequest{Starter: StarterOpponent}, true, StarterChallenged, false},
+		{"Opponent starts (challenged counters)", OptionsRequest{Starter: StarterOpponent}, false, StarterChallenger, false},
+		{"Random starter", OptionsRequest{Starter: StarterRandom}, false, StarterRandom, false},
+		{"Invalid starter", OptionsRequest{Starter: "white"}, true, "", true},
+		{"Negative initial time", OptionsRequest{TimeControl: game.TimeControl{InitialSeconds: -1}}, true, "", true},
+		{"Increment without initial time", OptionsRequest{TimeControl: game.TimeControl{IncrementSeconds: 5}}, true, "", true},
+		{"Squares variant", OptionsRequest{Variant: game.VariantSquares}, true, StarterChallenger, false},
+		{"Unknown variant", OptionsRequest{Variant: "hexagons"}, true, "", true},
+		{"Hints", OptionsRequest{Hints: 3}, true, StarterChallenger, false},
+		{"Too many hints", OptionsRequest{Hints: game.MaxHints + 1}, true, "", true},
+		{"Hints in a rated game", OptionsRequest{Hints: 1, Rated: true}, true, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options, err := tt.request.ToOptions(tt.proposerIsChallenger)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("expected an error, got options %+v", options)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if options.Starter != tt.expectedStarter {
+				t.Errorf("expected starter %s, got %s", tt.expectedStarter, options.Starter)
+			}
+		})
+	}
+}
+
+func TestChallengePlayers(t *testing.T) {
+	c := Challenge{ChallengerID: 1, ChallengedID: 2, Options: Options{Starter: StarterChallenged}}
+	if first, second := c.Players(); first != 2 || second != 1 {
+		t.Errorf("expected challenged player to start, got %d then %d", first, second)
+	}
+
+	c.Options.Starter = StarterRandom
+	for range 20 {
+		first, second := c.Players()
+		if first == second || (first != 1 && first != 2) || (second != 1 && second != 2) {
+			t.Fatalf("invalid random order: %d then %d", first, second)
+		}
+	}
+}
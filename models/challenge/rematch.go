@@ -0,0 +1,110 @@
+package challenge
+
+import (
+	"fmt"
+	"quarto/models/friend"
+	"quarto/models/game"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OfferRematch propose une revanche après une partie terminée, couleurs inversées.
+// Si l'adversaire a déjà proposé la revanche, elle est acceptée et la nouvelle partie est retournée.
+func OfferRematch(gameID string, userID int64) (*Challenge, *game.Game, error) {
+	previous, err := game.GetGame(gameID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if previous.Status != game.StatusFinished {
+		return nil, nil, ErrGameNotFinished
+	}
+	if previous.Options.Bot != nil {
+		return nil, nil, ErrBotRematch
+	}
+
+	played, err := game.HasRematch(gameID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("erreur lors de la vérification des revanches: %w", err)
+	}
+	if played {
+		return nil, nil, ErrRematchPlayed
+	}
+
+	pending, err := GetPendingRematch(gameID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("erreur lors de la vérification des revanches: %w", err)
+	}
+	if pending != nil {
+		if pending.ChallengerID == userID {
+			return nil, nil, ErrRematchPending
+		}
+		// Les deux joueurs veulent leur revanche : lancer la partie
+		return AcceptChallenge(pending.ID, userID)
+	}
+
+	opponentID := previous.Player1ID
+	if opponentID == userID {
+		opponentID = previous.Player2ID
+	}
+
+	blocked, err := friend.IsBlockedBetween(userID, opponentID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("erreur lors de la vérification des blocages: %w", err)
+	}
+	if blocked {
+		return nil, nil, ErrPlayerBlocked
+	}
+
+	// Inverser les couleurs : le joueur 2 de la partie précédente commence
+	starter := StarterChallenged
+	if previous.Player2ID == userID {
+		starter = StarterChallenger
+	}
+
+	challenge := Challenge{
+		ID:           uuid.New().String(),
+		ChallengerID: userID,
+		ChallengedID: opponentID,
+		Status:       "pending",
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+		ExpiresAt:    time.Now().Add(RematchExpiry),
+		Options: Options{
+			Starter:     starter,
+			TimeControl: previous.Options.TimeControl,
+			Rated:       previous.Options.Rated,
+			Variant:     previous.Options.Variant,
+			CallQuarto:  previous.Options.CallQuarto,
+			Hints:       previous.Options.Hints,
+		},
+		ProposedBy: userID,
+		Visibility: VisibilityDirect,
+		RematchOf:  gameID,
+	}
+
+	created, err := CreateRematchChallenge(challenge)
+	if err != nil {
+		return nil, nil, fmt.Errorf("erreur lors de la proposition de revanche: %w", err)
+	}
+	if !created {
+		// Une proposition simultanée a été enregistrée entre la vérification et l'insertion
+		return nil, nil, ErrRematchPending
+	}
+
+	return &challenge, nil, nil
+}
+
+// AcceptRematch accepte la revanche proposée par l'adversaire pour une partie
+func AcceptRematch(gameID string, userID int64) (*Challenge, *game.Game, error) {
+	pending, err := GetPendingRematch(gameID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("erreur lors de la récupération de la revanche: %w", err)
+	}
+	if pending == nil {
+		return nil, nil, ErrNoRematch
+	}
+
+	return AcceptChallenge(pending.ID, userID)
+}
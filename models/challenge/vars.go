@@ -1,6 +1,9 @@
 package challenge
 
 import (
+	"math/rand"
+	"quarto/models/game"
+	"quarto/models/user"
 	"time"
 
 	"github.com/fatih/structs"
@@ -17,12 +20,107 @@ type Challenge struct {
 	UpdatedAt    time.Time `json:"updated_at" structs:"updated_at"`
 	ExpiresAt    time.Time `json:"expires_at" structs:"expires_at"`
 	RespondedAt  time.Time `json:"responded_at,omitempty" structs:"responded_at,omitempty"`
+	Options      Options   `json:"options" structs:"options"`
+	ProposedBy   int64     `json:"proposed_by" structs:"proposed_by"`                   // Auteur de la dernière proposition, l'autre joueur doit répondre
+	Visibility   string    `json:"visibility" structs:"visibility"`                     // direct, open, invite
+	AllowGuest   bool      `json:"allow_guest" structs:"allow_guest"`                   // Le lien d'invitation peut créer un compte invité
+	RematchOf    string    `json:"rematch_of,omitempty" structs:"rematch_of,omitempty"` // Partie dont ce défi propose la revanche
+}
+
+// Visibilité d'un défi
+const (
+	VisibilityDirect = "direct" // Adressé à un joueur précis
+	VisibilityOpen   = "open"   // Listé dans le lobby, acceptable par n'importe qui
+	VisibilityInvite = "invite" // Acceptable uniquement via un lien d'invitation
+)
+
+// InviteSecret est la clé de signature des codes d'invitation
+var InviteSecret []byte
+
+// Premier joueur d'une partie, tel que stocké dans le défi
+const (
+	StarterChallenger = "challenger"
+	StarterChallenged = "challenged"
+	StarterRandom     = "random"
+)
+
+// Premier joueur d'une partie, tel qu'exprimé par l'auteur d'une proposition
+const (
+	StarterMe       = "me"
+	StarterOpponent = "opponent"
+)
+
+// Limites des options de défi
+const (
+	DefaultExpiry = 24 * time.Hour
+	MinExpiry     = 5 * time.Minute
+	MaxExpiry     = 7 * 24 * time.Hour
+	RematchExpiry = 10 * time.Minute
+
+	MaxInitialSeconds   = 24 * 60 * 60
+	MaxIncrementSeconds = 60 * 60
+)
+
+// Options représente les options d'un défi appliquées à la création de la partie
+type Options struct {
+	Starter     string           `json:"starter" structs:"starter"` // challenger, challenged, random
+	TimeControl game.TimeControl `json:"time_control" structs:"time_control"`
+	Rated       bool             `json:"rated" structs:"rated"`
+	Variant     string           `json:"variant" structs:"variant"` // standard, squares, squares_torus
+	CallQuarto  bool             `json:"call_quarto" structs:"call_quarto"`
+	Hints       int              `json:"hints" structs:"hints"`
 }
 
 // Structures pour les requêtes API
+type OptionsRequest struct {
+	Starter          string           `json:"starter" enums:"me,opponent,random"` // Premier joueur, du point de vue de l'auteur (défaut: me)
+	TimeControl      game.TimeControl `json:"time_control"`
+	Rated            bool             `json:"rated"`
+	Variant          string           `json:"variant" enums:"standard,squares,squares_torus"` // Variante de règles (défaut: standard)
+	CallQuarto       bool             `json:"call_quarto"`                                    // Les victoires doivent être annoncées
+	Hints            int              `json:"hints"`                                          // Indices accordés à chaque joueur (parties non classées)
+	ExpiresInMinutes int              `json:"expires_in_minutes"`                             // Durée de validité du défi (défaut: 24h)
+}
+
 type SendChallengeRequest struct {
-	ChallengedID int64  `json:"challenged_id" validate:"required"`
-	Message      string `json:"message"`
+	ChallengedID int64          `json:"challenged_id" validate:"required"`
+	Message      string         `json:"message"`
+	Options      OptionsRequest `json:"options"`
+}
+
+type CreateOpenChallengeRequest struct {
+	Message    string         `json:"message"`
+	Options    OptionsRequest `json:"options"`
+	InviteOnly bool           `json:"invite_only"` // Ne pas lister le défi dans le lobby
+	AllowGuest bool           `json:"allow_guest"` // Le lien d'invitation peut créer un compte invité
+}
+
+type Invite struct {
+	Code      string    `json:"code"`
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type OpenChallengeResponse struct {
+	Challenge *Challenge `json:"challenge"`
+	Invite    Invite     `json:"invite"`
+}
+
+type InvitePreviewResponse struct {
+	Challenge  *Challenge      `json:"challenge"`
+	Challenger user.UserPublic `json:"challenger"`
+}
+
+type AcceptInviteResponse struct {
+	Challenge *Challenge `json:"challenge"`
+	Game      any        `json:"game"`
+	Token     string     `json:"token,omitempty"` // Jeton de session du compte invité créé
+	User      any        `json:"user,omitempty"`  // Compte invité créé
+}
+
+type CounterChallengeRequest struct {
+	ChallengeID string         `json:"challenge_id" validate:"required"`
+	Options     OptionsRequest `json:"options"`
 }
 
 type RespondToChallengeRequest struct {
@@ -63,3 +161,111 @@ func (c Challenge) IsExpired() bool {
 func (c Challenge) CanRespond() bool {
 	return c.Status == "pending" && !c.IsExpired()
 }
+
+// IsOpen vérifie si le défi n'a pas encore d'adversaire désigné
+func (c Challenge) IsOpen() bool {
+	return c.ChallengedID == 0
+}
+
+// IsParticipant vérifie si l'utilisateur est l'un des deux joueurs du défi
+func (c Challenge) IsParticipant(userID int64) bool {
+	return c.ChallengerID == userID || c.ChallengedID == userID
+}
+
+// ExpectsResponseFrom vérifie si c'est à cet utilisateur de répondre à la dernière proposition
+func (c Challenge) ExpectsResponseFrom(userID int64) bool {
+	return c.IsParticipant(userID) && c.ProposedBy != userID
+}
+
+// Players retourne les joueurs de la partie dans l'ordre de jeu (le premier commence)
+func (c Challenge) Players() (first, second int64) {
+	starter := c.Options.Starter
+	if starter == StarterRandom {
+		starter = StarterChallenger
+		if rand.Intn(2) == 1 {
+			starter = StarterChallenged
+		}
+	}
+
+	if starter == StarterChallenged {
+		return c.ChallengedID, c.ChallengerID
+	}
+	return c.ChallengerID, c.ChallengedID
+}
+
+// GameOptions retourne les options de la partie créée à partir du défi
+func (o Options) GameOptions() game.GameOptions {
+	return game.GameOptions{
+		TimeControl: o.TimeControl,
+		Rated:       o.Rated,
+		Variant:     o.Variant,
+		CallQuarto:  o.CallQuarto,
+		Hints:       o.Hints,
+	}
+}
+
+// ToOptions valide la requête et la convertit en options stockées, du point de vue du défi
+func (r OptionsRequest) ToOptions(proposerIsChallenger bool) (Options, error) {
+	options := Options{
+		TimeControl: r.TimeControl,
+		Rated:       r.Rated,
+		Variant:     r.Variant,
+		CallQuarto:  r.CallQuarto,
+		Hints:       r.Hints,
+	}
+
+	switch r.Starter {
+	case "", StarterMe:
+		options.Starter = StarterChallenger
+		if !proposerIsChallenger {
+			options.Starter = StarterChallenged
+		}
+	case StarterOpponent:
+		options.Starter = StarterChallenged
+		if !proposerIsChallenger {
+			options.Starter = StarterChallenger
+		}
+	case StarterRandom:
+		options.Starter = StarterRandom
+	default:
+		return options, ErrInvalidStarter.With("starter", r.Starter)
+	}
+
+	if options.Variant == "" {
+		options.Variant = game.VariantStandard
+	} else if !game.IsValidVariant(options.Variant) {
+		return options, game.ErrUnknownVariant.With("variant", r.Variant)
+	}
+
+	if r.TimeControl.InitialSeconds < 0 || r.TimeControl.InitialSeconds > MaxInitialSeconds {
+		return options, ErrInvalidInitialTime.With("seconds", r.TimeControl.InitialSeconds)
+	}
+	if r.TimeControl.IncrementSeconds < 0 || r.TimeControl.IncrementSeconds > MaxIncrementSeconds {
+		return options, ErrInvalidIncrement.With("seconds", r.TimeControl.IncrementSeconds)
+	}
+	if r.TimeControl.InitialSeconds == 0 && r.TimeControl.IncrementSeconds > 0 {
+		return options, ErrIncrementWithoutTime
+	}
+
+	if r.Hints < 0 || r.Hints > game.MaxHints {
+		return options, ErrInvalidHints.With("max", game.MaxHints)
+	}
+	if r.Hints > 0 && r.Rated {
+		return options, ErrHintsRated
+	}
+
+	return options, nil
+}
+
+// Expiry retourne la durée de validité demandée pour le défi
+func (r OptionsRequest) Expiry() (time.Duration, error) {
+	if r.ExpiresInMinutes == 0 {
+		return DefaultExpiry, nil
+	}
+
+	expiry := time.Duration(r.ExpiresInMinutes) * time.Minute
+	if expiry < MinExpiry || expiry > MaxExpiry {
+		return 0, ErrInvalidExpiry.With("min", int(MinExpiry.Minutes())).With("max", int(MaxExpiry.Minutes()))
+	}
+	return expiry, nil
+}
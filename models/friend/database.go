@@ -0,0 +1,360 @@
+package friend
+
+import (
+	"database/sql"
+	"fmt"
+	"quarto/models/postgresql"
+	"quarto/models/user"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// ScanFriendship scanne une ligne de résultat SQL en structure Friendship
+func ScanFriendship(row pgx.Row) (f Friendship, err error) {
+	var (
+		id, requesterID, addresseeID sql.NullInt64
+		status                       sql.NullString
+		createdAt, respondedAt       sql.NullTime
+	)
+
+	err = row.Scan(
+		&id,
+		&requesterID,
+		&addresseeID,
+		&status,
+		&createdAt,
+		&respondedAt,
+	)
+
+	if err != nil {
+		return
+	}
+
+	f = Friendship{
+		ID:          id.Int64,
+		RequesterID: requesterID.Int64,
+		AddresseeID: addresseeID.Int64,
+		Status:      status.String,
+		CreatedAt:   createdAt.Time,
+		RespondedAt: respondedAt.Time,
+	}
+
+	return
+}
+
+// CreateFriendRequest insère une nouvelle demande d'ami en base
+func CreateFriendRequest(requesterID, addresseeID int64) (*Friendship, error) {
+	sqlCo, err := pgx.ConnectConfig(postgresql.SQLCtx, postgresql.SQLConn)
+	if err != nil {
+		return nil, fmt.Errorf("erreur de connexion DB: %v", err)
+	}
+	defer sqlCo.Close(postgresql.SQLCtx)
+
+	query := `
+		INSERT INTO friendships (requester_id, addressee_id, status)
+		VALUES ($1, $2, 'pending')
+		RETURNING id, requester_id, addressee_id, status, created_at, responded_at`
+
+	row := sqlCo.QueryRow(postgresql.SQLCtx, query, requesterID, addresseeID)
+	friendship, err := ScanFriendship(row)
+	if err != nil {
+		return nil, err
+	}
+
+	return &friendship, nil
+}
+
+// GetFriendshipByID récupère une relation d'amitié par son ID
+func GetFriendshipByID(friendshipID int64) (*Friendship, error) {
+	sqlCo, err := pgx.ConnectConfig(postgresql.SQLCtx, postgresql.SQLConn)
+	if err != nil {
+		return nil, fmt.Errorf("erreur de connexion DB: %v", err)
+	}
+	defer sqlCo.Close(postgresql.SQLCtx)
+
+	query := `
+		SELECT id, requester_id, addressee_id, status, created_at, responded_at
+		FROM friendships WHERE id = $1`
+
+	row := sqlCo.QueryRow(postgresql.SQLCtx, query, friendshipID)
+	friendship, err := ScanFriendship(row)
+	if err != nil {
+		return nil, err
+	}
+
+	return &friendship, nil
+}
+
+// GetFriendshipBetween récupère la relation entre deux joueurs (nil si aucune)
+func GetFriendshipBetween(userID, otherID int64) (*Friendship, error) {
+	sqlCo, err := pgx.ConnectConfig(postgresql.SQLCtx, postgresql.SQLConn)
+	if err != nil {
+		return nil, fmt.Errorf("erreur de connexion DB: %v", err)
+	}
+	defer sqlCo.Close(postgresql.SQLCtx)
+
+	query := `
+		SELECT id, requester_id, addressee_id, status, created_at, responded_at
+		FROM friendships
+		WHERE (requester_id = $1 AND addressee_id = $2) OR (requester_id = $2 AND addressee_id = $1)`
+
+	row := sqlCo.QueryRow(postgresql.SQLCtx, query, userID, otherID)
+	friendship, err := ScanFriendship(row)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &friendship, nil
+}
+
+// AcceptFriendship passe une demande d'ami au statut accepté
+func AcceptFriendship(friendshipID int64) error {
+	sqlCo, err := pgx.ConnectConfig(postgresql.SQLCtx, postgresql.SQLConn)
+	if err != nil {
+		return fmt.Errorf("erreur de connexion DB: %v", err)
+	}
+	defer sqlCo.Close(postgresql.SQLCtx)
+
+	query := `
+		UPDATE friendships
+		SET status = 'accepted', responded_at = NOW()
+		WHERE id = $1 AND status = 'pending'`
+
+	_, err = sqlCo.Exec(postgresql.SQLCtx, query, friendshipID)
+	return err
+}
+
+// DeleteFriendship supprime une relation d'amitié
+func DeleteFriendship(friendshipID int64) error {
+	sqlCo, err := pgx.ConnectConfig(postgresql.SQLCtx, postgresql.SQLConn)
+	if err != nil {
+		return fmt.Errorf("erreur de connexion DB: %v", err)
+	}
+	defer sqlCo.Close(postgresql.SQLCtx)
+
+	_, err = sqlCo.Exec(postgresql.SQLCtx, `DELETE FROM friendships WHERE id = $1`, friendshipID)
+	return err
+}
+
+// GetFriends récupère les amis (relations acceptées) d'un utilisateur
+func GetFriends(userID int64) ([]Friend, error) {
+	sqlCo, err := pgx.ConnectConfig(postgresql.SQLCtx, postgresql.SQLConn)
+	if err != nil {
+		return nil, fmt.Errorf("erreur de connexion DB: %v", err)
+	}
+	defer sqlCo.Close(postgresql.SQLCtx)
+
+	query := `
+		SELECT a.id, a.username, f.responded_at
+		FROM friendships f
+		JOIN account a ON a.id = CASE WHEN f.requester_id = $1 THEN f.addressee_id ELSE f.requester_id END
+		WHERE (f.requester_id = $1 OR f.addressee_id = $1)
+		AND f.status = 'accepted'
+		AND a.enable = TRUE
+		ORDER BY a.username ASC`
+
+	rows, err := sqlCo.Query(postgresql.SQLCtx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	friends := make([]Friend, 0)
+	for rows.Next() {
+		var (
+			friend Friend
+			since  sql.NullTime
+		)
+		if err := rows.Scan(&friend.User.ID, &friend.User.Username, &since); err != nil {
+			return nil, err
+		}
+		friend.Since = since.Time
+		friends = append(friends, friend)
+	}
+
+	return friends, nil
+}
+
+// GetPendingFriendRequests récupère les demandes d'ami en attente envoyées et reçues par un utilisateur
+func GetPendingFriendRequests(userID int64) (*FriendRequestListResponse, error) {
+	sqlCo, err := pgx.ConnectConfig(postgresql.SQLCtx, postgresql.SQLConn)
+	if err != nil {
+		return nil, fmt.Errorf("erreur de connexion DB: %v", err)
+	}
+	defer sqlCo.Close(postgresql.SQLCtx)
+
+	query := `
+		SELECT f.id, f.requester_id, a.id, a.username, f.created_at
+		FROM friendships f
+		JOIN account a ON a.id = CASE WHEN f.requester_id = $1 THEN f.addressee_id ELSE f.requester_id END
+		WHERE (f.requester_id = $1 OR f.addressee_id = $1)
+		AND f.status = 'pending'
+		ORDER BY f.created_at DESC`
+
+	rows, err := sqlCo.Query(postgresql.SQLCtx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	response := &FriendRequestListResponse{
+		Sent:     make([]FriendRequest, 0),
+		Received: make([]FriendRequest, 0),
+	}
+	for rows.Next() {
+		var (
+			request     FriendRequest
+			requesterID int64
+			createdAt   sql.NullTime
+		)
+		if err := rows.Scan(&request.ID, &requesterID, &request.User.ID, &request.User.Username, &createdAt); err != nil {
+			return nil, err
+		}
+		request.CreatedAt = createdAt.Time
+
+		if requesterID == userID {
+			response.Sent = append(response.Sent, request)
+		} else {
+			response.Received = append(response.Received, request)
+		}
+	}
+
+	return response, nil
+}
+
+// CreateBlock enregistre le blocage d'un joueur et supprime toute relation d'amitié existante
+func CreateBlock(blockerID, blockedID int64) error {
+	sqlCo, err := pgx.ConnectConfig(postgresql.SQLCtx, postgresql.SQLConn)
+	if err != nil {
+		return fmt.Errorf("erreur de connexion DB: %v", err)
+	}
+	defer sqlCo.Close(postgresql.SQLCtx)
+
+	tx, err := sqlCo.Begin(postgresql.SQLCtx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(postgresql.SQLCtx)
+
+	query := `
+		INSERT INTO blocks (blocker_id, blocked_id)
+		VALUES ($1, $2)
+		ON CONFLICT (blocker_id, blocked_id) DO NOTHING`
+	if _, err = tx.Exec(postgresql.SQLCtx, query, blockerID, blockedID); err != nil {
+		return err
+	}
+
+	query = `
+		DELETE FROM friendships
+		WHERE (requester_id = $1 AND addressee_id = $2) OR (requester_id = $2 AND addressee_id = $1)`
+	if _, err = tx.Exec(postgresql.SQLCtx, query, blockerID, blockedID); err != nil {
+		return err
+	}
+
+	return tx.Commit(postgresql.SQLCtx)
+}
+
+// DeleteBlock supprime le blocage d'un joueur
+func DeleteBlock(blockerID, blockedID int64) (bool, error) {
+	sqlCo, err := pgx.ConnectConfig(postgresql.SQLCtx, postgresql.SQLConn)
+	if err != nil {
+		return false, fmt.Errorf("erreur de connexion DB: %v", err)
+	}
+	defer sqlCo.Close(postgresql.SQLCtx)
+
+	cmd, err := sqlCo.Exec(postgresql.SQLCtx, `DELETE FROM blocks WHERE blocker_id = $1 AND blocked_id = $2`, blockerID, blockedID)
+	if err != nil {
+		return false, err
+	}
+
+	return cmd.RowsAffected() > 0, nil
+}
+
+// GetBlockedUsers récupère les joueurs bloqués par un utilisateur
+func GetBlockedUsers(userID int64) ([]BlockedUser, error) {
+	sqlCo, err := pgx.ConnectConfig(postgresql.SQLCtx, postgresql.SQLConn)
+	if err != nil {
+		return nil, fmt.Errorf("erreur de connexion DB: %v", err)
+	}
+	defer sqlCo.Close(postgresql.SQLCtx)
+
+	query := `
+		SELECT a.id, a.username, b.created_at
+		FROM blocks b
+		JOIN account a ON a.id = b.blocked_id
+		WHERE b.blocker_id = $1
+		ORDER BY b.created_at DESC`
+
+	rows, err := sqlCo.Query(postgresql.SQLCtx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	blocked := make([]BlockedUser, 0)
+	for rows.Next() {
+		var (
+			publicUser user.UserPublic
+			createdAt  sql.NullTime
+		)
+		if err := rows.Scan(&publicUser.ID, &publicUser.Username, &createdAt); err != nil {
+			return nil, err
+		}
+		blocked = append(blocked, BlockedUser{User: publicUser, CreatedAt: createdAt.Time})
+	}
+
+	return blocked, nil
+}
+
+// IsBlockedBetween vérifie si l'un des deux joueurs a bloqué l'autre
+func IsBlockedBetween(userID, otherID int64) (bool, error) {
+	sqlCo, err := pgx.ConnectConfig(postgresql.SQLCtx, postgresql.SQLConn)
+	if err != nil {
+		return false, fmt.Errorf("erreur de connexion DB: %v", err)
+	}
+	defer sqlCo.Close(postgresql.SQLCtx)
+
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM blocks
+			WHERE (blocker_id = $1 AND blocked_id = $2) OR (blocker_id = $2 AND blocked_id = $1)
+		)`
+
+	var blocked bool
+	err = sqlCo.QueryRow(postgresql.SQLCtx, query, userID, otherID).Scan(&blocked)
+	return blocked, err
+}
+
+// GetBlockedBetween récupère les joueurs qu'un utilisateur a bloqués ou qui l'ont bloqué
+func GetBlockedBetween(userID int64) (map[int64]bool, error) {
+	sqlCo, err := pgx.ConnectConfig(postgresql.SQLCtx, postgresql.SQLConn)
+	if err != nil {
+		return nil, fmt.Errorf("erreur de connexion DB: %v", err)
+	}
+	defer sqlCo.Close(postgresql.SQLCtx)
+
+	query := `
+		SELECT CASE WHEN blocker_id = $1 THEN blocked_id ELSE blocker_id END
+		FROM blocks
+		WHERE blocker_id = $1 OR blocked_id = $1`
+
+	rows, err := sqlCo.Query(postgresql.SQLCtx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	blocked := make(map[int64]bool)
+	for rows.Next() {
+		var otherID int64
+		if err := rows.Scan(&otherID); err != nil {
+			return nil, err
+		}
+		blocked[otherID] = true
+	}
+
+	return blocked, rows.Err()
+}
@@ -0,0 +1,20 @@
+package friend
+
+import (
+	"net/http"
+	"quarto/models/apperror"
+)
+
+// Erreurs métier des amitiés et des blocages
+var (
+	ErrSelfFriend       = apperror.New("friend.self", http.StatusUnprocessableEntity, "vous ne pouvez pas vous ajouter vous-même en ami", "you cannot add yourself as a friend")
+	ErrPlayerBlocked    = apperror.New("friend.blocked", http.StatusForbidden, "vous ne pouvez pas ajouter ce joueur en ami", "you cannot add this player as a friend")
+	ErrAlreadyFriends   = apperror.New("friend.already_friends", http.StatusConflict, "vous êtes déjà amis avec ce joueur", "you are already friends with this player")
+	ErrRequestPending   = apperror.New("friend.request_pending", http.StatusConflict, "une demande d'ami est déjà en attente", "a friend request is already pending")
+	ErrNotFriend        = apperror.New("friend.not_friend", http.StatusNotFound, "ce joueur ne fait pas partie de vos amis", "this player is not one of your friends")
+	ErrRequestNotFound  = apperror.New("friend.request_not_found", http.StatusNotFound, "demande d'ami non trouvée", "friend request not found")
+	ErrRequestForbidden = apperror.New("friend.request_forbidden", http.StatusForbidden, "vous n'êtes pas autorisé à répondre à cette demande d'ami", "you are not allowed to respond to this friend request")
+	ErrRequestHandled   = apperror.New("friend.request_handled", http.StatusConflict, "cette demande d'ami a déjà été traitée", "this friend request has already been handled")
+	ErrSelfBlock        = apperror.New("friend.self_block", http.StatusUnprocessableEntity, "vous ne pouvez pas vous bloquer vous-même", "you cannot block yourself")
+	ErrNotBlocked       = apperror.New("friend.not_blocked", http.StatusNotFound, "ce joueur n'est pas bloqué", "this player is not blocked")
+)
@@ -0,0 +1,181 @@
+package friend
+
+import (
+	"errors"
+	"fmt"
+	"quarto/models/user"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// SendFriendRequest envoie une demande d'ami (ou accepte celle déjà reçue de ce joueur)
+func SendFriendRequest(requesterID, addresseeID int64) (*Friendship, error) {
+	// Vérifier que le joueur ne s'ajoute pas lui-même
+	if requesterID == addresseeID {
+		return nil, ErrSelfFriend
+	}
+
+	// Vérifier que le destinataire existe
+	if _, err := user.GetUserPublicByID(addresseeID); err != nil {
+		return nil, err
+	}
+
+	blocked, err := IsBlockedBetween(requesterID, addresseeID)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la vérification des blocages: %w", err)
+	}
+	if blocked {
+		return nil, ErrPlayerBlocked
+	}
+
+	existing, err := GetFriendshipBetween(requesterID, addresseeID)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la vérification des relations existantes: %w", err)
+	}
+	if existing != nil {
+		switch {
+		case existing.Status == StatusAccepted:
+			return nil, ErrAlreadyFriends
+		case existing.RequesterID == requesterID:
+			return nil, ErrRequestPending
+		default:
+			// L'autre joueur nous a déjà envoyé une demande : l'accepter directement
+			return AcceptFriendRequest(existing.ID, requesterID)
+		}
+	}
+
+	friendship, err := CreateFriendRequest(requesterID, addresseeID)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la création de la demande d'ami: %w", err)
+	}
+
+	return friendship, nil
+}
+
+// AcceptFriendRequest accepte une demande d'ami reçue
+func AcceptFriendRequest(requestID, addresseeID int64) (*Friendship, error) {
+	friendship, err := getPendingRequestFor(requestID, addresseeID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = AcceptFriendship(friendship.ID)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de l'acceptation de la demande d'ami: %w", err)
+	}
+
+	updatedFriendship, err := GetFriendshipByID(friendship.ID)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la récupération de la demande d'ami mise à jour: %w", err)
+	}
+
+	return updatedFriendship, nil
+}
+
+// DeclineFriendRequest refuse une demande d'ami reçue
+func DeclineFriendRequest(requestID, addresseeID int64) (*Friendship, error) {
+	friendship, err := getPendingRequestFor(requestID, addresseeID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = DeleteFriendship(friendship.ID)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors du refus de la demande d'ami: %w", err)
+	}
+
+	return friendship, nil
+}
+
+// RemoveFriend supprime un ami ou annule une demande d'ami envoyée
+func RemoveFriend(userID, friendID int64) error {
+	friendship, err := GetFriendshipBetween(userID, friendID)
+	if err != nil {
+		return fmt.Errorf("erreur lors de la récupération de la relation: %w", err)
+	}
+	if friendship == nil {
+		return ErrNotFriend
+	}
+
+	// Une demande reçue se refuse, elle ne se supprime pas
+	if friendship.Status == StatusPending && friendship.RequesterID != userID {
+		return ErrNotFriend
+	}
+
+	err = DeleteFriendship(friendship.ID)
+	if err != nil {
+		return fmt.Errorf("erreur lors de la suppression de l'ami: %w", err)
+	}
+
+	return nil
+}
+
+// GetFriendList récupère la liste d'amis d'un utilisateur
+func GetFriendList(userID int64) ([]Friend, error) {
+	friends, err := GetFriends(userID)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la récupération des amis: %w", err)
+	}
+	return friends, nil
+}
+
+// AreFriends vérifie si deux joueurs sont amis
+func AreFriends(userID, otherID int64) (bool, error) {
+	friendship, err := GetFriendshipBetween(userID, otherID)
+	if err != nil {
+		return false, err
+	}
+	return friendship != nil && friendship.Status == StatusAccepted, nil
+}
+
+// BlockUser bloque un joueur : il ne peut plus défier, regarder les parties ni écrire à l'utilisateur
+func BlockUser(blockerID, blockedID int64) error {
+	if blockerID == blockedID {
+		return ErrSelfBlock
+	}
+
+	if _, err := user.GetUserPublicByID(blockedID); err != nil {
+		return err
+	}
+
+	err := CreateBlock(blockerID, blockedID)
+	if err != nil {
+		return fmt.Errorf("erreur lors du blocage du joueur: %w", err)
+	}
+
+	return nil
+}
+
+// UnblockUser débloque un joueur
+func UnblockUser(blockerID, blockedID int64) error {
+	removed, err := DeleteBlock(blockerID, blockedID)
+	if err != nil {
+		return fmt.Errorf("erreur lors du déblocage du joueur: %w", err)
+	}
+	if !removed {
+		return ErrNotBlocked
+	}
+
+	return nil
+}
+
+// getPendingRequestFor récupère une demande en attente adressée à l'utilisateur
+func getPendingRequestFor(requestID, addresseeID int64) (*Friendship, error) {
+	friendship, err := GetFriendshipByID(requestID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrRequestNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la récupération de la demande d'ami: %w", err)
+	}
+
+	if friendship.AddresseeID != addresseeID {
+		return nil, ErrRequestForbidden
+	}
+
+	if friendship.Status != StatusPending {
+		return nil, ErrRequestHandled
+	}
+
+	return friendship, nil
+}
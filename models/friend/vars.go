@@ -0,0 +1,68 @@
+package friend
+
+import (
+	"quarto/models/user"
+	"time"
+)
+
+// Friendship status constants
+const (
+	StatusPending  = "pending"
+	StatusAccepted = "accepted"
+)
+
+type (
+	// Friendship représente une relation d'amitié (demande en attente ou acceptée)
+	Friendship struct {
+		ID          int64     `json:"id"`
+		RequesterID int64     `json:"requester_id"`
+		AddresseeID int64     `json:"addressee_id"`
+		Status      string    `json:"status"` // pending, accepted
+		CreatedAt   time.Time `json:"created_at"`
+		RespondedAt time.Time `json:"responded_at,omitempty"`
+	}
+
+	// Friend représente un ami dans la liste d'amis
+	Friend struct {
+		User   user.UserPublic `json:"user"`
+		Online bool            `json:"online"`
+		Since  time.Time       `json:"since"`
+	}
+
+	// FriendRequest représente une demande d'ami en attente
+	FriendRequest struct {
+		ID        int64           `json:"id"`
+		User      user.UserPublic `json:"user"` // L'autre joueur (destinataire ou expéditeur)
+		CreatedAt time.Time       `json:"created_at"`
+	}
+
+	// BlockedUser représente un joueur bloqué
+	BlockedUser struct {
+		User      user.UserPublic `json:"user"`
+		CreatedAt time.Time       `json:"created_at"`
+	}
+
+	// Structures pour les requêtes API
+	SendFriendRequestRequest struct {
+		UserID int64 `json:"user_id" validate:"required"`
+	}
+
+	RespondToFriendRequestRequest struct {
+		RequestID int64 `json:"request_id" validate:"required"`
+		Accept    bool  `json:"accept"`
+	}
+
+	BlockUserRequest struct {
+		UserID int64 `json:"user_id" validate:"required"`
+	}
+
+	FriendRequestListResponse struct {
+		Sent     []FriendRequest `json:"sent"`
+		Received []FriendRequest `json:"received"`
+	}
+)
+
+// IsParticipant vérifie si l'utilisateur fait partie de la relation
+func (f Friendship) IsParticipant(userID int64) bool {
+	return f.RequesterID == userID || f.AddresseeID == userID
+}
@@ -0,0 +1,282 @@
+package game
+
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+// Actions de partie, disponibles en REST et via WebSocket
+const (
+	ActionOfferDraw       = "offer_draw"
+	ActionAcceptDraw      = "accept_draw"
+	ActionDeclineDraw     = "decline_draw"
+	ActionRequestTakeback = "request_takeback"
+	ActionAcceptTakeback  = "accept_takeback"
+	ActionDeclineTakeback = "decline_takeback"
+	ActionClaimQuarto     = "claim_quarto"
+)
+
+// Événements diffusés aux joueurs après une action
+const (
+	EventDrawOffered       = "draw_offered"
+	EventDrawAccepted      = "draw_accepted"
+	EventDrawDeclined      = "draw_declined"
+	EventTakebackRequested = "takeback_requested"
+	EventTakebackAccepted  = "takeback_accepted"
+	EventTakebackDeclined  = "takeback_declined"
+	EventQuartoClaimed     = "quarto_claimed"
+	EventClaimRejected     = "quarto_claim_rejected"
+)
+
+// Rules définit les actions autorisées dans les parties classées
+var Rules = ActionRules{
+	TakebacksUnratedOnly: true,
+}
+
+// IsGameAction indique si le type correspond à une action de partie
+func IsGameAction(action string) bool {
+	switch action {
+	case ActionOfferDraw, ActionAcceptDraw, ActionDeclineDraw,
+		ActionRequestTakeback, ActionAcceptTakeback, ActionDeclineTakeback,
+		ActionClaimQuarto:
+		return true
+	}
+	return false
+}
+
+// PerformAction applique une action de partie et l'enregistre ; retourne l'événement à diffuser
+func (g *Game) PerformAction(userID int64, action string) (event string, err error) {
+	event, err = g.ApplyAction(userID, action)
+	if errors.Is(err, ErrTimeExpired) {
+		// La défaite au temps constatée par l'action est enregistrée
+		if updateErr := UpdateGame(*g); updateErr != nil {
+			err = ErrGameUpdate.Wrap(updateErr)
+		}
+		return
+	}
+	if err != nil {
+		return
+	}
+
+	err = UpdateGame(*g)
+	if err != nil {
+		err = ErrGameUpdate.Wrap(err)
+	}
+	return
+}
+
+// ApplyAction applique une action de partie sans l'enregistrer. Si le joueur au trait a épuisé son temps, la partie
+// est d'abord perdue au temps et l'action refusée : une nulle ou une annonce ne peut plus la sauver.
+func (g *Game) ApplyAction(userID int64, action string) (event string, err error) {
+	if g.Player1ID != userID && g.Player2ID != userID {
+		return "", ErrGameForbidden
+	}
+
+	if g.Status != StatusPlaying {
+		return "", ErrGameNotActive
+	}
+
+	if g.FlagTimeout(time.Now()) {
+		return "", ErrTimeExpired
+	}
+
+	// Le bot ne négocie pas : seules les annonces de Quarto restent soumises aux options de la partie
+	if g.Options.Bot != nil && action != ActionClaimQuarto {
+		return "", ErrBotAction
+	}
+
+	switch action {
+	case ActionOfferDraw:
+		event, err = g.offerDraw(userID)
+	case ActionAcceptDraw:
+		event, err = g.acceptDraw(userID)
+	case ActionDeclineDraw:
+		event, err = g.declineDraw(userID)
+	case ActionRequestTakeback:
+		event, err = g.requestTakeback(userID)
+	case ActionAcceptTakeback:
+		event, err = g.acceptTakeback(userID)
+	case ActionDeclineTakeback:
+		event, err = g.declineTakeback(userID)
+	case ActionClaimQuarto:
+		event, err = g.claimQuarto(userID)
+	default:
+		err = ErrUnknownAction.With("action", action)
+	}
+
+	if err == nil {
+		g.UpdatedAt = time.Now()
+	}
+	return
+}
+
+// offerDraw propose la nulle ; si l'adversaire l'a déjà proposée elle est acceptée
+func (g *Game) offerDraw(userID int64) (string, error) {
+	if g.Options.Rated && Rules.DrawOffersUnratedOnly {
+		return "", ErrDrawOffersRated
+	}
+
+	switch g.DrawOfferedBy {
+	case userID:
+		return "", ErrDrawAlreadyOffered
+	case 0:
+		g.DrawOfferedBy = userID
+		return EventDrawOffered, nil
+	default:
+		return g.acceptDraw(userID)
+	}
+}
+
+// acceptDraw accepte la nulle proposée par l'adversaire et termine la partie
+func (g *Game) acceptDraw(userID int64) (string, error) {
+	if g.DrawOfferedBy == 0 || g.DrawOfferedBy == userID {
+		return "", ErrNoDrawOffer
+	}
+
+	g.DrawOfferedBy = 0
+	g.TakebackRequestedBy = 0
+	g.Status = StatusFinished
+	g.Winner = 0
+	return EventDrawAccepted, nil
+}
+
+// declineDraw refuse la nulle proposée par l'adversaire
+func (g *Game) declineDraw(userID int64) (string, error) {
+	if g.DrawOfferedBy == 0 || g.DrawOfferedBy == userID {
+		return "", ErrNoDrawOffer
+	}
+
+	g.DrawOfferedBy = 0
+	return EventDrawDeclined, nil
+}
+
+// requestTakeback demande à annuler son dernier placement
+func (g *Game) requestTakeback(userID int64) (string, error) {
+	if g.Options.Rated && Rules.TakebacksUnratedOnly {
+		return "", ErrTakebacksRated
+	}
+
+	if g.TakebackRequestedBy != 0 {
+		return "", ErrTakebackPending
+	}
+
+	if index := g.lastPlacementIndex(); index < 0 || g.History[index].Actor != userID {
+		return "", ErrNothingToTakeBack
+	}
+
+	g.TakebackRequestedBy = userID
+	return EventTakebackRequested, nil
+}
+
+// acceptTakeback accepte la demande d'annulation de l'adversaire et rembobine la partie
+func (g *Game) acceptTakeback(userID int64) (string, error) {
+	if g.TakebackRequestedBy == 0 || g.TakebackRequestedBy == userID {
+		return "", ErrNoTakebackRequest
+	}
+
+	if err := g.undoLastPlacement(); err != nil {
+		return "", err
+	}
+
+	g.TakebackRequestedBy = 0
+	return EventTakebackAccepted, nil
+}
+
+// declineTakeback refuse la demande d'annulation de l'adversaire
+func (g *Game) declineTakeback(userID int64) (string, error) {
+	if g.TakebackRequestedBy == 0 || g.TakebackRequestedBy == userID {
+		return "", ErrNoTakebackRequest
+	}
+
+	g.TakebackRequestedBy = 0
+	return EventTakebackDeclined, nil
+}
+
+// claimQuarto annonce la victoire formée par le dernier placement. Son auteur doit l'annoncer avant de
+// donner une pièce ; s'il l'a manquée, l'adversaire peut la revendiquer jusqu'à son propre placement.
+// Une annonce erronée fait perdre la partie.
+func (g *Game) claimQuarto(userID int64) (string, error) {
+	if !g.Options.CallQuarto {
+		return "", ErrCallQuartoDisabled
+	}
+
+	accepted, err := g.applyClaim(userID, time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	g.clearPendingRequests()
+	if accepted {
+		return EventQuartoClaimed, nil
+	}
+	return EventClaimRejected, nil
+}
+
+// applyClaim applique l'annonce de Quarto d'un joueur et l'enregistre dans l'historique ; retourne vrai si
+// le dernier placement forme bien un Quarto
+func (g *Game) applyClaim(userID int64, at time.Time) (bool, error) {
+	index := g.lastPlacementIndex()
+	if index < 0 {
+		return false, ErrNothingToClaim
+	}
+	placement := g.History[index]
+	selected := index < len(g.History)-1
+
+	if placement.Actor == userID && selected {
+		return false, ErrClaimTooLate
+	}
+	if placement.Actor != userID && !selected {
+		return false, ErrOpponentCanStillClaim
+	}
+
+	position := *placement.Position
+	accepted := completesWin(g.Board, position, g.Options.Variant)
+	event := g.newMoveEvent(MoveEventClaim, placement.Piece, &position, at)
+	event.Actor = userID
+	event.Accepted = accepted
+	g.History = append(g.History, event)
+
+	g.Status = StatusFinished
+	g.Winner = userID
+	if !accepted {
+		g.Winner = g.opponentOf(userID)
+	}
+	g.UpdatedAt = at
+
+	return accepted, nil
+}
+
+// undoLastPlacement annule le dernier placement et la sélection qui l'a éventuellement suivi :
+// le joueur concerné doit de nouveau placer la même pièce
+func (g *Game) undoLastPlacement() error {
+	index := g.lastPlacementIndex()
+	if index < 0 {
+		return ErrNothingToTakeBack
+	}
+	placement := g.History[index]
+
+	// La pièce déjà choisie pour l'adversaire redevient disponible
+	for _, event := range g.History[index+1:] {
+		if event.Type == MoveEventSelect {
+			position, _ := slices.BinarySearch(g.AvailablePieces, event.Piece)
+			g.AvailablePieces = slices.Insert(g.AvailablePieces, position, event.Piece)
+		}
+	}
+
+	g.Board[placement.Position.Row][placement.Position.Col] = PieceEmpty
+	g.History = g.History[:index]
+	g.SelectedPiece = placement.Piece
+	g.GamePhase = GamePhasePlacePiece
+	g.CurrentTurn = placement.Actor
+
+	return nil
+}
+
+// opponentOf retourne l'adversaire d'un joueur
+func (g *Game) opponentOf(userID int64) int64 {
+	if userID == g.Player1ID {
+		return g.Player2ID
+	}
+	return g.Player1ID
+}
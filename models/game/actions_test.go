@@ -0,0 +1,202 @@
+package game
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// playSelect et playPlace jouent un coup sans accès à la base
+func playSelect(t *testing.T, g *Game, piece Piece) {
+	t.Helper()
+	if err := g.applySelection(piece, time.Now()); err != nil {
+		t.Fatalf("sélection de la pièce %d: %v", piece, err)
+	}
+}
+
+func playPlace(t *testing.T, g *Game, position Position) {
+	t.Helper()
+	if err := g.applyPlacement(position, time.Now()); err != nil {
+		t.Fatalf("placement en %v: %v", position, err)
+	}
+}
+
+func TestTakeback(t *testing.T) {
+	g := InitializeGame(1, 2)
+	playSelect(t, &g, 5) // Le joueur 1 donne la pièce 5
+	expected := g
+	expected.History = slices.Clone(g.History)
+	playPlace(t, &g, Position{Row: 0, Col: 0}) // Le joueur 2 la place
+	playSelect(t, &g, 7)                       // Le joueur 2 donne la pièce 7
+
+	if _, err := g.ApplyAction(1, ActionRequestTakeback); err == nil {
+		t.Fatal("le joueur 1 ne devrait pas pouvoir annuler le placement du joueur 2")
+	}
+	if event, err := g.ApplyAction(2, ActionRequestTakeback); err != nil || event != EventTakebackRequested {
+		t.Fatalf("demande d'annulation: event=%q err=%v", event, err)
+	}
+	if _, err := g.ApplyAction(2, ActionAcceptTakeback); err == nil {
+		t.Fatal("le joueur 2 ne devrait pas pouvoir accepter sa propre demande")
+	}
+	if event, err := g.ApplyAction(1, ActionAcceptTakeback); err != nil || event != EventTakebackAccepted {
+		t.Fatalf("acceptation de l'annulation: event=%q err=%v", event, err)
+	}
+
+	// Le joueur 2 doit de nouveau placer la pièce 5, la pièce 7 est redevenue disponible
+	if g.Board != expected.Board || !slices.Equal(g.History, expected.History) {
+		t.Errorf("plateau ou historique non rembobiné: %v %v", g.Board, g.History)
+	}
+	if g.SelectedPiece != 5 || g.GamePhase != GamePhasePlacePiece || g.CurrentTurn != 2 {
+		t.Errorf("état après annulation: pièce=%d phase=%d tour=%d", g.SelectedPiece, g.GamePhase, g.CurrentTurn)
+	}
+	if !slices.Equal(g.AvailablePieces, expected.AvailablePieces) || !slices.Contains(g.AvailablePieces, 7) {
+		t.Errorf("pièces disponibles = %v, attendu %v", g.AvailablePieces, expected.AvailablePieces)
+	}
+	if g.TakebackRequestedBy != 0 {
+		t.Errorf("la demande d'annulation devrait être effacée")
+	}
+
+	// Plus aucun placement à annuler
+	if _, err := g.ApplyAction(2, ActionRequestTakeback); err == nil {
+		t.Error("aucun placement ne devrait pouvoir être annulé")
+	}
+}
+
+func TestTakebackRatedGame(t *testing.T) {
+	g := InitializeGame(1, 2)
+	g.Options.Rated = true
+	playSelect(t, &g, 0)
+	playPlace(t, &g, Position{Row: 1, Col: 1})
+
+	if _, err := g.ApplyAction(2, ActionRequestTakeback); err == nil {
+		t.Error("les annulations ne devraient pas être autorisées dans une partie classée")
+	}
+}
+
+func TestBotGameActions(t *testing.T) {
+	g := InitializeGame(1, 2)
+	g.Options.Bot = &BotOptions{PlayerID: 2, Engine: "mcts"}
+	playSelect(t, &g, 0)
+	playPlace(t, &g, Position{Row: 1, Col: 1})
+
+	for _, action := range []string{ActionOfferDraw, ActionRequestTakeback} {
+		if _, err := g.ApplyAction(1, action); !errors.Is(err, ErrBotAction) {
+			t.Errorf("%s accepté dans une partie contre le bot: %v", action, err)
+		}
+	}
+	if !g.IsBotTurn() {
+		t.Error("le bot devrait être au trait pour donner une pièce")
+	}
+}
+
+func TestDrawOffer(t *testing.T) {
+	g := InitializeGame(1, 2)
+
+	if _, err := g.ApplyAction(1, ActionAcceptDraw); err == nil {
+		t.Fatal("aucune nulle ne devrait pouvoir être acceptée sans proposition")
+	}
+	if event, err := g.ApplyAction(1, ActionOfferDraw); err != nil || event != EventDrawOffered {
+		t.Fatalf("proposition de nulle: event=%q err=%v", event, err)
+	}
+	if _, err := g.ApplyAction(1, ActionAcceptDraw); err == nil {
+		t.Fatal("le joueur 1 ne devrait pas pouvoir accepter sa propre proposition")
+	}
+	if event, err := g.ApplyAction(2, ActionDeclineDraw); err != nil || event != EventDrawDeclined {
+		t.Fatalf("refus de la nulle: event=%q err=%v", event, err)
+	}
+
+	// Deux propositions croisées valent acceptation
+	g.ApplyAction(1, ActionOfferDraw)
+	if event, err := g.ApplyAction(2, ActionOfferDraw); err != nil || event != EventDrawAccepted {
+		t.Fatalf("propositions croisées: event=%q err=%v", event, err)
+	}
+	if g.Status != StatusFinished || g.Winner != 0 {
+		t.Errorf("partie nulle attendue: status=%d winner=%d", g.Status, g.Winner)
+	}
+	if _, err := g.ApplyAction(1, ActionOfferDraw); err == nil {
+		t.Error("aucune action ne devrait être possible sur une partie terminée")
+	}
+}
+
+func TestCallQuarto(t *testing.T) {
+	g := InitializeGame(1, 2)
+	g.Options.CallQuarto = true
+
+	// Le joueur 1 complète une ligne de pièces blanches sans que la partie se termine
+	playSelect(t, &g, 0)
+	playPlace(t, &g, Position{Row: 0, Col: 0})
+	playSelect(t, &g, 1)
+	playPlace(t, &g, Position{Row: 0, Col: 1})
+	playSelect(t, &g, 2)
+	playPlace(t, &g, Position{Row: 0, Col: 2})
+	playSelect(t, &g, 3)
+	playPlace(t, &g, Position{Row: 0, Col: 3})
+	if g.Status != StatusPlaying {
+		t.Fatal("la victoire doit être annoncée")
+	}
+	if _, err := g.ApplyAction(2, ActionClaimQuarto); err == nil {
+		t.Error("l'adversaire ne peut pas revendiquer tant que le joueur peut annoncer")
+	}
+
+	clone := func(g Game) Game {
+		g.History = slices.Clone(g.History)
+		g.AvailablePieces = slices.Clone(g.AvailablePieces)
+		return g
+	}
+
+	announced := clone(g)
+	if event, err := announced.ApplyAction(1, ActionClaimQuarto); err != nil || event != EventQuartoClaimed || announced.Winner != 1 {
+		t.Errorf("annonce: event=%q err=%v winner=%d", event, err, announced.Winner)
+	}
+
+	// Le joueur 1 donne une pièce sans annoncer : le joueur 2 peut revendiquer la victoire manquée
+	playSelect(t, &g, 5)
+	if _, err := g.ApplyAction(1, ActionClaimQuarto); err == nil {
+		t.Error("il est trop tard pour annoncer après avoir donné une pièce")
+	}
+	missed := clone(g)
+	if event, err := missed.ApplyAction(2, ActionClaimQuarto); err != nil || event != EventQuartoClaimed || missed.Winner != 2 {
+		t.Errorf("revendication: event=%q err=%v winner=%d", event, err, missed.Winner)
+	}
+
+	// Une fois sa pièce posée, le joueur 2 ne peut plus revendiquer : une annonce erronée fait perdre
+	playPlace(t, &g, Position{Row: 2, Col: 0})
+	if event, err := g.ApplyAction(2, ActionClaimQuarto); err != nil || event != EventClaimRejected || g.Winner != 1 {
+		t.Errorf("annonce erronée: event=%q err=%v winner=%d", event, err, g.Winner)
+	}
+
+	standard := InitializeGame(1, 2)
+	if _, err := standard.ApplyAction(1, ActionClaimQuarto); err == nil {
+		t.Error("l'annonce n'existe pas dans les parties standard")
+	}
+
+	// Les annonces figurent dans l'historique : le rejeu et l'enregistrement retrouvent le résultat
+	for name, claimed := range map[string]Game{"annonce": announced, "revendication": missed, "annonce erronée": g} {
+		last := claimed.History[len(claimed.History)-1]
+		if last.Type != MoveEventClaim || last.Accepted != (claimed.Winner == last.Actor) {
+			t.Errorf("%s: dernier événement %+v", name, last)
+		}
+
+		plies, err := Replay(1, 2, claimed.Options, claimed.History)
+		if err != nil {
+			t.Fatalf("%s: rejeu: %v", name, err)
+		}
+		final := plies[len(plies)-1]
+		if final.Status != StatusFinished || final.Winner != claimed.Winner || final.Notation != ClaimToken {
+			t.Errorf("%s: position finale %+v, gagnant attendu %d", name, final, claimed.Winner)
+		}
+
+		record, err := ParseRecord(NewRecord(claimed, "a", "b").Format())
+		if err != nil {
+			t.Fatalf("%s: relecture de l'enregistrement: %v", name, err)
+		}
+		imported, err := ImportRecord(record)
+		if err != nil {
+			t.Fatalf("%s: import: %v", name, err)
+		}
+		if final := imported.Plies[len(imported.Plies)-1]; final.Winner != claimed.Winner || final.Event.Actor != last.Actor || final.Event.Accepted != last.Accepted {
+			t.Errorf("%s: import terminé sur %+v, attendu %+v", name, final.Event, last)
+		}
+	}
+}
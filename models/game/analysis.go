@@ -0,0 +1,145 @@
+package game
+
+import "slices"
+
+// Caractéristiques des pièces, telles que renvoyées par l'analyse
+const (
+	CharacteristicWhite  = "white"
+	CharacteristicBlack  = "black"
+	CharacteristicSquare = "square"
+	CharacteristicRound  = "round"
+	CharacteristicTall   = "tall"
+	CharacteristicShort  = "short"
+	CharacteristicFilled = "filled"
+	CharacteristicHollow = "hollow"
+)
+
+// MaxHints est le nombre maximal d'indices par joueur pouvant être accordé dans une partie
+const MaxHints = 10
+
+// Analyze analyse la position : alignements à une pièce du Quarto, pièces empoisonnées (permettant à celui qui
+// les reçoit de gagner) et pièces sûres parmi celles qui restent à donner
+func (g Game) Analyze() AnalysisResponse {
+	analysis := AnalysisResponse{
+		GameID:         g.ID,
+		BestMoves:      []string{},
+		Threats:        []Threat{},
+		PoisonedPieces: []Piece{},
+		SafePieces:     []Piece{},
+		HintsRemaining: -1,
+	}
+
+	for _, line := range WinningLines(g.Options.Variant) {
+		if threat, ok := lineThreat(g.Board, line); ok {
+			analysis.Threats = append(analysis.Threats, threat)
+		}
+	}
+
+	for _, piece := range g.AvailablePieces {
+		poisoned := slices.ContainsFunc(analysis.Threats, func(threat Threat) bool {
+			return slices.ContainsFunc(threat.Characteristics, func(characteristic string) bool {
+				return slices.Contains(pieceCharacteristics(piece), characteristic)
+			})
+		})
+		if poisoned {
+			analysis.PoisonedPieces = append(analysis.PoisonedPieces, piece)
+		} else {
+			analysis.SafePieces = append(analysis.SafePieces, piece)
+		}
+	}
+
+	return analysis
+}
+
+// lineThreat vérifie si un alignement n'attend plus qu'une pièce pour former un Quarto
+func lineThreat(board [4][4]Piece, line [4]Position) (threat Threat, ok bool) {
+	var pieces []Piece
+	for _, square := range line {
+		notation := CoordsToPosition(square.Row, square.Col)
+		threat.Squares = append(threat.Squares, notation)
+		if piece := board[square.Row][square.Col]; piece != PieceEmpty {
+			pieces = append(pieces, piece)
+		} else if threat.Square == "" {
+			threat.Square = notation
+		}
+	}
+	if len(pieces) != 3 {
+		return threat, false
+	}
+
+	threat.Characteristics = pieceCharacteristics(pieces[0])
+	for _, piece := range pieces[1:] {
+		shared := pieceCharacteristics(piece)
+		threat.Characteristics = slices.DeleteFunc(threat.Characteristics, func(characteristic string) bool {
+			return !slices.Contains(shared, characteristic)
+		})
+	}
+	return threat, len(threat.Characteristics) > 0
+}
+
+// pieceCharacteristics retourne les quatre caractéristiques d'une pièce
+func pieceCharacteristics(piece Piece) []string {
+	color, shape, size, fill := GetPieceCharacteristics(piece)
+	return []string{
+		[]string{CharacteristicWhite, CharacteristicBlack}[color],
+		[]string{CharacteristicSquare, CharacteristicRound}[shape],
+		[]string{CharacteristicTall, CharacteristicShort}[size],
+		[]string{CharacteristicFilled, CharacteristicHollow}[fill],
+	}
+}
+
+// HintsRemaining retourne le nombre d'indices restant au joueur (-1 si illimité, une fois la partie terminée)
+func (g Game) HintsRemaining(userID int64) int {
+	if g.Status != StatusPlaying {
+		return -1
+	}
+
+	used := g.Player1HintsUsed
+	if userID == g.Player2ID && userID != g.Player1ID {
+		used = g.Player2HintsUsed
+	}
+	return max(g.Options.Hints-used, 0)
+}
+
+// CanAnalyze vérifie que le joueur peut analyser la position : pendant la partie, les indices doivent être autorisés
+func (g Game) CanAnalyze() error {
+	if g.Status == StatusPlaying && g.Options.Hints == 0 {
+		return ErrHintsDisabled
+	}
+	return nil
+}
+
+// UseHint décompte un indice du budget du joueur au trait ; les indices sont libres une fois la partie terminée
+func (g *Game) UseHint(userID int64) error {
+	if g.Status != StatusPlaying {
+		return nil
+	}
+	if err := g.CanAnalyze(); err != nil {
+		return err
+	}
+	if err := g.canPlay(userID); err != nil {
+		return err
+	}
+	if g.HintsRemaining(userID) == 0 {
+		return ErrNoHintsLeft
+	}
+
+	player2 := userID == g.Player2ID && userID != g.Player1ID
+	used, err := useHint(g.ID, player2, g.Options.Hints)
+	if err != nil {
+		return err
+	}
+	if player2 {
+		g.Player2HintsUsed = used
+	} else {
+		g.Player1HintsUsed = used
+	}
+	return nil
+}
+
+// SetHint complète l'analyse avec le coup conseillé par le moteur
+func (a *AnalysisResponse) SetHint(hint Hint, continuation []string) {
+	a.Hint = &hint
+	a.Evaluation = hint.Evaluation
+	a.BestMoves = continuation
+}
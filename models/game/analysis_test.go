@@ -0,0 +1,78 @@
+package game
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAnalyze(t *testing.T) {
+	g := InitializeGame(1, 2)
+	// Trois grandes pièces blanches sur la première rangée, d1 libre
+	for col, piece := range []Piece{0, 1, 4} {
+		playSelect(t, &g, piece)
+		playPlace(t, &g, Position{Row: 0, Col: col})
+	}
+
+	analysis := g.Analyze()
+	if len(analysis.Threats) != 1 {
+		t.Fatalf("une menace attendue, obtenu %+v", analysis.Threats)
+	}
+	threat := analysis.Threats[0]
+	if threat.Square != "d1" || !slices.Equal(threat.Characteristics, []string{CharacteristicWhite, CharacteristicTall}) {
+		t.Errorf("menace = %+v", threat)
+	}
+
+	// Toute pièce blanche ou grande compléterait la rangée
+	for _, piece := range analysis.PoisonedPieces {
+		if color, _, size, _ := GetPieceCharacteristics(piece); color != 0 && size != 0 {
+			t.Errorf("la pièce %s ne devrait pas être empoisonnée", PieceToNotation(piece))
+		}
+	}
+	if !slices.Equal(analysis.SafePieces, []Piece{10, 11, 14, 15}) {
+		t.Errorf("pièces sûres = %v", analysis.SafePieces)
+	}
+	if len(analysis.PoisonedPieces)+len(analysis.SafePieces) != len(g.AvailablePieces) {
+		t.Errorf("toutes les pièces disponibles doivent être classées")
+	}
+}
+
+func TestAnalyzeSquaresVariant(t *testing.T) {
+	g := InitializeGame(1, 2)
+	g.Options.Variant = VariantSquares
+	g.Board[1][1], g.Board[1][2], g.Board[2][1] = 0, 2, 4
+
+	analysis := g.Analyze()
+	if len(analysis.Threats) != 1 || analysis.Threats[0].Square != "c3" {
+		t.Errorf("le carré b2-c3 devrait être menacé: %+v", analysis.Threats)
+	}
+}
+
+func TestHintsRemaining(t *testing.T) {
+	g := InitializeGame(1, 2)
+	if err := g.CanAnalyze(); err == nil {
+		t.Error("l'analyse ne devrait pas être possible sans indices")
+	}
+
+	g.Options.Hints = 2
+	g.Player2HintsUsed = 2
+	if err := g.CanAnalyze(); err != nil {
+		t.Errorf("analyse refusée: %v", err)
+	}
+	if remaining := g.HintsRemaining(1); remaining != 2 {
+		t.Errorf("joueur 1: %d indices restants, attendu 2", remaining)
+	}
+	if remaining := g.HintsRemaining(2); remaining != 0 {
+		t.Errorf("joueur 2: %d indices restants, attendu 0", remaining)
+	}
+	if err := g.UseHint(2); err == nil {
+		t.Error("le joueur 2 n'est pas au trait")
+	}
+
+	g.Status = StatusFinished
+	if remaining := g.HintsRemaining(2); remaining != -1 {
+		t.Errorf("indices illimités attendus après la partie, obtenu %d", remaining)
+	}
+	if err := g.UseHint(2); err != nil {
+		t.Errorf("indice refusé après la partie: %v", err)
+	}
+}
@@ -0,0 +1,43 @@
+package game
+
+import "math/rand"
+
+// Premier joueur d'une partie contre un bot
+const (
+	BotStarterMe     = "me"
+	BotStarterBot    = "bot"
+	BotStarterRandom = "random"
+)
+
+// IsBot indique si le joueur est le bot de la partie
+func (g Game) IsBot(userID int64) bool {
+	return g.Options.Bot != nil && g.Options.Bot.PlayerID == userID
+}
+
+// IsBotTurn indique si c'est au bot de jouer
+func (g Game) IsBotTurn() bool {
+	return g.Status == StatusPlaying && g.IsBot(g.CurrentTurn)
+}
+
+// Players valide la requête et retourne les joueurs de la partie dans l'ordre, selon le premier joueur demandé
+func (r CreateBotGameRequest) Players(userID, botID int64) (player1ID, player2ID int64, err error) {
+	if !IsValidVariant(r.Variant) {
+		return 0, 0, ErrUnknownVariant.With("variant", r.Variant)
+	}
+
+	starter := r.Starter
+	if starter == BotStarterRandom {
+		starter = BotStarterMe
+		if rand.Intn(2) == 1 {
+			starter = BotStarterBot
+		}
+	}
+
+	switch starter {
+	case "", BotStarterMe:
+		return userID, botID, nil
+	case BotStarterBot:
+		return botID, userID, nil
+	}
+	return 0, 0, ErrBotStarter.With("starter", r.Starter)
+}
@@ -0,0 +1,61 @@
+package game
+
+import "time"
+
+// IsTimed indique si la partie se joue avec une limite de temps
+func (g Game) IsTimed() bool {
+	return g.Options.TimeControl.InitialSeconds > 0
+}
+
+// RemainingTime retourne le temps restant à un joueur : le temps initial, augmenté de l'incrément à chaque pièce
+// donnée, moins ses temps de réflexion, y compris celui qui court s'il a le trait
+func (g Game) RemainingTime(playerID int64, now time.Time) time.Duration {
+	control := g.Options.TimeControl
+	remaining := time.Duration(control.InitialSeconds) * time.Second
+	for _, event := range g.History {
+		if event.Actor != playerID {
+			continue
+		}
+		remaining -= time.Duration(event.ThinkingMs) * time.Millisecond
+		if event.Type == MoveEventSelect {
+			remaining += time.Duration(control.IncrementSeconds) * time.Second
+		}
+	}
+
+	if g.Status == StatusPlaying && g.CurrentTurn == playerID {
+		since := g.CreatedAt
+		if len(g.History) > 0 {
+			since = g.History[len(g.History)-1].Timestamp
+		}
+		if !since.IsZero() && now.After(since) {
+			remaining -= now.Sub(since)
+		}
+	}
+
+	return remaining
+}
+
+// FlagTimeout termine la partie si le joueur au trait a épuisé son temps, qui la perd alors ; retourne vrai si
+// la partie vient d'être perdue au temps
+func (g *Game) FlagTimeout(now time.Time) bool {
+	if g.Status != StatusPlaying || !g.IsTimed() || g.RemainingTime(g.CurrentTurn, now) > 0 {
+		return false
+	}
+
+	g.Status = StatusFinished
+	g.Winner = g.opponentOf(g.CurrentTurn)
+	g.clearPendingRequests()
+	g.UpdatedAt = now
+	return true
+}
+
+// checkClock enregistre la défaite au temps du joueur au trait s'il a épuisé son temps avant de jouer
+func (g *Game) checkClock(now time.Time) error {
+	if !g.FlagTimeout(now) {
+		return nil
+	}
+	if err := UpdateGame(*g); err != nil {
+		return ErrGameUpdate.Wrap(err)
+	}
+	return ErrTimeExpired
+}
@@ -0,0 +1,66 @@
+package game
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	g := InitializeGame(1, 2)
+	g.CreatedAt = start
+	g.Options.TimeControl = TimeControl{InitialSeconds: 60, IncrementSeconds: 2}
+
+	// Le joueur 1 donne une pièce en 10s, le joueur 2 place en 30s puis donne une pièce en 5s
+	if err := g.applySelection(0, start.Add(10*time.Second)); err != nil {
+		t.Fatalf("sélection: %v", err)
+	}
+	if err := g.applyPlacement(Position{Row: 0, Col: 0}, start.Add(40*time.Second)); err != nil {
+		t.Fatalf("placement: %v", err)
+	}
+	if err := g.applySelection(1, start.Add(45*time.Second)); err != nil {
+		t.Fatalf("sélection: %v", err)
+	}
+
+	now := start.Add(45 * time.Second)
+	if remaining := g.RemainingTime(1, now); remaining != 52*time.Second {
+		t.Errorf("temps restant du joueur 1 = %v, attendu 52s", remaining)
+	}
+	if remaining := g.RemainingTime(2, now); remaining != 27*time.Second {
+		t.Errorf("temps restant du joueur 2 = %v, attendu 27s", remaining)
+	}
+
+	// Le temps du joueur au trait s'écoule jusqu'à la défaite au temps
+	if g.FlagTimeout(now.Add(51 * time.Second)) {
+		t.Fatalf("le joueur 1 a encore du temps")
+	}
+	if !g.FlagTimeout(now.Add(52 * time.Second)) {
+		t.Fatalf("le joueur 1 aurait dû perdre au temps")
+	}
+	if g.Status != StatusFinished || g.Winner != 2 {
+		t.Errorf("statut %d, gagnant %d, attendu une victoire du joueur 2", g.Status, g.Winner)
+	}
+
+	// Sans limite de temps, aucune défaite au temps
+	untimed := InitializeGame(1, 2)
+	untimed.CreatedAt = start
+	if untimed.FlagTimeout(start.Add(24 * time.Hour)) {
+		t.Errorf("une partie sans limite de temps ne peut être perdue au temps")
+	}
+}
+
+func TestActionAfterTimeout(t *testing.T) {
+	g := InitializeGame(1, 2)
+	g.CreatedAt = time.Now().Add(-2 * time.Minute)
+	g.Options.TimeControl = TimeControl{InitialSeconds: 60}
+	g.DrawOfferedBy = 2
+
+	// Le joueur 1, au trait et sans temps, ne peut plus accepter la nulle : il a perdu au temps
+	if _, err := g.ApplyAction(1, ActionAcceptDraw); !errors.Is(err, ErrTimeExpired) {
+		t.Fatalf("nulle acceptée après la fin du temps: %v", err)
+	}
+	if g.Status != StatusFinished || g.Winner != 2 || g.DrawOfferedBy != 0 {
+		t.Errorf("statut %d, gagnant %d, nulle proposée par %d, attendu une victoire du joueur 2", g.Status, g.Winner, g.DrawOfferedBy)
+	}
+}
@@ -3,6 +3,7 @@ package game
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"quarto/models/postgresql"
 	"time"
@@ -102,74 +103,67 @@ func deserializeAvailablePiecesFromJSON(jsonStr string) ([]Piece, error) {
 	return pieces, nil
 }
 
-// serializeHistoryToJSON convertit []Move en JSON
-func serializeHistoryToJSON(history []Move) (string, error) {
-	// Convertir en structure sérialisable
-	type SerializableMove struct {
-		Piece    int `json:"piece"`
-		Position struct {
-			Row int `json:"row"`
-			Col int `json:"col"`
-		} `json:"position"`
-	}
-
-	jsonHistory := make([]SerializableMove, len(history))
-	for i, move := range history {
-		jsonHistory[i] = SerializableMove{
-			Piece: int(move.Piece),
-		}
-		jsonHistory[i].Position.Row = move.Position.Row
-		jsonHistory[i].Position.Col = move.Position.Col
+// serializeHistoryToJSON convertit []MoveEvent en JSON
+func serializeHistoryToJSON(history []MoveEvent) (string, error) {
+	if history == nil {
+		history = []MoveEvent{}
 	}
 
-	jsonData, err := json.Marshal(jsonHistory)
+	jsonData, err := json.Marshal(history)
 	if err != nil {
 		return "", err
 	}
 	return string(jsonData), nil
 }
 
-// deserializeHistoryFromJSON convertit le JSON en []Move
-func deserializeHistoryFromJSON(jsonStr string) ([]Move, error) {
+// deserializeHistoryFromJSON convertit le JSON en []MoveEvent.
+// Les anciennes entrées (placement seul, sans type) sont converties en une sélection suivie d'un placement.
+func deserializeHistoryFromJSON(jsonStr string) ([]MoveEvent, error) {
 	if jsonStr == "" || jsonStr == "null" {
-		return []Move{}, nil
-	}
-
-	type SerializableMove struct {
-		Piece    int `json:"piece"`
-		Position struct {
-			Row int `json:"row"`
-			Col int `json:"col"`
-		} `json:"position"`
+		return []MoveEvent{}, nil
 	}
 
-	var jsonHistory []SerializableMove
+	var jsonHistory []MoveEvent
 	if err := json.Unmarshal([]byte(jsonStr), &jsonHistory); err != nil {
 		return nil, fmt.Errorf("erreur de parsing de l'historique: %v", err)
 	}
 
-	history := make([]Move, len(jsonHistory))
-	for i, move := range jsonHistory {
-		history[i] = Move{
-			Piece: Piece(move.Piece),
-			Position: Position{
-				Row: move.Position.Row,
-				Col: move.Position.Col,
-			},
+	history := make([]MoveEvent, 0, len(jsonHistory))
+	for _, event := range jsonHistory {
+		if event.Type != "" {
+			history = append(history, event)
+			continue
 		}
+
+		if event.Position == nil {
+			return nil, fmt.Errorf("erreur de parsing de l'historique: placement sans position")
+		}
+		history = append(history,
+			MoveEvent{Type: MoveEventSelect, Piece: event.Piece},
+			MoveEvent{Type: MoveEventPlace, Piece: event.Piece, Position: event.Position},
+		)
 	}
 
 	return history, nil
 }
 
+// gameColumns liste les colonnes lues par ScanGame, dans l'ordre
+const gameColumns = `id, player1_id, player2_id, current_turn, game_phase,
+			board, available_pieces, selected_piece, status, winner, move_history,
+			options, created_at, updated_at, series_id, previous_game_id,
+			draw_offered_by, takeback_requested_by, player1_hints_used, player2_hints_used`
+
 // ScanGame scanne une ligne de résultat SQL en structure Game
 func ScanGame(row pgx.Row) (g Game, err error) {
 	var (
-		id                                        sql.NullString
+		id, seriesID, previousGameID              sql.NullString
 		player1ID, player2ID, currentTurn, winner sql.NullInt64
+		drawOfferedBy, takebackRequestedBy        sql.NullInt64
+		player1HintsUsed, player2HintsUsed        sql.NullInt32
 		gamePhase, status                         sql.NullInt32
 		selectedPiece                             sql.NullInt32
 		board, availablePieces, moveHistory       sql.NullString
+		options                                   sql.NullString
 		createdAt, updatedAt                      sql.NullTime
 	)
 
@@ -185,8 +179,15 @@ func ScanGame(row pgx.Row) (g Game, err error) {
 		&status,
 		&winner,
 		&moveHistory,
+		&options,
 		&createdAt,
 		&updatedAt,
+		&seriesID,
+		&previousGameID,
+		&drawOfferedBy,
+		&takebackRequestedBy,
+		&player1HintsUsed,
+		&player2HintsUsed,
 	)
 
 	if err != nil {
@@ -216,32 +217,56 @@ func ScanGame(row pgx.Row) (g Game, err error) {
 	}
 
 	// Désérialiser l'historique
-	var history []Move
+	var history []MoveEvent
 	if moveHistory.Valid {
 		history, err = deserializeHistoryFromJSON(moveHistory.String)
 		if err != nil {
 			return
 		}
 	} else {
-		history = []Move{}
+		history = []MoveEvent{}
 	}
 
-	g = Game{
-		ID:              id.String,
-		Player1ID:       player1ID.Int64,
-		Player2ID:       player2ID.Int64,
-		CurrentTurn:     currentTurn.Int64,
-		GamePhase:       int(gamePhase.Int32),
-		Board:           gameBoard,
-		AvailablePieces: pieces,
-		SelectedPiece:   Piece(selectedPiece.Int32),
-		Status:          int(status.Int32),
-		Winner:          winner.Int64,
-		History:         history,
-		CreatedAt:       createdAt.Time,
-		UpdatedAt:       updatedAt.Time,
+	// Désérialiser les options
+	var gameOptions GameOptions
+	if options.Valid && options.String != "" {
+		if err = json.Unmarshal([]byte(options.String), &gameOptions); err != nil {
+			err = fmt.Errorf("erreur de parsing des options: %v", err)
+			return
+		}
 	}
 
+	g = Game{
+		ID:                  id.String,
+		Player1ID:           player1ID.Int64,
+		Player2ID:           player2ID.Int64,
+		CurrentTurn:         currentTurn.Int64,
+		GamePhase:           int(gamePhase.Int32),
+		Board:               gameBoard,
+		AvailablePieces:     pieces,
+		SelectedPiece:       Piece(selectedPiece.Int32),
+		Status:              int(status.Int32),
+		Winner:              winner.Int64,
+		History:             history,
+		Options:             gameOptions,
+		CreatedAt:           createdAt.Time,
+		UpdatedAt:           updatedAt.Time,
+		SeriesID:            seriesID.String,
+		PreviousGameID:      previousGameID.String,
+		DrawOfferedBy:       drawOfferedBy.Int64,
+		TakebackRequestedBy: takebackRequestedBy.Int64,
+		Player1HintsUsed:    int(player1HintsUsed.Int32),
+		Player2HintsUsed:    int(player2HintsUsed.Int32),
+	}
+
+	// Les parties antérieures aux séries forment leur propre série
+	if g.SeriesID == "" {
+		g.SeriesID = g.ID
+	}
+
+	// Compléter l'historique des parties enregistrées avant le journal des sélections
+	g.completeLegacyHistory()
+
 	return
 }
 
@@ -269,16 +294,23 @@ func CreateGame(game Game) error {
 		return fmt.Errorf("erreur de sérialisation de l'historique: %v", err)
 	}
 
+	optionsJSON, err := json.Marshal(game.Options)
+	if err != nil {
+		return fmt.Errorf("erreur de sérialisation des options: %v", err)
+	}
+
 	query := `
 		INSERT INTO games (id, player1_id, player2_id, current_turn, game_phase, 
-			board, available_pieces, selected_piece, status, winner, move_history, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
+			board, available_pieces, selected_piece, status, winner, move_history, options, created_at, updated_at,
+			series_id, previous_game_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, NULLIF($16, ''))`
 
 	_, err = sqlCo.Exec(postgresql.SQLCtx, query,
 		game.ID, game.Player1ID, game.Player2ID,
 		game.CurrentTurn, game.GamePhase, boardJSON, availablePiecesJSON,
 		int(game.SelectedPiece), game.Status, game.Winner, historyJSON,
-		game.CreatedAt, game.UpdatedAt)
+		string(optionsJSON), game.CreatedAt, game.UpdatedAt,
+		game.SeriesID, game.PreviousGameID)
 
 	return err
 }
@@ -287,21 +319,23 @@ func CreateGame(game Game) error {
 func GetGameByID(gameID string) (g Game, err error) {
 	sqlCo, err := pgx.ConnectConfig(postgresql.SQLCtx, postgresql.SQLConn)
 	if err != nil {
-		err = fmt.Errorf("erreur de connexion DB: %v", err)
+		err = ErrGameLoad.Wrap(fmt.Errorf("erreur de connexion DB: %w", err))
 		return
 	}
 	defer sqlCo.Close(postgresql.SQLCtx)
 
 	query := `
-		SELECT id, player1_id, player2_id, current_turn, game_phase,
-			board, available_pieces, selected_piece, status, winner, move_history,
-			created_at, updated_at
+		SELECT ` + gameColumns + `
 		FROM games WHERE id = $1`
 
 	row := sqlCo.QueryRow(postgresql.SQLCtx, query, gameID)
 	g, err = ScanGame(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		err = ErrGameNotFound
+		return
+	}
 	if err != nil {
-		err = fmt.Errorf("partie non trouvée: %v", err)
+		err = ErrGameLoad.Wrap(err)
 		return
 	}
 
@@ -335,17 +369,47 @@ func UpdateGame(game Game) error {
 	query := `
 		UPDATE games 
 		SET current_turn = $1, game_phase = $2, board = $3, available_pieces = $4,
-			selected_piece = $5, status = $6, winner = $7, move_history = $8, updated_at = $9
-		WHERE id = $10`
+			selected_piece = $5, status = $6, winner = $7, move_history = $8, updated_at = $9,
+			draw_offered_by = $10, takeback_requested_by = $11
+		WHERE id = $12`
 
 	_, err = sqlCo.Exec(postgresql.SQLCtx, query,
 		game.CurrentTurn, game.GamePhase, boardJSON, availablePiecesJSON,
 		int(game.SelectedPiece), game.Status, game.Winner, historyJSON,
-		time.Now(), game.ID)
+		time.Now(), game.DrawOfferedBy, game.TakebackRequestedBy, game.ID)
 
 	return err
 }
 
+// useHint décompte un indice du joueur si son budget le permet et retourne le nombre d'indices consommés
+func useHint(gameID string, player2 bool, budget int) (used int, err error) {
+	sqlCo, err := pgx.ConnectConfig(postgresql.SQLCtx, postgresql.SQLConn)
+	if err != nil {
+		return 0, fmt.Errorf("erreur de connexion DB: %v", err)
+	}
+	defer sqlCo.Close(postgresql.SQLCtx)
+
+	column := "player1_hints_used"
+	if player2 {
+		column = "player2_hints_used"
+	}
+
+	// L'incrément conditionnel évite de dépasser le budget avec des demandes simultanées
+	query := `
+		UPDATE games SET ` + column + ` = ` + column + ` + 1
+		WHERE id = $1 AND ` + column + ` < $2
+		RETURNING ` + column
+
+	err = sqlCo.QueryRow(postgresql.SQLCtx, query, gameID, budget).Scan(&used)
+	if err == pgx.ErrNoRows {
+		return 0, ErrNoHintsLeft
+	}
+	if err != nil {
+		return 0, ErrGameUpdate.Wrap(err)
+	}
+	return used, nil
+}
+
 // GetUserGames récupère toutes les parties d'un utilisateur
 func GetUserGames(userID int64) ([]Game, error) {
 	sqlCo, err := pgx.ConnectConfig(postgresql.SQLCtx, postgresql.SQLConn)
@@ -355,9 +419,7 @@ func GetUserGames(userID int64) ([]Game, error) {
 	defer sqlCo.Close(postgresql.SQLCtx)
 
 	query := `
-		SELECT id, player1_id, player2_id, current_turn, game_phase,
-			board, available_pieces, selected_piece, status, winner, move_history,
-			created_at, updated_at
+		SELECT ` + gameColumns + `
 		FROM games 
 		WHERE player1_id = $1 OR player2_id = $1
 		ORDER BY created_at DESC`
@@ -389,9 +451,7 @@ func GetActiveGames(userID int64) ([]Game, error) {
 	defer sqlCo.Close(postgresql.SQLCtx)
 
 	query := `
-		SELECT id, player1_id, player2_id, current_turn, game_phase,
-			board, available_pieces, selected_piece, status, winner, move_history,
-			created_at, updated_at
+		SELECT ` + gameColumns + `
 		FROM games 
 		WHERE (player1_id = $1 OR player2_id = $1) AND status = 0
 		ORDER BY updated_at DESC`
@@ -414,6 +474,82 @@ func GetActiveGames(userID int64) ([]Game, error) {
 	return games, nil
 }
 
+// GetTimedActiveGames récupère les parties en cours jouées avec une limite de temps
+func GetTimedActiveGames() ([]Game, error) {
+	sqlCo, err := pgx.ConnectConfig(postgresql.SQLCtx, postgresql.SQLConn)
+	if err != nil {
+		return nil, fmt.Errorf("erreur de connexion DB: %v", err)
+	}
+	defer sqlCo.Close(postgresql.SQLCtx)
+
+	query := `
+		SELECT ` + gameColumns + `
+		FROM games 
+		WHERE status = 0 AND COALESCE((options->'time_control'->>'initial_seconds')::int, 0) > 0`
+
+	rows, err := sqlCo.Query(postgresql.SQLCtx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var games []Game
+	for rows.Next() {
+		game, err := ScanGame(rows)
+		if err != nil {
+			return nil, err
+		}
+		games = append(games, game)
+	}
+
+	return games, nil
+}
+
+// GetSeriesGames récupère les parties d'une série dans l'ordre où elles ont été jouées
+func GetSeriesGames(seriesID string) ([]Game, error) {
+	sqlCo, err := pgx.ConnectConfig(postgresql.SQLCtx, postgresql.SQLConn)
+	if err != nil {
+		return nil, fmt.Errorf("erreur de connexion DB: %v", err)
+	}
+	defer sqlCo.Close(postgresql.SQLCtx)
+
+	query := `
+		SELECT ` + gameColumns + `
+		FROM games 
+		WHERE COALESCE(series_id, id) = $1
+		ORDER BY created_at ASC`
+
+	rows, err := sqlCo.Query(postgresql.SQLCtx, query, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var games []Game
+	for rows.Next() {
+		game, err := ScanGame(rows)
+		if err != nil {
+			return nil, err
+		}
+		games = append(games, game)
+	}
+
+	return games, nil
+}
+
+// HasRematch vérifie si une revanche a déjà été créée à partir d'une partie
+func HasRematch(gameID string) (bool, error) {
+	sqlCo, err := pgx.ConnectConfig(postgresql.SQLCtx, postgresql.SQLConn)
+	if err != nil {
+		return false, fmt.Errorf("erreur de connexion DB: %v", err)
+	}
+	defer sqlCo.Close(postgresql.SQLCtx)
+
+	var exists bool
+	err = sqlCo.QueryRow(postgresql.SQLCtx, `SELECT EXISTS (SELECT 1 FROM games WHERE previous_game_id = $1)`, gameID).Scan(&exists)
+	return exists, err
+}
+
 // DeleteGame supprime une partie (pour les tests ou le nettoyage)
 func DeleteGame(gameID string) error {
 	sqlCo, err := pgx.ConnectConfig(postgresql.SQLCtx, postgresql.SQLConn)
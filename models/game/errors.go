@@ -0,0 +1,107 @@
+package game
+
+import (
+	"errors"
+	"net/http"
+	"quarto/models/apperror"
+)
+
+// Erreurs métier des parties
+var (
+	ErrGameNotFound  = apperror.New("game.not_found", http.StatusNotFound, "partie non trouvée", "game not found")
+	ErrGameForbidden = apperror.New("game.forbidden", http.StatusForbidden, "vous n'avez pas accès à cette partie", "you do not have access to this game")
+	ErrGameNotActive = apperror.New("game.not_active", http.StatusConflict, "cette partie n'est plus active", "this game is no longer active")
+	ErrGameLoad      = apperror.New("game.load_failed", http.StatusInternalServerError, "erreur lors du chargement de la partie", "failed to load the game")
+	ErrGameUpdate    = apperror.New("game.update_failed", http.StatusInternalServerError, "erreur lors de la mise à jour du jeu", "failed to update the game")
+
+	ErrNotSelectPhase   = apperror.New("game.not_select_phase", http.StatusConflict, "ce n'est pas la phase de sélection de pièce", "it is not the piece selection phase")
+	ErrNotPlacePhase    = apperror.New("game.not_place_phase", http.StatusConflict, "ce n'est pas la phase de placement de pièce", "it is not the piece placement phase")
+	ErrNotYourTurn      = apperror.New("game.not_your_turn", http.StatusConflict, "ce n'est pas le tour du joueur {player}", "it is not player {player}'s turn")
+	ErrPieceUnavailable = apperror.New("game.piece_unavailable", http.StatusUnprocessableEntity, "cette pièce n'est pas disponible", "this piece is not available")
+	ErrNoSelectedPiece  = apperror.New("game.no_selected_piece", http.StatusConflict, "aucune pièce n'est sélectionnée", "no piece is selected")
+	ErrInvalidPosition  = apperror.New("game.invalid_position", http.StatusUnprocessableEntity, "position invalide", "invalid position")
+	ErrInvalidPiece     = apperror.New("game.invalid_piece_notation", http.StatusBadRequest, "notation de pièce invalide: {piece}", "invalid piece notation: {piece}")
+	ErrInvalidMove      = apperror.New("game.invalid_move_notation", http.StatusBadRequest, "notation de coup invalide: {move}", "invalid move notation: {move}")
+	ErrSquareOccupied   = apperror.New("game.square_occupied", http.StatusUnprocessableEntity, "cette position est déjà occupée", "this square is already occupied")
+	ErrTimeExpired      = apperror.New("game.time_expired", http.StatusConflict, "votre temps est écoulé, la partie est perdue au temps", "your time has run out, the game is lost on time")
+	ErrWrongPiece       = apperror.New("game.wrong_piece", http.StatusUnprocessableEntity, "la pièce placée n'est pas la pièce sélectionnée", "the placed piece is not the selected piece")
+	ErrUnknownVariant   = apperror.New("game.unknown_variant", http.StatusUnprocessableEntity, "variante inconnue: {variant}", "unknown variant: {variant}")
+
+	ErrUnknownAction         = apperror.New("game.unknown_action", http.StatusBadRequest, "action inconnue: {action}", "unknown action: {action}")
+	ErrDrawOffersRated       = apperror.New("game.draw_offers_disabled", http.StatusForbidden, "les propositions de nulle ne sont pas autorisées dans les parties classées", "draw offers are not allowed in rated games")
+	ErrDrawAlreadyOffered    = apperror.New("game.draw_already_offered", http.StatusConflict, "vous avez déjà proposé la nulle", "you have already offered a draw")
+	ErrNoDrawOffer           = apperror.New("game.no_draw_offer", http.StatusConflict, "aucune proposition de nulle de votre adversaire", "your opponent has not offered a draw")
+	ErrTakebacksRated        = apperror.New("game.takebacks_disabled", http.StatusForbidden, "les annulations de coup ne sont pas autorisées dans les parties classées", "takebacks are not allowed in rated games")
+	ErrTakebackPending       = apperror.New("game.takeback_pending", http.StatusConflict, "une demande d'annulation est déjà en attente", "a takeback request is already pending")
+	ErrNothingToTakeBack     = apperror.New("game.nothing_to_take_back", http.StatusConflict, "vous n'avez aucun placement à annuler", "you have no placement to take back")
+	ErrNoTakebackRequest     = apperror.New("game.no_takeback_request", http.StatusConflict, "aucune demande d'annulation de votre adversaire", "your opponent has not requested a takeback")
+	ErrCallQuartoDisabled    = apperror.New("game.call_quarto_disabled", http.StatusConflict, "les victoires sont détectées automatiquement dans cette partie", "wins are detected automatically in this game")
+	ErrNothingToClaim        = apperror.New("game.nothing_to_claim", http.StatusConflict, "aucun placement ne peut encore former de Quarto", "no placement can form a Quarto yet")
+	ErrClaimTooLate          = apperror.New("game.claim_too_late", http.StatusConflict, "vous avez déjà donné une pièce, il est trop tard pour annoncer Quarto", "you have already given a piece, it is too late to call Quarto")
+	ErrOpponentCanStillClaim = apperror.New("game.claim_not_yet", http.StatusConflict, "votre adversaire peut encore annoncer son Quarto", "your opponent can still call their Quarto")
+
+	ErrBotAction      = apperror.New("game.bot_action", http.StatusConflict, "le bot n'accepte ni nulle ni annulation de coup", "the bot does not accept draws or takebacks")
+	ErrBotEngine      = apperror.New("game.unknown_bot_engine", http.StatusUnprocessableEntity, "moteur inconnu: {engine}", "unknown engine: {engine}")
+	ErrBotTimeLimit   = apperror.New("game.invalid_bot_time_limit", http.StatusUnprocessableEntity, "temps de réflexion du bot invalide: {ms} ms (max {max})", "invalid bot thinking time: {ms} ms (max {max})")
+	ErrBotStarter     = apperror.New("game.invalid_bot_starter", http.StatusUnprocessableEntity, "premier joueur invalide: {starter}", "invalid starting player: {starter}")
+	ErrBotUnavailable = apperror.New("game.bot_unavailable", http.StatusServiceUnavailable, "le bot n'est pas disponible", "the bot is not available")
+
+	ErrHintsDisabled = apperror.New("game.hints_disabled", http.StatusForbidden, "les indices ne sont pas autorisés dans cette partie", "hints are not allowed in this game")
+	ErrNoHintsLeft   = apperror.New("game.no_hints_left", http.StatusConflict, "vous avez utilisé tous vos indices", "you have used all your hints")
+
+	ErrInvalidPly      = apperror.New("game.invalid_ply", http.StatusBadRequest, "demi-coup invalide: {ply} (la partie en compte {total})", "invalid ply: {ply} (the game has {total})")
+	ErrIllegalHistory  = apperror.New("game.illegal_history", http.StatusUnprocessableEntity, "demi-coup {ply} illégal", "illegal ply {ply}")
+	ErrInvalidFEN      = apperror.New("game.invalid_fen", http.StatusBadRequest, "position invalide: {reason}", "invalid position: {reason}")
+	ErrInvalidRecord   = apperror.New("game.invalid_record", http.StatusBadRequest, "enregistrement invalide: {reason}", "invalid record: {reason}")
+	ErrResultMismatch  = apperror.New("game.result_mismatch", http.StatusUnprocessableEntity, "le résultat {result} ne correspond pas à la position finale ({expected})", "the result {result} does not match the final position ({expected})")
+	ErrUnknownMoveType = apperror.New("game.unknown_move_type", http.StatusUnprocessableEntity, "type d'action inconnu: {type}", "unknown move type: {type}")
+	ErrMissingPosition = apperror.New("game.missing_position", http.StatusUnprocessableEntity, "placement sans position", "placement without a position")
+)
+
+// Motifs de ErrInvalidFEN et ErrInvalidRecord, exposés par leur code dans le détail reason
+var (
+	errFENFieldCount    = apperror.New("fen.field_count", http.StatusBadRequest, "4 champs attendus (plateau, pièce en main, joueur au trait, phase) suivis de la variante", "4 fields expected (board, piece in hand, player to move, phase) followed by the variant")
+	errFENVariant       = apperror.New("fen.unknown_variant", http.StatusBadRequest, "variante inconnue {variant}", "unknown variant {variant}")
+	errFENRankCount     = apperror.New("fen.rank_count", http.StatusBadRequest, "4 rangées attendues", "4 ranks expected")
+	errFENRankLength    = apperror.New("fen.rank_length", http.StatusBadRequest, "la rangée {rank} doit contenir 4 cases", "rank {rank} must contain 4 squares")
+	errFENPiece         = apperror.New("fen.unknown_piece", http.StatusBadRequest, "pièce inconnue {piece}", "unknown piece {piece}")
+	errFENDuplicate     = apperror.New("fen.duplicate_piece", http.StatusBadRequest, "la pièce {piece} apparaît plusieurs fois", "piece {piece} appears more than once")
+	errFENHandOnBoard   = apperror.New("fen.piece_in_hand_on_board", http.StatusBadRequest, "la pièce en main {piece} est déjà sur le plateau", "the piece in hand {piece} is already on the board")
+	errFENHandInSelect  = apperror.New("fen.piece_in_hand_during_selection", http.StatusBadRequest, "aucune pièce ne doit être en main pendant la sélection", "no piece may be in hand during the selection")
+	errFENNoHandInPlace = apperror.New("fen.no_piece_in_hand", http.StatusBadRequest, "une pièce doit être en main pendant le placement", "a piece must be in hand during the placement")
+	errFENBoardFull     = apperror.New("fen.board_full", http.StatusBadRequest, "le plateau est plein", "the board is full")
+	errFENPhase         = apperror.New("fen.unknown_phase", http.StatusBadRequest, "phase inconnue {phase}", "unknown phase {phase}")
+	errFENWrongPlayer   = apperror.New("fen.wrong_player", http.StatusBadRequest, "avec {placed} pièces posées, c'est au joueur {expected} de jouer", "with {placed} pieces placed, it is player {expected}'s turn")
+	errFENPlayer        = apperror.New("fen.unknown_player", http.StatusBadRequest, "joueur au trait inconnu {player}", "unknown player to move {player}")
+	errFENAlreadyWon    = apperror.New("fen.already_won", http.StatusBadRequest, "la partie est déjà gagnée", "the game is already won")
+
+	errRecordTagUnclosed    = apperror.New("record.tag_unclosed", http.StatusBadRequest, "ligne {line}: balise non fermée", "line {line}: unclosed tag")
+	errRecordTagName        = apperror.New("record.tag_name", http.StatusBadRequest, "ligne {line}: nom de balise invalide", "line {line}: invalid tag name")
+	errRecordTagValue       = apperror.New("record.tag_value", http.StatusBadRequest, "ligne {line}: valeur de balise invalide", "line {line}: invalid tag value")
+	errRecordTagEscape      = apperror.New("record.tag_escape", http.StatusBadRequest, "ligne {line}: échappement invalide dans la balise {tag}", "line {line}: invalid escape in tag {tag}")
+	errRecordTagQuote       = apperror.New("record.tag_quote", http.StatusBadRequest, "ligne {line}: guillemet non échappé dans la balise {tag}", "line {line}: unescaped quote in tag {tag}")
+	errRecordTagNewline     = apperror.New("record.tag_newline", http.StatusBadRequest, "ligne {line}: retour à la ligne dans la balise {tag}", "line {line}: line break in tag {tag}")
+	errRecordTagDuplicate   = apperror.New("record.duplicate_tag", http.StatusBadRequest, "ligne {line}: balise {tag} en double", "line {line}: duplicate tag {tag}")
+	errRecordCommentOpen    = apperror.New("record.comment_not_opened", http.StatusBadRequest, "commentaire fermé sans être ouvert", "comment closed without being opened")
+	errRecordCommentClose   = apperror.New("record.comment_not_closed", http.StatusBadRequest, "commentaire non fermé", "unclosed comment")
+	errRecordAfterResult    = apperror.New("record.after_result", http.StatusBadRequest, "contenu inattendu après le résultat: {token}", "unexpected content after the result: {token}")
+	errRecordMoveNumber     = apperror.New("record.move_number", http.StatusBadRequest, "numéro de tour invalide: {token}", "invalid move number: {token}")
+	errRecordNoPlacement    = apperror.New("record.missing_placement", http.StatusBadRequest, "tour {move}: placement manquant", "move {move}: missing placement")
+	errRecordNoPiece        = apperror.New("record.placement_without_piece", http.StatusBadRequest, "placement sans pièce donnée: {token}", "placement without a given piece: {token}")
+	errRecordWrongPiece     = apperror.New("record.wrong_piece", http.StatusBadRequest, "tour {move}: la pièce placée {placed} n'est pas la pièce donnée {piece}", "move {move}: the placed piece {placed} is not the given piece {piece}")
+	errRecordPiece          = apperror.New("record.invalid_piece", http.StatusBadRequest, "notation de pièce invalide: {token}", "invalid piece notation: {token}")
+	errRecordPosition       = apperror.New("record.invalid_position", http.StatusBadRequest, "position invalide: {token}", "invalid position: {token}")
+	errRecordClaim          = apperror.New("record.unexpected_claim", http.StatusBadRequest, "annonce de Quarto inattendue", "unexpected Quarto claim")
+	errRecordNoResult       = apperror.New("record.missing_result", http.StatusBadRequest, "résultat manquant en fin de partie", "missing result at the end of the game")
+	errRecordVariant        = apperror.New("record.unknown_variant", http.StatusBadRequest, "variante inconnue: {variant}", "unknown variant: {variant}")
+	errRecordResultMismatch = apperror.New("record.result_tag_mismatch", http.StatusBadRequest, "le résultat {result} ne correspond pas à la balise Result {tag}", "the result {result} does not match the Result tag {tag}")
+)
+
+// invalidBecause complète une erreur de décodage avec son motif ; une erreur sans motif métier est insérée telle quelle
+func invalidBecause(invalid *apperror.Error, err error) *apperror.Error {
+	var reason *apperror.Error
+	if errors.As(err, &reason) {
+		return invalid.Because(reason)
+	}
+	return invalid.With("reason", err.Error())
+}
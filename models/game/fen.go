@@ -0,0 +1,180 @@
+package game
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Notation compacte d'une position (équivalent du FEN pour Quarto) :
+//
+//	0.3./..../..a./.... 5 1 p
+//
+// - le plateau, rangée par rangée de a1 (en haut à gauche) à d4, une pièce par caractère hexadécimal et "." pour une case vide
+// - la pièce en main, donnée à l'adversaire ("-" si aucune)
+// - le joueur au trait ("1" pour le joueur qui sélectionne en premier, "2" pour l'autre)
+// - la phase ("s" pour la sélection d'une pièce, "p" pour son placement)
+// - la variante, omise pour la variante standard
+const (
+	fenEmpty         = '.'
+	fenNoPiece       = "-"
+	fenPhaseSelect   = "s"
+	fenPhasePlace    = "p"
+	fenRankSeparator = "/"
+)
+
+// ToFEN encode la position courante de la partie
+func (g Game) ToFEN() string {
+	var b strings.Builder
+
+	for row := range 4 {
+		if row > 0 {
+			b.WriteString(fenRankSeparator)
+		}
+		for col := range 4 {
+			piece := g.Board[row][col]
+			if piece == PieceEmpty {
+				b.WriteByte(fenEmpty)
+			} else {
+				b.WriteString(strconv.FormatInt(int64(piece), 16))
+			}
+		}
+	}
+
+	hand, phase := fenNoPiece, fenPhaseSelect
+	if g.SelectedPiece != PieceEmpty {
+		hand, phase = strconv.FormatInt(int64(g.SelectedPiece), 16), fenPhasePlace
+	}
+
+	side := "1"
+	if g.CurrentTurn == g.Player2ID && g.Player2ID != g.Player1ID {
+		side = "2"
+	}
+
+	fmt.Fprintf(&b, " %s %s %s", hand, side, phase)
+	if variant := g.Options.VariantOrDefault(); variant != VariantStandard {
+		b.WriteString(" " + variant)
+	}
+	return b.String()
+}
+
+// ParseFEN décode une position et la vérifie (pièces en double, joueur au trait et phase cohérents avec le plateau)
+func ParseFEN(fen string, player1ID, player2ID int64) (g Game, err error) {
+	defer func() {
+		if err != nil {
+			err = invalidBecause(ErrInvalidFEN, err)
+		}
+	}()
+
+	fields := strings.Fields(fen)
+	if len(fields) != 4 && len(fields) != 5 {
+		return g, errFENFieldCount
+	}
+
+	g = InitializeGame(player1ID, player2ID)
+	if len(fields) == 5 {
+		if !IsValidVariant(fields[4]) {
+			return g, errFENVariant.With("variant", fields[4])
+		}
+		g.Options.Variant = fields[4]
+	}
+	used := make(map[Piece]bool)
+
+	ranks := strings.Split(fields[0], fenRankSeparator)
+	if len(ranks) != 4 {
+		return g, errFENRankCount
+	}
+	placed := 0
+	for row, rank := range ranks {
+		if len(rank) != 4 {
+			return g, errFENRankLength.With("rank", row+1)
+		}
+		for col := range 4 {
+			if rank[col] == fenEmpty {
+				continue
+			}
+			piece, err := parseFENPiece(rank[col : col+1])
+			if err != nil {
+				return g, err
+			}
+			if used[piece] {
+				return g, errFENDuplicate.With("piece", PieceToNotation(piece))
+			}
+			used[piece] = true
+			g.Board[row][col] = piece
+			placed++
+		}
+	}
+
+	if fields[1] != fenNoPiece {
+		piece, err := parseFENPiece(fields[1])
+		if err != nil {
+			return g, err
+		}
+		if used[piece] {
+			return g, errFENHandOnBoard.With("piece", PieceToNotation(piece))
+		}
+		used[piece] = true
+		g.SelectedPiece = piece
+	}
+
+	switch fields[3] {
+	case fenPhaseSelect:
+		if g.SelectedPiece != PieceEmpty {
+			return g, errFENHandInSelect
+		}
+		g.GamePhase = GamePhaseSelectPiece
+	case fenPhasePlace:
+		if g.SelectedPiece == PieceEmpty {
+			return g, errFENNoHandInPlace
+		}
+		if placed == 16 {
+			return g, errFENBoardFull
+		}
+		g.GamePhase = GamePhasePlacePiece
+	default:
+		return g, errFENPhase.With("phase", fields[3])
+	}
+
+	// Le joueur 1 sélectionne aux tours pairs : le joueur au trait se déduit du nombre de pièces posées
+	expected := "1"
+	if (placed%2 == 1) == (g.GamePhase == GamePhaseSelectPiece) {
+		expected = "2"
+	}
+	switch fields[2] {
+	case "1", "2":
+		if fields[2] != expected {
+			return g, errFENWrongPlayer.With("placed", placed).With("expected", expected)
+		}
+	default:
+		return g, errFENPlayer.With("player", fields[2])
+	}
+	if expected == "2" {
+		g.CurrentTurn = player2ID
+	}
+
+	g.AvailablePieces = slices.DeleteFunc(g.AvailablePieces, func(piece Piece) bool { return used[piece] })
+
+	// Une position gagnante ou un plateau plein termine la partie
+	if CheckWinVariant(g.Board, g.Options.Variant) {
+		if g.GamePhase != GamePhaseSelectPiece {
+			return g, errFENAlreadyWon
+		}
+		g.Status = StatusFinished
+		g.Winner = g.CurrentTurn
+	} else if placed == 16 {
+		g.Status = StatusFinished
+	}
+
+	return g, nil
+}
+
+// parseFENPiece analyse une pièce en hexadécimal
+func parseFENPiece(token string) (Piece, error) {
+	value, err := strconv.ParseInt(token, 16, 8)
+	if err != nil || len(token) != 1 || !IsValidPiece(Piece(value)) {
+		return PieceEmpty, errFENPiece.With("piece", token)
+	}
+	return Piece(value), nil
+}
@@ -0,0 +1,95 @@
+package game
+
+import (
+	"errors"
+	"quarto/models/apperror"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFENRoundTrip(t *testing.T) {
+	g := InitializeGame(1, 2)
+	if fen := g.ToFEN(); fen != "..../..../..../.... - 1 s" {
+		t.Errorf("position initiale = %q", fen)
+	}
+
+	now := time.Now()
+	steps := []func() error{
+		func() error { return g.applySelection(0, now) },
+		func() error { return g.applyPlacement(Position{Row: 0, Col: 0}, now) },
+		func() error { return g.applySelection(10, now) },
+		func() error { return g.applyPlacement(Position{Row: 2, Col: 2}, now) },
+		func() error { return g.applySelection(3, now) },
+		func() error { return g.applyPlacement(Position{Row: 0, Col: 2}, now) },
+		func() error { return g.applySelection(5, now) },
+	}
+	for i, step := range steps {
+		if err := step(); err != nil {
+			t.Fatalf("coup %d: %v", i+1, err)
+		}
+
+		fen := g.ToFEN()
+		decoded, err := ParseFEN(fen, 1, 2)
+		if err != nil {
+			t.Fatalf("coup %d: décodage de %q: %v", i+1, fen, err)
+		}
+		if decoded.Board != g.Board || decoded.SelectedPiece != g.SelectedPiece || decoded.GamePhase != g.GamePhase ||
+			decoded.CurrentTurn != g.CurrentTurn || len(decoded.AvailablePieces) != len(g.AvailablePieces) {
+			t.Errorf("coup %d: %q décodé en %+v, attendu %+v", i+1, fen, decoded, g)
+		}
+	}
+
+	if fen := g.ToFEN(); fen != "0.3./..../..a./.... 5 1 p" {
+		t.Errorf("position finale = %q", fen)
+	}
+}
+
+func TestParseFEN(t *testing.T) {
+	// Ligne de pièces blanches complétée par le joueur 2 au 4e placement
+	g, err := ParseFEN("0123/4.../..../.... - 2 s", 1, 2)
+	if err != nil {
+		t.Fatalf("position gagnée: %v", err)
+	}
+	if g.Status != StatusFinished || g.Winner != 2 {
+		t.Errorf("victoire du joueur 2 attendue: status=%d winner=%d", g.Status, g.Winner)
+	}
+
+	// Le carré b2-c3, complété par le joueur 1, ne gagne que dans la variante des carrés
+	if g, err := ParseFEN("..../.01./.23./.... - 1 s", 1, 2); err != nil || g.Status != StatusPlaying {
+		t.Errorf("carré en variante standard: status=%d err=%v", g.Status, err)
+	}
+	g, err = ParseFEN("..../.01./.23./.... - 1 s squares", 1, 2)
+	if err != nil || g.Status != StatusFinished || g.Options.Variant != VariantSquares {
+		t.Errorf("carré en variante des carrés: %+v err=%v", g, err)
+	}
+	if fen := g.ToFEN(); fen != "..../.01./.23./.... - 1 s squares" {
+		t.Errorf("variante non encodée: %q", fen)
+	}
+
+	// Le motif est exposé par un code indépendant de la langue et traduit dans les deux messages
+	invalid := map[string]*apperror.Error{
+		"..../..../..../....":                errFENFieldCount,
+		".../..../..../.... - 1 s":           errFENRankLength,
+		"0..0/..../..../.... - 1 s":          errFENDuplicate,
+		"0.../..../..../.... 0 2 p":          errFENHandOnBoard,
+		"x.../..../..../.... - 2 s":          errFENPiece,
+		"0.../..../..../.... - 1 p":          errFENNoHandInPlace,
+		"0.../..../..../.... 1 2 s":          errFENHandInSelect,
+		"0.../..../..../.... - 1 s":          errFENWrongPlayer,
+		"..../..../..../.... - 1 x":          errFENPhase,
+		"0123/..../..../.... 5 2 p":          errFENAlreadyWon,
+		"..../..../..../.... - 1 s hexagons": errFENVariant,
+	}
+	for fen, reason := range invalid {
+		_, err := ParseFEN(fen, 1, 2)
+		var appErr *apperror.Error
+		if !errors.As(err, &appErr) || !errors.Is(appErr, ErrInvalidFEN) || appErr.Details["reason"] != reason.Code {
+			t.Errorf("%q: erreur %v, motif %s attendu", fen, err, reason.Code)
+			continue
+		}
+		if message := appErr.Message(apperror.LangEN); strings.Contains(message, "{") || message == "invalid position: " {
+			t.Errorf("%q: message anglais incomplet %q", fen, message)
+		}
+	}
+}
@@ -1,29 +1,86 @@
 package game
 
 import (
-	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 )
 
-// CreateNewGame crée une nouvelle partie entre deux joueurs
-func CreateNewGame(player1ID, player2ID int64) (g Game, err error) {
+// CreateNewGame crée une nouvelle partie entre deux joueurs (le joueur 1 commence)
+func CreateNewGame(player1ID, player2ID int64, options GameOptions) (g Game, err error) {
 	g = InitializeGame(player1ID, player2ID)
 	g.ID = uuid.New().String()
+	g.Options = options
 	g.CreatedAt = time.Now()
 	g.UpdatedAt = time.Now()
+	g.SeriesID = g.ID
+
+	err = CreateGame(g)
+	return
+}
+
+// CreateRematchGame crée la revanche d'une partie terminée, rattachée à la même série
+func CreateRematchGame(previous Game, player1ID, player2ID int64, options GameOptions) (g Game, err error) {
+	g = InitializeGame(player1ID, player2ID)
+	g.ID = uuid.New().String()
+	g.Options = options
+	g.CreatedAt = time.Now()
+	g.UpdatedAt = time.Now()
+	g.SeriesID = previous.SeriesID
+	g.PreviousGameID = previous.ID
 
 	err = CreateGame(g)
 	return
 }
 
 // SelectPiece sélectionne une pièce pour le prochain coup
-func (g *Game) SelectPiece(piece Piece) error {
+func (g *Game) SelectPiece(userID int64, piece Piece) error {
+	if err := g.canPlay(userID); err != nil {
+		return err
+	}
+	now := time.Now()
+	if err := g.checkClock(now); err != nil {
+		return err
+	}
+	if err := g.applySelection(piece, now); err != nil {
+		return err
+	}
+
+	err := UpdateGame(*g)
+	if err != nil {
+		return ErrGameUpdate.Wrap(err)
+	}
+
+	return nil
+}
+
+// PlacePiece place une pièce sur le plateau
+func (g *Game) PlacePiece(userID int64, position Position) (err error) {
+	if err = g.canPlay(userID); err != nil {
+		return
+	}
+	now := time.Now()
+	if err = g.checkClock(now); err != nil {
+		return
+	}
+	if err = g.applyPlacement(position, now); err != nil {
+		return
+	}
+
+	err = UpdateGame(*g)
+	if err != nil {
+		return ErrGameUpdate.Wrap(err)
+	}
+
+	return
+}
+
+// applySelection applique la sélection d'une pièce pour l'adversaire, sans l'enregistrer
+func (g *Game) applySelection(piece Piece, at time.Time) error {
 
 	// Vérifier que c'est la phase de sélection
 	if g.GamePhase != GamePhaseSelectPiece {
-		return fmt.Errorf("ce n'est pas la phase de sélection de pièce")
+		return ErrNotSelectPhase
 	}
 
 	pieceAvailable := false
@@ -35,9 +92,12 @@ func (g *Game) SelectPiece(piece Piece) error {
 	}
 
 	if !pieceAvailable {
-		return fmt.Errorf("cette pièce n'est pas disponible")
+		return ErrPieceUnavailable
 	}
 
+	// Enregistrer la sélection dans l'historique
+	g.History = append(g.History, g.newMoveEvent(MoveEventSelect, piece, nil, at))
+
 	// Mettre à jour le jeu
 	g.SelectedPiece = piece
 	g.GamePhase = GamePhasePlacePiece
@@ -52,47 +112,51 @@ func (g *Game) SelectPiece(piece Piece) error {
 	g.AvailablePieces = newAvailablePieces
 
 	g.switchTurn()
-	g.UpdatedAt = time.Now()
-
-	err := UpdateGame(*g)
-	if err != nil {
-		return fmt.Errorf("erreur lors de la mise à jour du jeu: %v", err)
-	}
+	g.clearPendingRequests()
+	g.UpdatedAt = at
 
 	return nil
 }
 
-// PlacePiece place une pièce sur le plateau
-func (g *Game) PlacePiece(position Position) (err error) {
+// applyPlacement applique le placement de la pièce sélectionnée, sans l'enregistrer
+func (g *Game) applyPlacement(position Position, at time.Time) error {
 
 	// Vérifier que c'est la phase de placement
 	if g.GamePhase != GamePhasePlacePiece {
-		return fmt.Errorf("ce n'est pas la phase de placement de pièce")
+		return ErrNotPlacePhase
 	}
 
 	// Vérifier qu'une pièce est sélectionnée
 	if !IsValidPiece(g.SelectedPiece) || g.SelectedPiece == PieceEmpty {
-		return fmt.Errorf("aucune pièce n'est sélectionnée")
+		return ErrNoSelectedPiece
+	}
+
+	if !IsValidRow(position.Row) || !IsValidCol(position.Col) {
+		return ErrInvalidPosition
 	}
 
 	if g.Board[position.Row][position.Col] != PieceEmpty {
-		return fmt.Errorf("cette position est déjà occupée")
+		return ErrSquareOccupied
 	}
 
 	// Placer la pièce
 	g.Board[position.Row][position.Col] = g.SelectedPiece
 
 	// Mettre à jour l'historique des mouvements
-	g.History = append(g.History, Move{
-		Piece:    g.SelectedPiece,
-		Position: position,
-	})
+	g.History = append(g.History, g.newMoveEvent(MoveEventPlace, g.SelectedPiece, &position, at))
 
 	g.SelectedPiece = PieceEmpty // Réinitialiser la pièce sélectionnée
-	g.UpdatedAt = time.Now()
+	g.clearPendingRequests()
+	g.UpdatedAt = at
+
+	// Vérifier les conditions de victoire ; avec l'annonce de Quarto, seule la dernière pièce posée termine
+	// la partie d'office, une victoire devant sinon être annoncée
+	win := CheckWinVariant(g.Board, g.Options.Variant)
+	if g.Options.CallQuarto {
+		win = len(g.AvailablePieces) == 0 && completesWin(g.Board, position, g.Options.Variant)
+	}
 
-	// Vérifier les conditions de victoire
-	if CheckWin(g.Board) {
+	if win {
 		g.Status = StatusFinished
 		g.Winner = g.CurrentTurn
 	} else if len(g.AvailablePieces) == 0 {
@@ -104,12 +168,7 @@ func (g *Game) PlacePiece(position Position) (err error) {
 		g.GamePhase = GamePhaseSelectPiece
 	}
 
-	err = UpdateGame(*g)
-	if err != nil {
-		return fmt.Errorf("erreur lors de la mise à jour du jeu: %v", err)
-	}
-
-	return
+	return nil
 }
 
 // GetGame récupère une partie et vérifie les droits d'accès
@@ -121,13 +180,19 @@ func GetGame(gameID string, userID int64) (g Game, err error) {
 
 	// Vérifier que l'utilisateur fait partie de cette partie
 	if g.Player1ID != userID && g.Player2ID != userID {
-		err = fmt.Errorf("vous n'avez pas accès à cette partie")
+		err = ErrGameForbidden
 		return
 	}
 
 	return g, nil
 }
 
+// clearPendingRequests annule les propositions de nulle et demandes d'annulation en attente
+func (g *Game) clearPendingRequests() {
+	g.DrawOfferedBy = 0
+	g.TakebackRequestedBy = 0
+}
+
 // switchTurn
 func (g *Game) switchTurn() {
 	switch g.CurrentTurn {
@@ -143,7 +208,7 @@ func (g *Game) ForfeitGame(userID int64) (err error) {
 
 	// Vérifier que la partie est active
 	if g.Status != StatusPlaying {
-		return fmt.Errorf("cette partie n'est plus active")
+		return ErrGameNotActive
 	}
 
 	// Déterminer le gagnant (l'autre joueur)
@@ -157,6 +222,7 @@ func (g *Game) ForfeitGame(userID int64) (err error) {
 	// Mettre à jour la partie
 	g.Status = StatusFinished
 	g.Winner = winner
+	g.clearPendingRequests()
 	g.UpdatedAt = time.Now()
 
 	err = UpdateGame(*g)
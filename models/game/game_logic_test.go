@@ -1,6 +1,7 @@
 package game
 
 import (
+	"errors"
 	"testing"
 )
 
@@ -404,6 +405,12 @@ func TestHasCommonCharacteristic(t *testing.T) {
 			expected: false,
 			reason:   "no common characteristics",
 		},
+		{
+			name:     "Empty first square",
+			pieces:   []Piece{PieceEmpty, PieceWhiteSquareLargeEmpty, PieceWhiteCircleLargeFilled, PieceWhiteCircleLargeEmpty},
+			expected: false,
+			reason:   "a line with an empty square is never complete",
+		},
 		{
 			name:     "Wrong number of pieces (too few)",
 			pieces:   []Piece{PieceWhiteSquareLargeFilled, PieceWhiteSquareLargeEmpty, PieceWhiteCircleLargeFilled},
@@ -495,6 +502,36 @@ func TestIsValidPiece(t *testing.T) {
 	}
 }
 
+func TestParseMoveNotation(t *testing.T) {
+	piece, position, err := ParseMoveNotation("NRPT-b3")
+	if err != nil || piece != 15 || position != "b3" {
+		t.Fatalf("ParseMoveNotation(NRPT-b3) = %d, %s, %v", piece, position, err)
+	}
+	if row, col, err := PositionToCoords(position); err != nil || row != 2 || col != 1 {
+		t.Errorf("PositionToCoords(b3) = %d, %d, %v", row, col, err)
+	}
+
+	tests := []struct {
+		notation string
+		expected error
+	}{
+		{"NRPT", ErrInvalidMove},
+		{"NRPT-b3-c4", ErrInvalidMove},
+		{"NRP-b3", ErrInvalidPiece},
+		{"XRPT-b3", ErrInvalidPiece},
+	}
+	for _, tt := range tests {
+		if _, _, err := ParseMoveNotation(tt.notation); !errors.Is(err, tt.expected) {
+			t.Errorf("ParseMoveNotation(%s) = %v, expected %v", tt.notation, err, tt.expected)
+		}
+	}
+	for _, position := range []string{"", "e1", "a5", "a10"} {
+		if _, _, err := PositionToCoords(position); !errors.Is(err, ErrInvalidPosition) {
+			t.Errorf("PositionToCoords(%q) = %v, expected %v", position, err, ErrInvalidPosition)
+		}
+	}
+}
+
 func TestGetEmptyBoard(t *testing.T) {
 	board := GetEmptyBoard()
 
@@ -537,3 +574,35 @@ func TestGetAllPieces(t *testing.T) {
 		}
 	}
 }
+
+func TestCheckWinVariant(t *testing.T) {
+	// Carré 2x2 de pièces blanches en b2-c3, sans ligne gagnante
+	square := GetEmptyBoard()
+	square[1][1], square[1][2], square[2][1], square[2][2] = 0, 1, 2, 3
+
+	// Même carré à cheval sur les bords (a1, d1, a4, d4)
+	corners := GetEmptyBoard()
+	corners[0][0], corners[0][3], corners[3][0], corners[3][3] = 0, 1, 2, 3
+
+	tests := []struct {
+		name     string
+		board    [4][4]Piece
+		variant  string
+		expected bool
+	}{
+		{"Square ignored in standard", square, VariantStandard, false},
+		{"Square ignored in legacy games", square, "", false},
+		{"Square wins in squares", square, VariantSquares, true},
+		{"Square wins in toroidal squares", square, VariantSquaresTorus, true},
+		{"Wrapped square ignored in squares", corners, VariantSquares, false},
+		{"Wrapped square wins in toroidal squares", corners, VariantSquaresTorus, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := CheckWinVariant(tt.board, tt.variant); result != tt.expected {
+				t.Errorf("CheckWinVariant(%s) = %v, expected %v", tt.variant, result, tt.expected)
+			}
+		})
+	}
+}
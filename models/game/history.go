@@ -0,0 +1,78 @@
+package game
+
+import "time"
+
+// newMoveEvent crée une entrée d'historique pour le joueur dont c'est le tour
+func (g *Game) newMoveEvent(eventType string, piece Piece, position *Position, at time.Time) MoveEvent {
+	event := MoveEvent{
+		Type:      eventType,
+		Actor:     g.CurrentTurn,
+		Piece:     piece,
+		Position:  position,
+		Timestamp: at,
+	}
+
+	// Le temps de réflexion court depuis l'action précédente (ou le début de la partie)
+	since := g.CreatedAt
+	if len(g.History) > 0 {
+		since = g.History[len(g.History)-1].Timestamp
+	}
+	if !since.IsZero() && at.After(since) {
+		event.ThinkingMs = at.Sub(since).Milliseconds()
+	}
+
+	return event
+}
+
+// Placements retourne les placements de l'historique, dans l'ordre
+func (g Game) Placements() []Move {
+	moves := make([]Move, 0, len(g.History)/2)
+	for _, event := range g.History {
+		if event.Type == MoveEventPlace && event.Position != nil {
+			moves = append(moves, Move{Piece: event.Piece, Position: *event.Position})
+		}
+	}
+	return moves
+}
+
+// lastPlacementIndex retourne l'index du dernier placement de l'historique (-1 si aucun)
+func (g Game) lastPlacementIndex() int {
+	for i := len(g.History) - 1; i >= 0; i-- {
+		if g.History[i].Type == MoveEventPlace {
+			return i
+		}
+	}
+	return -1
+}
+
+// completeLegacyHistory complète l'historique des parties enregistrées avant le journal des sélections :
+// les auteurs sont déduits de l'alternance (le joueur 1 sélectionne en premier, celui qui place sélectionne ensuite)
+// et la sélection en attente de placement est ajoutée
+func (g *Game) completeLegacyHistory() {
+	selector := g.Player1ID
+	for i := range g.History {
+		event := &g.History[i]
+		switch event.Type {
+		case MoveEventSelect:
+			if event.Actor == 0 {
+				event.Actor = selector
+			}
+			selector = g.opponentOf(event.Actor)
+		case MoveEventPlace:
+			if event.Actor == 0 {
+				event.Actor = selector
+			}
+			selector = event.Actor
+		}
+	}
+
+	// Dans l'ancien format, la pièce sélectionnée et pas encore placée n'apparaissait pas
+	lastIsSelection := len(g.History) > 0 && g.History[len(g.History)-1].Type == MoveEventSelect
+	if g.GamePhase == GamePhasePlacePiece && g.SelectedPiece != PieceEmpty && !lastIsSelection {
+		g.History = append(g.History, MoveEvent{
+			Type:  MoveEventSelect,
+			Actor: g.opponentOf(g.CurrentTurn),
+			Piece: g.SelectedPiece,
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeserializeLegacyHistory(t *testing.T) {
+	// Ancien format : placements seuls, la pièce 3 est sélectionnée mais pas encore placée
+	legacy := `[{"piece":5,"position":{"row":0,"col":0}},{"piece":9,"position":{"row":1,"col":2}}]`
+
+	history, err := deserializeHistoryFromJSON(legacy)
+	if err != nil {
+		t.Fatalf("désérialisation: %v", err)
+	}
+
+	g := InitializeGame(1, 2)
+	g.History = history
+	g.GamePhase = GamePhasePlacePiece
+	g.SelectedPiece = 3
+	g.CurrentTurn = 2
+	g.completeLegacyHistory()
+
+	expected := []struct {
+		eventType string
+		actor     int64
+		piece     Piece
+	}{
+		{MoveEventSelect, 1, 5},
+		{MoveEventPlace, 2, 5},
+		{MoveEventSelect, 2, 9},
+		{MoveEventPlace, 1, 9},
+		{MoveEventSelect, 1, 3},
+	}
+
+	if len(g.History) != len(expected) {
+		t.Fatalf("nombre d'événements = %d, attendu %d: %+v", len(g.History), len(expected), g.History)
+	}
+	for i, e := range expected {
+		event := g.History[i]
+		if event.Type != e.eventType || event.Actor != e.actor || event.Piece != e.piece {
+			t.Errorf("événement %d = %s/%d/%d, attendu %s/%d/%d", i, event.Type, event.Actor, event.Piece, e.eventType, e.actor, e.piece)
+		}
+	}
+
+	if placements := g.Placements(); len(placements) != 2 || placements[1].Position != (Position{Row: 1, Col: 2}) {
+		t.Errorf("placements inattendus: %+v", placements)
+	}
+
+	// Le nouveau format est relu à l'identique
+	serialized, err := serializeHistoryToJSON(g.History)
+	if err != nil {
+		t.Fatalf("sérialisation: %v", err)
+	}
+	reread, err := deserializeHistoryFromJSON(serialized)
+	if err != nil || len(reread) != len(g.History) {
+		t.Fatalf("relecture: %d événements, erreur %v", len(reread), err)
+	}
+}
+
+func TestMoveEventThinkingTime(t *testing.T) {
+	g := InitializeGame(1, 2)
+	start := g.CreatedAt
+
+	if err := g.applySelection(4, start.Add(3*time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.applyPlacement(Position{Row: 2, Col: 2}, start.Add(5*time.Second)); err != nil {
+		t.Fatal(err)
+	}
+
+	if g.History[0].Actor != 1 || g.History[0].ThinkingMs != 3000 {
+		t.Errorf("sélection: acteur %d, réflexion %dms", g.History[0].Actor, g.History[0].ThinkingMs)
+	}
+	if g.History[1].Actor != 2 || g.History[1].ThinkingMs != 2000 {
+		t.Errorf("placement: acteur %d, réflexion %dms", g.History[1].Actor, g.History[1].ThinkingMs)
+	}
+}
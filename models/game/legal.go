@@ -0,0 +1,96 @@
+package game
+
+import (
+	"errors"
+	"quarto/models/apperror"
+	"slices"
+	"time"
+)
+
+// LegalMoves retourne les coups légaux de la phase en cours : pièces à donner ou cases où placer la pièce sélectionnée
+func (g Game) LegalMoves() LegalMovesResponse {
+	response := LegalMovesResponse{
+		GameID:      g.ID,
+		GamePhase:   g.GamePhase,
+		CurrentTurn: g.CurrentTurn,
+		Pieces:      []Piece{},
+		Positions:   []string{},
+	}
+	if g.Status != StatusPlaying {
+		return response
+	}
+
+	switch g.GamePhase {
+	case GamePhaseSelectPiece:
+		for _, move := range GetValidMoves(g.GamePhase, g.Board, g.AvailablePieces) {
+			response.Pieces = append(response.Pieces, move.Piece)
+		}
+	case GamePhasePlacePiece:
+		for _, move := range GetValidMoves(g.GamePhase, g.Board, []Piece{g.SelectedPiece}) {
+			response.Positions = append(response.Positions, CoordsToPosition(move.Position.Row, move.Position.Col))
+		}
+	}
+
+	return response
+}
+
+// CheckSelection vérifie la sélection d'une pièce sans l'enregistrer
+func (g Game) CheckSelection(userID int64, piece Piece) MoveCheck {
+	clone := g.clone()
+	err := clone.canPlay(userID)
+	if err == nil {
+		err = clone.applySelection(piece, time.Now())
+	}
+	return newMoveCheck(err, false)
+}
+
+// CheckPlacement vérifie le placement de la pièce sélectionnée sans l'enregistrer, et indique s'il gagnerait
+func (g Game) CheckPlacement(userID int64, position Position) MoveCheck {
+	clone := g.clone()
+	err := clone.canPlay(userID)
+	if err == nil {
+		err = clone.applyPlacement(position, time.Now())
+	}
+	return newMoveCheck(err, err == nil && completesWin(clone.Board, position, clone.Options.Variant))
+}
+
+// canPlay vérifie que la partie est en cours et que c'est au joueur de jouer
+func (g Game) canPlay(userID int64) error {
+	if g.Status != StatusPlaying {
+		return ErrGameNotActive
+	}
+	if g.CurrentTurn != userID {
+		return ErrNotYourTurn.With("player", userID)
+	}
+	return nil
+}
+
+// clone copie la partie pour y appliquer un coup sans modifier l'originale
+func (g Game) clone() Game {
+	g.AvailablePieces = slices.Clone(g.AvailablePieces)
+	g.History = slices.Clone(g.History)
+	return g
+}
+
+// newMoveCheck construit le résultat d'une vérification de coup
+func newMoveCheck(err error, wins bool) MoveCheck {
+	if err == nil {
+		return MoveCheck{Legal: true, Wins: wins}
+	}
+
+	check := MoveCheck{Reason: err.Error(), err: err}
+	var appErr *apperror.Error
+	if errors.As(err, &appErr) {
+		check.Code = appErr.Code
+	}
+	return check
+}
+
+// Localized retourne le résultat avec la raison traduite dans la langue demandée
+func (m MoveCheck) Localized(lang string) MoveCheck {
+	var appErr *apperror.Error
+	if errors.As(m.err, &appErr) {
+		m.Reason = appErr.Message(lang)
+	}
+	return m
+}
@@ -0,0 +1,92 @@
+package game
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestLegalMoves(t *testing.T) {
+	g := InitializeGame(1, 2)
+	if moves := g.LegalMoves(); len(moves.Pieces) != 16 || len(moves.Positions) != 0 {
+		t.Errorf("sélection initiale: %d pièces, %d cases", len(moves.Pieces), len(moves.Positions))
+	}
+
+	playSelect(t, &g, 3)
+	playPlace(t, &g, Position{Row: 0, Col: 0})
+	playSelect(t, &g, 7)
+	moves := g.LegalMoves()
+	if len(moves.Pieces) != 0 || len(moves.Positions) != 15 || slices.Contains(moves.Positions, "a1") {
+		t.Errorf("placement: pièces=%v cases=%v", moves.Pieces, moves.Positions)
+	}
+
+	g.Status = StatusFinished
+	if moves := g.LegalMoves(); len(moves.Pieces) != 0 || len(moves.Positions) != 0 {
+		t.Errorf("aucun coup attendu sur une partie terminée: %+v", moves)
+	}
+}
+
+func TestCheckMove(t *testing.T) {
+	g := InitializeGame(1, 2)
+
+	if check := g.CheckSelection(2, 0); check.Legal || check.Code != ErrNotYourTurn.Code {
+		t.Errorf("sélection hors tour: %+v", check)
+	}
+	if check := g.CheckSelection(1, 0); !check.Legal || check.Wins {
+		t.Errorf("sélection légale: %+v", check)
+	}
+	if g.GamePhase != GamePhaseSelectPiece || len(g.History) != 0 || len(g.AvailablePieces) != 16 {
+		t.Fatal("la vérification ne doit pas modifier la partie")
+	}
+
+	// Trois pièces blanches alignées : la quatrième forme un Quarto
+	for col, piece := range []Piece{0, 1, 2} {
+		playSelect(t, &g, piece)
+		playPlace(t, &g, Position{Row: 0, Col: col})
+	}
+	playSelect(t, &g, 3)
+
+	if check := g.CheckPlacement(g.CurrentTurn, Position{Row: 0, Col: 3}); !check.Legal || !check.Wins {
+		t.Errorf("placement gagnant: %+v", check)
+	}
+	if check := g.CheckPlacement(g.CurrentTurn, Position{Row: 1, Col: 3}); !check.Legal || check.Wins {
+		t.Errorf("placement neutre: %+v", check)
+	}
+	if check := g.CheckPlacement(g.CurrentTurn, Position{Row: 0, Col: 0}); check.Legal || check.Code != ErrSquareOccupied.Code {
+		t.Errorf("case occupée: %+v", check)
+	}
+	if g.Status != StatusPlaying || g.Board[0][3] != PieceEmpty {
+		t.Error("la vérification ne doit pas modifier la partie")
+	}
+
+	if err := g.applyPlacement(Position{Row: 0, Col: 3}, g.UpdatedAt); err != nil {
+		t.Fatal(err)
+	}
+	if check := g.CheckSelection(g.CurrentTurn, 4); check.Legal || check.Code != ErrGameNotActive.Code {
+		t.Errorf("coup sur une partie terminée: %+v", check)
+	}
+}
+
+func TestMoveOutOfTurn(t *testing.T) {
+	g := InitializeGame(1, 2)
+
+	// Le joueur 2 ne peut ni sélectionner à la place du joueur 1, ni placer la pièce qui lui est donnée avant son tour
+	if err := g.SelectPiece(2, 0); !errors.Is(err, ErrNotYourTurn) {
+		t.Errorf("sélection hors tour: %v", err)
+	}
+	playSelect(t, &g, 0)
+	if err := g.PlacePiece(1, Position{Row: 0, Col: 0}); !errors.Is(err, ErrNotYourTurn) {
+		t.Errorf("placement hors tour: %v", err)
+	}
+	if err := g.PlacePiece(3, Position{Row: 0, Col: 0}); !errors.Is(err, ErrNotYourTurn) {
+		t.Errorf("placement d'un joueur extérieur: %v", err)
+	}
+	if g.Board[0][0] != PieceEmpty || len(g.History) != 1 || g.CurrentTurn != 2 {
+		t.Fatalf("un coup refusé ne doit pas modifier la partie: %+v", g)
+	}
+
+	g.Status = StatusFinished
+	if err := g.PlacePiece(2, Position{Row: 0, Col: 0}); !errors.Is(err, ErrGameNotActive) {
+		t.Errorf("placement sur une partie terminée: %v", err)
+	}
+}
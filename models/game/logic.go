@@ -45,7 +45,7 @@ func checkDiagonals(board [4][4]Piece) bool {
 
 // hasCommonCharacteristic vérifie si 4 pièces ont au moins une caractéristique commune
 func hasCommonCharacteristic(pieces []Piece) bool {
-	if len(pieces) != 4 {
+	if len(pieces) != 4 || pieces[0] == PieceEmpty {
 		return false
 	}
 	color, shape, size, fill := GetPieceCharacteristics(pieces[0])
@@ -104,9 +104,10 @@ func InitializeGame(player1ID, player2ID int64) Game {
 		GamePhase:       GamePhaseSelectPiece,
 		Board:           GetEmptyBoard(),
 		AvailablePieces: availablePieces,
+		SelectedPiece:   PieceEmpty,
 		Status:          StatusPlaying,
 		Winner:          0,
-		History:         []Move{},
+		History:         []MoveEvent{},
 		CreatedAt:       time.Now(),
 		UpdatedAt:       time.Now(),
 	}
@@ -54,7 +54,7 @@ func CoordsToPosition(row, col int) string {
 // PositionToCoords convertit une position algébrique en coordonnées
 func PositionToCoords(position string) (int, int, error) {
 	if len(position) != 2 {
-		return 0, 0, fmt.Errorf("position invalide: %s", position)
+		return 0, 0, ErrInvalidPosition.With("position", position)
 	}
 
 	file := position[0]
@@ -65,11 +65,11 @@ func PositionToCoords(position string) (int, int, error) {
 		file = file + 32 // Convertir en minuscule
 	}
 	if file < 'a' || file > 'd' {
-		return 0, 0, fmt.Errorf("colonne invalide: %c", file)
+		return 0, 0, ErrInvalidPosition.With("position", position)
 	}
 
 	if rank < '1' || rank > '4' {
-		return 0, 0, fmt.Errorf("ligne invalide: %c", rank)
+		return 0, 0, ErrInvalidPosition.With("position", position)
 	}
 
 	col := int(file - 'a')
@@ -88,27 +88,25 @@ func CreateMoveNotation(piece Piece, position string) string {
 func ParseMoveNotation(notation string) (Piece, string, error) {
 	parts := strings.Split(notation, "-")
 	if len(parts) != 2 {
-		return 0, "", fmt.Errorf("notation invalide: %s", notation)
+		return 0, "", ErrInvalidMove.With("move", notation)
 	}
 
 	pieceNotation := parts[0]
 	position := parts[1]
 
-	if len(pieceNotation) != 4 {
-		return 0, "", fmt.Errorf("notation de pièce invalide: %s", pieceNotation)
-	}
-
 	piece, err := NotationToPiece(pieceNotation)
 
 	return piece, position, err
 }
 
+// NotationToPiece convertit une pièce en notation algébrique (BCGP), éventuellement suivie de sa position
 func NotationToPiece(notation string) (Piece, error) {
 	parts := strings.Split(notation, "-")
 	pieceNotation := parts[0]
 
-	if len(pieceNotation) != 4 {
-		return 0, fmt.Errorf("notation de pièce invalide: %s", pieceNotation)
+	if len(pieceNotation) != 4 || !strings.ContainsRune("BN", rune(pieceNotation[0])) || !strings.ContainsRune("CR", rune(pieceNotation[1])) ||
+		!strings.ContainsRune("GP", rune(pieceNotation[2])) || !strings.ContainsRune("PT", rune(pieceNotation[3])) {
+		return 0, ErrInvalidPiece.With("piece", pieceNotation)
 	}
 
 	color := 0
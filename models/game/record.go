@@ -0,0 +1,417 @@
+package game
+
+import (
+	"fmt"
+	"quarto/models/apperror"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Résultats d'une partie dans un enregistrement
+const (
+	ResultPlayer1 = "1-0"
+	ResultPlayer2 = "0-1"
+	ResultDraw    = "1/2-1/2"
+	ResultOngoing = "*"
+)
+
+// ClaimToken est le jeton d'une annonce de Quarto dans la liste des coups, après le placement ou la sélection
+// qui la précède
+const ClaimToken = "Quarto"
+
+// Balises standard d'un enregistrement, écrites dans cet ordre avant les balises libres
+var recordTagOrder = []string{"Event", "Site", "Date", "Player1", "Player2", "Result", "TimeControl", "Variant", "CallQuarto", "Rated", "GameId"}
+
+type (
+	// Record représente une partie au format texte portable (équivalent du PGN pour Quarto)
+	Record struct {
+		Tags   map[string]string `json:"tags"`
+		Moves  []RecordMove      `json:"moves"`
+		Result string            `json:"result"`
+	}
+
+	// RecordMove représente un tour : la pièce donnée à l'adversaire puis son placement (absent si pas encore joué)
+	RecordMove struct {
+		Piece    Piece     `json:"piece"`
+		Position *Position `json:"position,omitempty"`
+		Claim    bool      `json:"claim,omitempty"` // Quarto annoncé après le dernier élément du tour, par le joueur qui a reçu la pièce
+	}
+
+	// ImportedRecord représente un enregistrement importé pour analyse, rejoué entre les joueurs 1 et 2
+	ImportedRecord struct {
+		Record Record `json:"record"`
+		Plies  []Ply  `json:"plies"`
+	}
+)
+
+// NewRecord construit l'enregistrement d'une partie
+func NewRecord(g Game, player1Name, player2Name string) Record {
+	record := Record{
+		Tags: map[string]string{
+			"Event":       "Quarto",
+			"Site":        "quarto",
+			"Date":        g.CreatedAt.Format("2006.01.02"),
+			"Player1":     player1Name,
+			"Player2":     player2Name,
+			"TimeControl": g.Options.TimeControl.String(),
+			"Variant":     g.Options.VariantOrDefault(),
+			"Rated":       strconv.FormatBool(g.Options.Rated),
+			"GameId":      g.ID,
+		},
+		Moves:  make([]RecordMove, 0, len(g.History)/2+1),
+		Result: g.Result(),
+	}
+	record.Tags["Result"] = record.Result
+	if g.Options.CallQuarto {
+		record.Tags["CallQuarto"] = "true"
+	}
+
+	for _, event := range g.History {
+		switch event.Type {
+		case MoveEventSelect:
+			record.Moves = append(record.Moves, RecordMove{Piece: event.Piece})
+		case MoveEventPlace:
+			if len(record.Moves) > 0 && event.Position != nil {
+				position := *event.Position
+				record.Moves[len(record.Moves)-1].Position = &position
+			}
+		case MoveEventClaim:
+			if len(record.Moves) > 0 {
+				record.Moves[len(record.Moves)-1].Claim = true
+			}
+		}
+	}
+
+	return record
+}
+
+// Result retourne le résultat de la partie au format des enregistrements
+func (g Game) Result() string {
+	if g.Status != StatusFinished {
+		return ResultOngoing
+	}
+	switch g.Winner {
+	case g.Player1ID:
+		return ResultPlayer1
+	case g.Player2ID:
+		return ResultPlayer2
+	default:
+		return ResultDraw
+	}
+}
+
+// String retourne la cadence au format "initial+incrément" en secondes ("-" sans limite)
+func (tc TimeControl) String() string {
+	if tc.InitialSeconds == 0 && tc.IncrementSeconds == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d+%d", tc.InitialSeconds, tc.IncrementSeconds)
+}
+
+// Events convertit les coups de l'enregistrement en historique (le joueur 1 sélectionne en premier)
+func (r Record) Events(player1ID, player2ID int64) []MoveEvent {
+	events := make([]MoveEvent, 0, len(r.Moves)*2)
+	selector, placer := player1ID, player2ID
+	for _, move := range r.Moves {
+		events = append(events, MoveEvent{Type: MoveEventSelect, Actor: selector, Piece: move.Piece})
+		if move.Position != nil {
+			position := *move.Position
+			events = append(events, MoveEvent{Type: MoveEventPlace, Actor: placer, Piece: move.Piece, Position: &position})
+		}
+		// Qu'elle suive le placement ou la sélection, l'annonce revient au joueur qui a reçu la pièce ; son
+		// issue est constatée au rejeu
+		if move.Claim {
+			events = append(events, MoveEvent{Type: MoveEventClaim, Actor: placer})
+		}
+		if move.Position != nil {
+			selector, placer = placer, selector
+		}
+	}
+	return events
+}
+
+// ImportRecord rejoue les coups d'un enregistrement analysé, sans créer de partie
+func ImportRecord(record Record) (ImportedRecord, error) {
+	const player1, player2 = 1, 2
+	options := GameOptions{Variant: record.Tags["Variant"], CallQuarto: record.Tags["CallQuarto"] == "true"}
+	plies, err := Replay(player1, player2, options, record.Events(player1, player2))
+	if err != nil {
+		return ImportedRecord{}, err
+	}
+
+	// Un résultat atteint sur le plateau doit correspondre au résultat annoncé ; un abandon ou une nulle
+	// par accord n'est pas vérifiable et reste libre
+	final := plies[len(plies)-1]
+	if final.Status == StatusFinished {
+		g := Game{Player1ID: player1, Player2ID: player2, Status: final.Status, Winner: final.Winner}
+		if g.Result() != record.Result {
+			return ImportedRecord{}, ErrResultMismatch.With("result", record.Result).With("expected", g.Result())
+		}
+	}
+
+	return ImportedRecord{Record: record, Plies: plies}, nil
+}
+
+// Format sérialise l'enregistrement
+func (r Record) Format() string {
+	var b strings.Builder
+
+	keys := make([]string, 0, len(r.Tags))
+	for key := range r.Tags {
+		if !slices.Contains(recordTagOrder, key) {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	for _, key := range append(slices.Clone(recordTagOrder), keys...) {
+		if value, ok := r.Tags[key]; ok {
+			fmt.Fprintf(&b, "[%s \"%s\"]\n", key, escapeTagValue(value))
+		}
+	}
+	b.WriteString("\n")
+
+	tokens := make([]string, 0, len(r.Moves)*3+1)
+	for i, move := range r.Moves {
+		tokens = append(tokens, strconv.Itoa(i+1)+".", PieceToNotation(move.Piece))
+		if move.Position != nil {
+			tokens = append(tokens, CreateMoveNotation(move.Piece, CoordsToPosition(move.Position.Row, move.Position.Col)))
+		}
+		if move.Claim {
+			tokens = append(tokens, ClaimToken)
+		}
+	}
+	result := r.Result
+	if result == "" {
+		result = ResultOngoing
+	}
+	tokens = append(tokens, result)
+
+	// Couper les lignes à 80 caractères comme en PGN
+	line := 0
+	for i, token := range tokens {
+		if i > 0 {
+			if line+1+len(token) > 80 {
+				b.WriteString("\n")
+				line = 0
+			} else {
+				b.WriteString(" ")
+				line++
+			}
+		}
+		b.WriteString(token)
+		line += len(token)
+	}
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+// ParseRecord analyse un enregistrement ; la légalité des coups est vérifiée séparément par Replay
+func ParseRecord(text string) (Record, error) {
+	record, err := parseRecord(text)
+	if err != nil {
+		return Record{}, invalidBecause(ErrInvalidRecord, err)
+	}
+	return record, nil
+}
+
+// parseRecord analyse un enregistrement, les erreurs décrivant l'élément fautif
+func parseRecord(text string) (Record, error) {
+	record := Record{Tags: make(map[string]string)}
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+
+	// Balises d'en-tête
+	i := 0
+	for ; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "[") {
+			break
+		}
+		key, value, err := parseTag(line)
+		if err != nil {
+			return Record{}, err.With("line", i+1)
+		}
+		if _, exists := record.Tags[key]; exists {
+			return Record{}, errRecordTagDuplicate.With("line", i+1).With("tag", key)
+		}
+		record.Tags[key] = value
+	}
+
+	// Liste des coups
+	movetext, err := stripComments(strings.Join(lines[i:], " "))
+	if err != nil {
+		return Record{}, err
+	}
+
+	for _, token := range strings.Fields(movetext) {
+		if record.Result != "" {
+			return Record{}, errRecordAfterResult.With("token", token)
+		}
+
+		switch {
+		case isResult(token):
+			record.Result = token
+		case token == ClaimToken:
+			if len(record.Moves) == 0 || record.Moves[len(record.Moves)-1].Claim {
+				return Record{}, errRecordClaim
+			}
+			record.Moves[len(record.Moves)-1].Claim = true
+		case strings.HasSuffix(token, "."):
+			number, err := strconv.Atoi(strings.TrimSuffix(token, "."))
+			if err != nil || number != len(record.Moves)+1 {
+				return Record{}, errRecordMoveNumber.With("token", token)
+			}
+			if len(record.Moves) > 0 && record.Moves[len(record.Moves)-1].Position == nil {
+				return Record{}, errRecordNoPlacement.With("move", len(record.Moves))
+			}
+		case strings.Contains(token, "-"):
+			if len(record.Moves) == 0 || record.Moves[len(record.Moves)-1].Position != nil {
+				return Record{}, errRecordNoPiece.With("token", token)
+			}
+			move := &record.Moves[len(record.Moves)-1]
+			piece, position, err := parsePlacement(token)
+			if err != nil {
+				return Record{}, err
+			}
+			if piece != move.Piece {
+				return Record{}, errRecordWrongPiece.With("move", len(record.Moves)).With("placed", PieceToNotation(piece)).With("piece", PieceToNotation(move.Piece))
+			}
+			move.Position = &position
+		default:
+			piece, err := parsePiece(token)
+			if err != nil {
+				return Record{}, err
+			}
+			if len(record.Moves) > 0 && record.Moves[len(record.Moves)-1].Position == nil {
+				return Record{}, errRecordNoPlacement.With("move", len(record.Moves))
+			}
+			record.Moves = append(record.Moves, RecordMove{Piece: piece})
+		}
+	}
+
+	if record.Result == "" {
+		return Record{}, errRecordNoResult
+	}
+	if !IsValidVariant(record.Tags["Variant"]) {
+		return Record{}, errRecordVariant.With("variant", record.Tags["Variant"])
+	}
+	if tag, ok := record.Tags["Result"]; ok && tag != record.Result {
+		return Record{}, errRecordResultMismatch.With("result", record.Result).With("tag", tag)
+	}
+
+	return record, nil
+}
+
+// parseTag analyse une balise [Clé "Valeur"]
+func parseTag(line string) (string, string, *apperror.Error) {
+	if !strings.HasSuffix(line, "]") {
+		return "", "", errRecordTagUnclosed
+	}
+	content := line[1 : len(line)-1]
+
+	key, rest, found := strings.Cut(content, " ")
+	if !found || key == "" || strings.IndexFunc(key, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' }) >= 0 {
+		return "", "", errRecordTagName
+	}
+
+	rest = strings.TrimSpace(rest)
+	if len(rest) < 2 || rest[0] != '"' || rest[len(rest)-1] != '"' {
+		return "", "", errRecordTagValue
+	}
+
+	var value strings.Builder
+	quoted := rest[1 : len(rest)-1]
+	for i := 0; i < len(quoted); i++ {
+		switch quoted[i] {
+		case '\\':
+			if i+1 >= len(quoted) || (quoted[i+1] != '\\' && quoted[i+1] != '"') {
+				return "", "", errRecordTagEscape.With("tag", key)
+			}
+			i++
+			value.WriteByte(quoted[i])
+		case '"':
+			return "", "", errRecordTagQuote.With("tag", key)
+		case '\n', '\r':
+			return "", "", errRecordTagNewline.With("tag", key)
+		default:
+			value.WriteByte(quoted[i])
+		}
+	}
+
+	return key, value.String(), nil
+}
+
+// escapeTagValue échappe une valeur de balise
+func escapeTagValue(value string) string {
+	value = strings.ReplaceAll(value, "\\", "\\\\")
+	value = strings.ReplaceAll(value, "\"", "\\\"")
+	return strings.NewReplacer("\n", " ", "\r", " ").Replace(value)
+}
+
+// stripComments retire les commentaires {…} de la liste des coups
+func stripComments(movetext string) (string, error) {
+	var b strings.Builder
+	inComment := false
+	for _, r := range movetext {
+		switch {
+		case r == '{' && !inComment:
+			inComment = true
+			b.WriteRune(' ')
+		case r == '}' && inComment:
+			inComment = false
+		case r == '}':
+			return "", errRecordCommentOpen
+		case !inComment:
+			b.WriteRune(r)
+		}
+	}
+	if inComment {
+		return "", errRecordCommentClose
+	}
+	return b.String(), nil
+}
+
+// parsePiece analyse strictement une pièce en notation (BCGP)
+func parsePiece(token string) (Piece, error) {
+	if strings.Contains(token, "-") {
+		return PieceEmpty, errRecordPiece.With("token", token)
+	}
+	piece, err := NotationToPiece(token)
+	if err != nil {
+		return PieceEmpty, errRecordPiece.With("token", token)
+	}
+	return piece, nil
+}
+
+// parsePlacement analyse strictement un placement en notation (BCGP-a1)
+func parsePlacement(token string) (Piece, Position, error) {
+	pieceNotation, positionNotation, _ := strings.Cut(token, "-")
+	piece, err := parsePiece(pieceNotation)
+	if err != nil {
+		return PieceEmpty, Position{}, err
+	}
+
+	if len(positionNotation) != 2 || positionNotation[0] < 'a' || positionNotation[0] > 'd' {
+		return PieceEmpty, Position{}, errRecordPosition.With("token", token)
+	}
+	row, col, err := PositionToCoords(positionNotation)
+	if err != nil {
+		return PieceEmpty, Position{}, errRecordPosition.With("token", token)
+	}
+
+	return piece, Position{Row: row, Col: col}, nil
+}
+
+// isResult indique si le jeton est un résultat de partie
+func isResult(token string) bool {
+	switch token {
+	case ResultPlayer1, ResultPlayer2, ResultDraw, ResultOngoing:
+		return true
+	}
+	return false
+}
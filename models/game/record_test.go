@@ -0,0 +1,139 @@
+package game
+
+import (
+	"errors"
+	"quarto/models/apperror"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sampleRecord = `[Event "Quarto"]
+[Site "quarto"]
+[Date "2025.03.14"]
+[Player1 "alice"]
+[Player2 "bob \"le sage\""]
+[Result "1-0"]
+[TimeControl "300+5"]
+
+1. BCGP BCGP-a1 2. NCPT NCPT-b2 {bien joué} 3. BCPP BCPP-c3
+4. BCGT BCGT-d4 1-0
+`
+
+func TestRecordRoundTrip(t *testing.T) {
+	g := InitializeGame(1, 2)
+	g.ID = "game-1"
+	g.CreatedAt = time.Date(2025, 3, 14, 10, 0, 0, 0, time.UTC)
+	g.Options.TimeControl = TimeControl{InitialSeconds: 300, IncrementSeconds: 5}
+	now := time.Now()
+	for _, step := range []func() error{
+		func() error { return g.applySelection(0, now) },
+		func() error { return g.applyPlacement(Position{Row: 0, Col: 0}, now) },
+		func() error { return g.applySelection(13, now) },
+		func() error { return g.applyPlacement(Position{Row: 3, Col: 2}, now) },
+		func() error { return g.applySelection(6, now) },
+	} {
+		if err := step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	record := NewRecord(g, "alice", "bob")
+	text := record.Format()
+
+	parsed, err := ParseRecord(text)
+	if err != nil {
+		t.Fatalf("relecture de l'enregistrement: %v\n%s", err, text)
+	}
+	if !reflect.DeepEqual(parsed, record) {
+		t.Fatalf("aller-retour différent:\n%+v\n%+v", parsed, record)
+	}
+
+	// Les coups relus reproduisent l'historique de la partie
+	events := parsed.Events(1, 2)
+	plies, err := Replay(1, 2, GameOptions{}, events)
+	if err != nil {
+		t.Fatalf("rejeu de l'enregistrement: %v", err)
+	}
+	if last := plies[len(plies)-1]; last.Board != g.Board || last.SelectedPiece != 6 || last.CurrentTurn != g.CurrentTurn {
+		t.Errorf("position finale inattendue: %+v", last)
+	}
+}
+
+func TestParseRecord(t *testing.T) {
+	record, err := ParseRecord(sampleRecord)
+	if err != nil {
+		t.Fatalf("analyse: %v", err)
+	}
+
+	if record.Tags["Player2"] != `bob "le sage"` || record.Result != ResultPlayer1 || len(record.Moves) != 4 {
+		t.Errorf("enregistrement inattendu: %+v", record)
+	}
+	imported, err := ImportRecord(record)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if len(imported.Plies) != 9 {
+		t.Errorf("%d positions rejouées, attendu 9", len(imported.Plies))
+	}
+	if contradicted, err := ParseRecord(strings.Replace(sampleRecord, "1-0", "0-1", 2)); err != nil {
+		t.Errorf("analyse: %v", err)
+	} else if _, err := ImportRecord(contradicted); err == nil {
+		t.Error("un résultat contredit par la position finale devrait être refusé")
+	}
+
+	invalid := map[string]*apperror.Error{
+		"1. BCGP BCGP-a1":                 errRecordNoResult,
+		"1. BCGP NRPT-a1 *":               errRecordWrongPiece,
+		"1. BCGP 2. NRPT NRPT-b2 *":       errRecordNoPlacement,
+		"2. BCGP BCGP-a1 *":               errRecordMoveNumber,
+		"1. BCGP BCGP-e5 *":               errRecordPosition,
+		"1. XXXX *":                       errRecordPiece,
+		"[Result \"0-1\"]\n\n1-0":         errRecordResultMismatch,
+		"1. BCGP {oups *":                 errRecordCommentClose,
+		"* 1. BCGP":                       errRecordAfterResult,
+		"[Event \"a\"]\n[Event \"b\"]\n*": errRecordTagDuplicate,
+		"[Event a]\n*":                    errRecordTagValue,
+		"[Variant \"hexagons\"]\n\n*":     errRecordVariant,
+		"1. BCGP Quarto Quarto *":         errRecordClaim,
+	}
+	for text, reason := range invalid {
+		_, err := ParseRecord(text)
+		var appErr *apperror.Error
+		if !errors.As(err, &appErr) || !errors.Is(appErr, ErrInvalidRecord) || appErr.Details["reason"] != reason.Code {
+			t.Errorf("%q: erreur %v, motif %s attendu", text, err, reason.Code)
+			continue
+		}
+		if message := appErr.Message(apperror.LangEN); strings.Contains(message, "{") {
+			t.Errorf("%q: message anglais incomplet %q", text, message)
+		}
+	}
+}
+
+func FuzzParseRecord(f *testing.F) {
+	f.Add(sampleRecord)
+	f.Add("*")
+	f.Add("[Event \"x\"]\n\n1. BCGP BCGP-a1 2. NNPT *")
+	f.Add("1. NRPT NRPT-d4 {commentaire} 1/2-1/2")
+
+	f.Fuzz(func(t *testing.T, text string) {
+		record, err := ParseRecord(text)
+		if err != nil {
+			return
+		}
+
+		// Tout enregistrement accepté doit survivre à un aller-retour
+		formatted := record.Format()
+		reparsed, err := ParseRecord(formatted)
+		if err != nil {
+			t.Fatalf("enregistrement reformaté refusé: %v\n%s", err, formatted)
+		}
+		if !reflect.DeepEqual(record, reparsed) {
+			t.Fatalf("aller-retour différent:\n%+v\n%+v", record, reparsed)
+		}
+		if strings.Count(formatted, "\n") == 0 {
+			t.Fatalf("enregistrement formaté sans fin de ligne")
+		}
+	})
+}
@@ -0,0 +1,138 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"quarto/models/apperror"
+	"slices"
+)
+
+// ReplayError signale le premier demi-coup illégal d'un historique corrompu
+type ReplayError struct {
+	Ply   int
+	Event MoveEvent
+	Err   error
+}
+
+func (e *ReplayError) Error() string {
+	return fmt.Sprintf("historique invalide au demi-coup %d (%s %s): %v", e.Ply, e.Event.Type, PieceToNotation(e.Event.Piece), e.Err)
+}
+
+func (e *ReplayError) Unwrap() error {
+	return e.Err
+}
+
+// As expose l'historique corrompu comme erreur métier, avec le code de sa cause dans les détails
+func (e *ReplayError) As(target any) bool {
+	appErr, ok := target.(**apperror.Error)
+	if !ok {
+		return false
+	}
+
+	*appErr = ErrIllegalHistory.With("ply", e.Ply)
+	var cause *apperror.Error
+	if errors.As(e.Err, &cause) {
+		*appErr = (*appErr).With("cause", cause.Code)
+	}
+	return true
+}
+
+// Replay rejoue un historique depuis la position initiale et retourne la position après chaque demi-coup.
+// La fonction est pure : elle n'accède pas à la base et ne modifie pas l'historique reçu.
+func Replay(player1ID, player2ID int64, options GameOptions, history []MoveEvent) ([]Ply, error) {
+	g := InitializeGame(player1ID, player2ID)
+	g.Options = options
+	plies := make([]Ply, 0, len(history)+1)
+	plies = append(plies, g.snapshot(0, nil))
+
+	for i, event := range history {
+		ply := i + 1
+		if err := g.replayEvent(event); err != nil {
+			return plies, &ReplayError{Ply: ply, Event: event, Err: err}
+		}
+
+		// L'issue d'une annonce est celle constatée sur le plateau, y compris pour un enregistrement importé
+		if event.Type == MoveEventClaim {
+			event.Accepted = g.Winner == event.Actor
+			plies = append(plies, g.snapshot(ply, &event))
+			continue
+		}
+		plies = append(plies, g.snapshot(ply, &history[i]))
+	}
+
+	return plies, nil
+}
+
+// PositionAt retourne la position d'une partie après le demi-coup demandé
+func PositionAt(g Game, ply int) (*Ply, error) {
+	if ply < 0 || ply > len(g.History) {
+		return nil, ErrInvalidPly.With("ply", ply).With("total", len(g.History))
+	}
+
+	plies, err := Replay(g.Player1ID, g.Player2ID, g.Options, g.History[:ply])
+	if err != nil {
+		return nil, err
+	}
+
+	return &plies[ply], nil
+}
+
+// replayEvent applique un événement de l'historique en vérifiant sa légalité
+func (g *Game) replayEvent(event MoveEvent) error {
+	if g.Status != StatusPlaying {
+		return ErrGameNotActive
+	}
+
+	if event.Actor != 0 && event.Actor != g.CurrentTurn {
+		return ErrNotYourTurn.With("player", event.Actor)
+	}
+
+	switch event.Type {
+	case MoveEventSelect:
+		return g.applySelection(event.Piece, event.Timestamp)
+	case MoveEventPlace:
+		if event.Position == nil {
+			return ErrMissingPosition
+		}
+		if event.Piece != g.SelectedPiece {
+			return ErrWrongPiece
+		}
+		return g.applyPlacement(*event.Position, event.Timestamp)
+	case MoveEventClaim:
+		if !g.Options.CallQuarto {
+			return ErrCallQuartoDisabled
+		}
+		_, err := g.applyClaim(event.Actor, event.Timestamp)
+		return err
+	default:
+		return ErrUnknownMoveType.With("type", event.Type)
+	}
+}
+
+// snapshot capture la position courante
+func (g Game) snapshot(ply int, event *MoveEvent) Ply {
+	snapshot := Ply{
+		Ply:             ply,
+		Event:           event,
+		Board:           g.Board,
+		AvailablePieces: slices.Clone(g.AvailablePieces),
+		SelectedPiece:   g.SelectedPiece,
+		GamePhase:       g.GamePhase,
+		CurrentTurn:     g.CurrentTurn,
+		Status:          g.Status,
+		Winner:          g.Winner,
+		FEN:             g.ToFEN(),
+	}
+
+	if event != nil {
+		snapshot.Notation = PieceToNotation(event.Piece)
+		switch {
+		case event.Type == MoveEventClaim:
+			snapshot.Notation = ClaimToken
+		case event.Type == MoveEventPlace && event.Position != nil:
+			snapshot.Notation = CreateMoveNotation(event.Piece, CoordsToPosition(event.Position.Row, event.Position.Col))
+		}
+	}
+
+	return snapshot
+}
@@ -0,0 +1,89 @@
+package game
+
+import (
+	"errors"
+	"quarto/models/apperror"
+	"testing"
+	"time"
+)
+
+func TestReplay(t *testing.T) {
+	g := InitializeGame(1, 2)
+	now := time.Now()
+	steps := []func() error{
+		func() error { return g.applySelection(0, now) },
+		func() error { return g.applyPlacement(Position{Row: 0, Col: 0}, now) },
+		func() error { return g.applySelection(1, now) },
+		func() error { return g.applyPlacement(Position{Row: 0, Col: 1}, now) },
+	}
+	for i, step := range steps {
+		if err := step(); err != nil {
+			t.Fatalf("étape %d: %v", i, err)
+		}
+	}
+
+	plies, err := Replay(1, 2, g.Options, g.History)
+	if err != nil {
+		t.Fatalf("rejeu: %v", err)
+	}
+	if len(plies) != len(g.History)+1 {
+		t.Fatalf("nombre de positions = %d, attendu %d", len(plies), len(g.History)+1)
+	}
+
+	if plies[0].Event != nil || plies[0].SelectedPiece != PieceEmpty || len(plies[0].AvailablePieces) != 16 {
+		t.Errorf("position initiale inattendue: %+v", plies[0])
+	}
+	if plies[1].Notation != "BCGP" || plies[1].GamePhase != GamePhasePlacePiece || plies[1].CurrentTurn != 2 {
+		t.Errorf("après la sélection: %+v", plies[1])
+	}
+	if plies[2].Notation != "BCGP-a1" || plies[2].Board[0][0] != 0 {
+		t.Errorf("après le placement: %+v", plies[2])
+	}
+
+	last := plies[len(plies)-1]
+	if last.Board != g.Board || last.CurrentTurn != g.CurrentTurn || len(last.AvailablePieces) != len(g.AvailablePieces) {
+		t.Errorf("la dernière position ne correspond pas à la partie: %+v", last)
+	}
+
+	position, err := PositionAt(g, 2)
+	if err != nil || position.Ply != 2 || position.Board != plies[2].Board {
+		t.Errorf("PositionAt(2) = %+v, %v", position, err)
+	}
+	if _, err := PositionAt(g, len(g.History)+1); err == nil {
+		t.Error("un demi-coup hors de la partie devrait être refusé")
+	}
+}
+
+func TestReplayCorruptHistory(t *testing.T) {
+	history := []MoveEvent{
+		{Type: MoveEventSelect, Actor: 1, Piece: 3},
+		{Type: MoveEventPlace, Actor: 2, Piece: 3, Position: &Position{Row: 1, Col: 1}},
+		{Type: MoveEventSelect, Actor: 2, Piece: 3}, // Pièce déjà jouée
+		{Type: MoveEventPlace, Actor: 1, Piece: 3, Position: &Position{Row: 2, Col: 2}},
+	}
+
+	plies, err := Replay(1, 2, GameOptions{}, history)
+
+	var replayErr *ReplayError
+	if !errors.As(err, &replayErr) {
+		t.Fatalf("erreur attendue de type ReplayError, obtenu %v", err)
+	}
+	if replayErr.Ply != 3 {
+		t.Errorf("premier demi-coup illégal = %d, attendu 3", replayErr.Ply)
+	}
+	if len(plies) != 3 {
+		t.Errorf("positions valides retournées = %d, attendu 3", len(plies))
+	}
+
+	// L'erreur est exposée comme erreur métier, avec le code de sa cause
+	var appErr *apperror.Error
+	if !errors.As(err, &appErr) || !errors.Is(appErr, ErrIllegalHistory) || appErr.Details["cause"] != ErrPieceUnavailable.Code {
+		t.Errorf("erreur métier inattendue: %+v", appErr)
+	}
+
+	// Un joueur qui agit hors de son tour est détecté
+	history = []MoveEvent{{Type: MoveEventSelect, Actor: 2, Piece: 0}}
+	if _, err := Replay(1, 2, GameOptions{}, history); !errors.As(err, &replayErr) || replayErr.Ply != 1 {
+		t.Errorf("acteur invalide non détecté: %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package game
+
+import "fmt"
+
+// GetSeries récupère la série de revanches d'une partie et vérifie les droits d'accès
+func GetSeries(gameID string, userID int64) (*Series, error) {
+	g, err := GetGame(gameID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	games, err := GetSeriesGames(g.SeriesID)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la récupération de la série: %v", err)
+	}
+
+	series := BuildSeries(g.SeriesID, games)
+	return &series, nil
+}
+
+// BuildSeries calcule le score cumulé d'une série à partir de ses parties triées chronologiquement
+func BuildSeries(seriesID string, games []Game) Series {
+	series := Series{
+		ID:    seriesID,
+		Games: make([]SeriesGame, 0, len(games)),
+	}
+	if len(games) == 0 {
+		return series
+	}
+
+	// Le score est exprimé pour les joueurs de la première partie, les couleurs alternant ensuite
+	series.Player1ID = games[0].Player1ID
+	series.Player2ID = games[0].Player2ID
+
+	for _, g := range games {
+		if g.Status == StatusFinished {
+			switch g.Winner {
+			case series.Player1ID:
+				series.Score.Player1Wins++
+			case series.Player2ID:
+				series.Score.Player2Wins++
+			default:
+				series.Score.Draws++
+			}
+		}
+
+		series.Games = append(series.Games, SeriesGame{
+			GameID:    g.ID,
+			Player1ID: g.Player1ID,
+			Player2ID: g.Player2ID,
+			Status:    g.Status,
+			Winner:    g.Winner,
+			CreatedAt: g.CreatedAt,
+			Score:     series.Score,
+		})
+	}
+
+	return series
+}
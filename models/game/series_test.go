@@ -0,0 +1,41 @@
+package game
+
+import "testing"
+
+func TestBuildSeries(t *testing.T) {
+	games := []Game{
+		{ID: "g1", Player1ID: 1, Player2ID: 2, Status: StatusFinished, Winner: 1},
+		{ID: "g2", Player1ID: 2, Player2ID: 1, Status: StatusFinished, Winner: 2},
+		{ID: "g3", Player1ID: 1, Player2ID: 2, Status: StatusFinished, Winner: 0},
+		{ID: "g4", Player1ID: 2, Player2ID: 1, Status: StatusPlaying},
+	}
+
+	series := BuildSeries("g1", games)
+
+	if series.Player1ID != 1 || series.Player2ID != 2 {
+		t.Fatalf("joueurs de la série = %d/%d, attendu 1/2", series.Player1ID, series.Player2ID)
+	}
+
+	expected := []SeriesScore{
+		{Player1Wins: 1},
+		{Player1Wins: 1, Player2Wins: 1},
+		{Player1Wins: 1, Player2Wins: 1, Draws: 1},
+		{Player1Wins: 1, Player2Wins: 1, Draws: 1}, // Partie en cours : score inchangé
+	}
+
+	if len(series.Games) != len(expected) {
+		t.Fatalf("nombre de parties = %d, attendu %d", len(series.Games), len(expected))
+	}
+	for i, score := range expected {
+		if series.Games[i].Score != score {
+			t.Errorf("score après la partie %d = %+v, attendu %+v", i+1, series.Games[i].Score, score)
+		}
+	}
+	if series.Score != expected[len(expected)-1] {
+		t.Errorf("score final = %+v, attendu %+v", series.Score, expected[len(expected)-1])
+	}
+
+	if empty := BuildSeries("none", nil); len(empty.Games) != 0 || empty.Score != (SeriesScore{}) {
+		t.Errorf("série vide inattendue: %+v", empty)
+	}
+}
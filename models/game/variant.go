@@ -0,0 +1,101 @@
+package game
+
+import "slices"
+
+// Variantes de règles, choisies à la création de la partie
+const (
+	VariantStandard     = "standard"      // Lignes, colonnes et diagonales
+	VariantSquares      = "squares"       // Variante standard, plus tout carré 2x2 de cases adjacentes
+	VariantSquaresTorus = "squares_torus" // Variante des carrés, le plateau se refermant sur ses bords
+)
+
+// IsValidVariant vérifie qu'une variante est connue (vide = standard)
+func IsValidVariant(variant string) bool {
+	switch variant {
+	case "", VariantStandard, VariantSquares, VariantSquaresTorus:
+		return true
+	}
+	return false
+}
+
+// CheckWinVariant vérifie s'il y a une victoire sur le plateau selon la variante
+func CheckWinVariant(board [4][4]Piece, variant string) bool {
+	if CheckWin(board) {
+		return true
+	}
+
+	switch variant {
+	case VariantSquares:
+		return checkSquares(board, 3)
+	case VariantSquaresTorus:
+		return checkSquares(board, 4)
+	}
+	return false
+}
+
+// checkSquares vérifie les carrés 2x2 dont le coin supérieur gauche est dans les limites (4 : carrés à cheval sur les bords)
+func checkSquares(board [4][4]Piece, limit int) bool {
+	for i := range limit {
+		for j := range limit {
+			next, down := (j+1)%4, (i+1)%4
+			if hasCommonCharacteristic([]Piece{board[i][j], board[i][next], board[down][j], board[down][next]}) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// completesWin vérifie si la pièce posée en position forme une ligne (ou un carré selon la variante) gagnante
+func completesWin(board [4][4]Piece, position Position, variant string) bool {
+	for _, line := range WinningLines(variant) {
+		if !slices.Contains(line[:], position) {
+			continue
+		}
+		pieces := make([]Piece, 0, 4)
+		for _, square := range line {
+			pieces = append(pieces, board[square.Row][square.Col])
+		}
+		if hasCommonCharacteristic(pieces) {
+			return true
+		}
+	}
+	return false
+}
+
+// WinningLines retourne les alignements de cases gagnants de la variante
+func WinningLines(variant string) (lines [][4]Position) {
+	for i := range 4 {
+		lines = append(lines,
+			[4]Position{{i, 0}, {i, 1}, {i, 2}, {i, 3}},
+			[4]Position{{0, i}, {1, i}, {2, i}, {3, i}},
+		)
+	}
+	lines = append(lines,
+		[4]Position{{0, 0}, {1, 1}, {2, 2}, {3, 3}},
+		[4]Position{{0, 3}, {1, 2}, {2, 1}, {3, 0}},
+	)
+
+	limit := 0
+	switch variant {
+	case VariantSquares:
+		limit = 3
+	case VariantSquaresTorus:
+		limit = 4
+	}
+	for i := range limit {
+		for j := range limit {
+			next, down := (j+1)%4, (i+1)%4
+			lines = append(lines, [4]Position{{i, j}, {i, next}, {down, j}, {down, next}})
+		}
+	}
+	return
+}
+
+// VariantOrDefault retourne la variante de la partie (standard pour les parties antérieures aux variantes)
+func (o GameOptions) VariantOrDefault() string {
+	if o.Variant == "" {
+		return VariantStandard
+	}
+	return o.Variant
+}
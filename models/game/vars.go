@@ -6,6 +6,13 @@ import (
 	"github.com/fatih/structs"
 )
 
+// MoveEvent types
+const (
+	MoveEventSelect = "select"
+	MoveEventPlace  = "place"
+	MoveEventClaim  = "claim"
+)
+
 // GamePhase constants
 const (
 	GamePhaseSelectPiece = iota
@@ -42,29 +49,124 @@ const (
 
 type (
 	Game struct {
-		ID              string      `structs:"id" json:"id"`
-		Player1ID       int64       `structs:"player1_id" json:"player1_id"`
-		Player2ID       int64       `structs:"player2_id" json:"player2_id"`
-		CurrentTurn     int64       `structs:"current_turn" json:"current_turn"`         // ID of the player whose turn it is
-		GamePhase       int         `structs:"game_phase" json:"game_phase"`             // 0 = "selectPiece", 1 = "placePiece"
-		Board           [4][4]Piece `structs:"board" json:"board"`                       // 4x4 matrix of Piece
-		AvailablePieces []Piece     `structs:"available_pieces" json:"available_pieces"` // List of available pieces (1-16)
-		SelectedPiece   Piece       `structs:"selected_piece" json:"selected_piece"`     // Current piece to place
-		Status          int         `structs:"status" json:"status"`                     // 0 = "playing", 1 = "finished"
-		Winner          int64       `structs:"winner" json:"winner"`                     // ID of the winner (0 if draw)
-		History         []Move      `structs:"move_history" json:"move_history"`         // List of moves made in the game
-		CreatedAt       time.Time   `structs:"created_at" json:"created_at"`
-		UpdatedAt       time.Time   `structs:"updated_at" json:"updated_at"`
+		ID                  string      `structs:"id" json:"id"`
+		Player1ID           int64       `structs:"player1_id" json:"player1_id"`
+		Player2ID           int64       `structs:"player2_id" json:"player2_id"`
+		CurrentTurn         int64       `structs:"current_turn" json:"current_turn"`         // ID of the player whose turn it is
+		GamePhase           int         `structs:"game_phase" json:"game_phase"`             // 0 = "selectPiece", 1 = "placePiece"
+		Board               [4][4]Piece `structs:"board" json:"board"`                       // 4x4 matrix of Piece
+		AvailablePieces     []Piece     `structs:"available_pieces" json:"available_pieces"` // List of available pieces (1-16)
+		SelectedPiece       Piece       `structs:"selected_piece" json:"selected_piece"`     // Current piece to place
+		Status              int         `structs:"status" json:"status"`                     // 0 = "playing", 1 = "finished"
+		Winner              int64       `structs:"winner" json:"winner"`                     // ID of the winner (0 if draw)
+		History             []MoveEvent `structs:"move_history" json:"move_history"`         // Selections and placements made in the game
+		Options             GameOptions `structs:"options" json:"options"`                   // Options chosen when the game was created
+		CreatedAt           time.Time   `structs:"created_at" json:"created_at"`
+		UpdatedAt           time.Time   `structs:"updated_at" json:"updated_at"`
+		SeriesID            string      `structs:"series_id" json:"series_id"`                         // Série de revanches à laquelle appartient la partie
+		PreviousGameID      string      `structs:"previous_game_id" json:"previous_game_id"`           // Partie dont celle-ci est la revanche
+		DrawOfferedBy       int64       `structs:"draw_offered_by" json:"draw_offered_by"`             // Joueur ayant proposé la nulle (0 si aucune proposition)
+		TakebackRequestedBy int64       `structs:"takeback_requested_by" json:"takeback_requested_by"` // Joueur ayant demandé l'annulation de son coup (0 si aucune demande)
+		Player1HintsUsed    int         `structs:"player1_hints_used" json:"player1_hints_used"`       // Indices consommés par le joueur 1
+		Player2HintsUsed    int         `structs:"player2_hints_used" json:"player2_hints_used"`       // Indices consommés par le joueur 2
+	}
+
+	// ActionRules définit les restrictions des actions de partie
+	ActionRules struct {
+		DrawOffersUnratedOnly bool // Propositions de nulle réservées aux parties non classées
+		TakebacksUnratedOnly  bool // Annulations de coup réservées aux parties non classées
 	}
 
 	Piece int
 
+	// Series regroupe une partie et ses revanches successives
+	Series struct {
+		ID        string       `json:"id"`
+		Player1ID int64        `json:"player1_id"` // Joueur 1 de la première partie
+		Player2ID int64        `json:"player2_id"` // Joueur 2 de la première partie
+		Games     []SeriesGame `json:"games"`
+		Score     SeriesScore  `json:"score"`
+	}
+
+	// Ply représente la position de la partie après un demi-coup (sélection ou placement) ; le demi-coup 0 est la position initiale
+	Ply struct {
+		Ply             int         `json:"ply"`
+		Event           *MoveEvent  `json:"event,omitempty"`    // Action ayant mené à cette position
+		Notation        string      `json:"notation,omitempty"` // BCGP pour une sélection, BCGP-a1 pour un placement
+		FEN             string      `json:"fen"`                // Notation compacte de la position
+		Board           [4][4]Piece `json:"board"`
+		AvailablePieces []Piece     `json:"available_pieces"`
+		SelectedPiece   Piece       `json:"selected_piece"`
+		GamePhase       int         `json:"game_phase"`
+		CurrentTurn     int64       `json:"current_turn"`
+		Status          int         `json:"status"`
+		Winner          int64       `json:"winner"`
+	}
+
+	// ReplayResponse représente le déroulé complet d'une partie
+	ReplayResponse struct {
+		GameID string `json:"game_id"`
+		Plies  []Ply  `json:"plies"`
+	}
+
+	// SeriesGame représente une partie d'une série avec le score cumulé après celle-ci
+	SeriesGame struct {
+		GameID    string      `json:"game_id"`
+		Player1ID int64       `json:"player1_id"`
+		Player2ID int64       `json:"player2_id"`
+		Status    int         `json:"status"`
+		Winner    int64       `json:"winner"`
+		CreatedAt time.Time   `json:"created_at"`
+		Score     SeriesScore `json:"score"`
+	}
+
+	// SeriesScore représente le score d'une série, du point de vue des joueurs de la première partie
+	SeriesScore struct {
+		Player1Wins int `json:"player1_wins"`
+		Player2Wins int `json:"player2_wins"`
+		Draws       int `json:"draws"`
+	}
+
+	// GameOptions représente les options d'une partie, fixées à sa création
+	GameOptions struct {
+		TimeControl TimeControl `structs:"time_control" json:"time_control"`
+		Rated       bool        `structs:"rated" json:"rated"`
+		Variant     string      `structs:"variant" json:"variant" enums:"standard,squares,squares_torus"` // Variante de règles (défaut: standard)
+		CallQuarto  bool        `structs:"call_quarto" json:"call_quarto"`                                // Les victoires doivent être annoncées par l'action claim_quarto
+		Hints       int         `structs:"hints" json:"hints"`                                            // Indices accordés à chaque joueur pendant la partie
+		Bot         *BotOptions `structs:"bot,omitempty" json:"bot,omitempty"`                            // Adversaire joué par le serveur (parties contre un bot)
+	}
+
+	// BotOptions représente le bot d'une partie : son compte et le moteur qui cherche ses coups
+	BotOptions struct {
+		PlayerID    int64  `structs:"player_id" json:"player_id"`
+		Engine      string `structs:"engine" json:"engine" enums:"minimax,mcts"` // Moteur de recherche (défaut: minimax)
+		TimeLimitMs int    `structs:"time_limit_ms" json:"time_limit_ms"`        // Temps de réflexion par coup (défaut: 5000)
+	}
+
+	// TimeControl représente la cadence d'une partie (0 = pas de limite de temps)
+	TimeControl struct {
+		InitialSeconds   int `structs:"initial_seconds" json:"initial_seconds"`
+		IncrementSeconds int `structs:"increment_seconds" json:"increment_seconds"`
+	}
+
 	// Position représente une position sur le plateau Quarto (4x4)
 	Position struct {
 		Row int `json:"row"`
 		Col int `json:"col"`
 	}
 
+	// MoveEvent représente une action de jeu enregistrée dans l'historique (sélection, placement ou annonce de Quarto)
+	MoveEvent struct {
+		Type       string    `structs:"type" json:"type"`                             // select, place, claim
+		Actor      int64     `structs:"actor" json:"actor"`                           // Joueur ayant effectué l'action
+		Piece      Piece     `structs:"piece" json:"piece"`                           // Pièce sélectionnée ou placée
+		Position   *Position `structs:"position,omitempty" json:"position,omitempty"` // Position du placement
+		Timestamp  time.Time `structs:"timestamp" json:"timestamp"`                   // Date de l'action (vide pour les anciennes parties)
+		ThinkingMs int64     `structs:"thinking_ms" json:"thinking_ms"`               // Temps de réflexion depuis l'action précédente
+		Accepted   bool      `structs:"accepted,omitempty" json:"accepted,omitempty"` // Annonce de Quarto fondée (claim uniquement)
+	}
+
 	// Move représente un mouvement complet dans Quarto (placement + sélection pour l'adversaire)
 	Move struct {
 		Piece    Piece    `json:"piece"`    // ID de la pièce sélectionnée par l'adversaire (0-15)
@@ -80,6 +182,36 @@ type (
 		Position string `json:"position" validate:"required"`
 	}
 
+	// LegalMovesResponse liste les coups légaux de la phase en cours
+	LegalMovesResponse struct {
+		GameID      string   `json:"game_id"`
+		GamePhase   int      `json:"game_phase"`
+		CurrentTurn int64    `json:"current_turn"`
+		Pieces      []Piece  `json:"pieces"`    // Pièces pouvant être données (phase de sélection)
+		Positions   []string `json:"positions"` // Cases libres pour la pièce sélectionnée (phase de placement)
+	}
+
+	// MoveCheck représente le résultat d'un coup simulé sans être enregistré
+	MoveCheck struct {
+		Legal  bool   `json:"legal"`
+		Wins   bool   `json:"wins"`             // Le placement formerait un Quarto
+		Code   string `json:"code,omitempty"`   // Code de l'erreur si le coup est illégal
+		Reason string `json:"reason,omitempty"` // Raison si le coup est illégal
+		err    error
+	}
+
+	// CreateBotGameRequest représente la création d'une partie contre le bot du serveur
+	CreateBotGameRequest struct {
+		Engine      string `json:"engine" enums:"minimax,mcts"`                    // Moteur de recherche du bot (défaut: minimax)
+		TimeLimitMs int    `json:"time_limit_ms"`                                  // Temps de réflexion du bot par coup (défaut: 5000, max: 30000)
+		Starter     string `json:"starter" enums:"me,bot,random"`                  // Premier joueur (défaut: me)
+		Variant     string `json:"variant" enums:"standard,squares,squares_torus"` // Variante de règles (défaut: standard)
+	}
+
+	GameActionRequest struct {
+		Action string `json:"action" validate:"required" example:"offer_draw"` // offer_draw, accept_draw, decline_draw, request_takeback, accept_takeback, decline_takeback, claim_quarto
+	}
+
 	AIResponse struct {
 		SelectedPiece  *int    `json:"selected_piece"`
 		Position       *string `json:"position"`
@@ -91,10 +223,30 @@ type (
 		NodesVisited   int     `json:"nodes_visited"`
 	}
 
+	// AnalysisResponse représente l'analyse d'une position
 	AnalysisResponse struct {
-		Evaluation float64  `json:"evaluation"`
-		BestMoves  []string `json:"best_moves"`
-		Threats    []string `json:"threats"`
+		GameID         string   `json:"game_id"`
+		Evaluation     float64  `json:"evaluation"`      // Évaluation du moteur pour le joueur au trait, entre -1 et 1 : 1 gain forcé, -1 perte forcée, sinon estimation heuristique (0 nulle ou position équilibrée)
+		BestMoves      []string `json:"best_moves"`      // Continuation conseillée par le moteur
+		Threats        []Threat `json:"threats"`         // Alignements auxquels il ne manque qu'une pièce
+		PoisonedPieces []Piece  `json:"poisoned_pieces"` // Pièces qui permettraient à celui qui les reçoit de gagner
+		SafePieces     []Piece  `json:"safe_pieces"`     // Pièces ne complétant aucun alignement
+		Hint           *Hint    `json:"hint,omitempty"`  // Coup conseillé par le moteur, si demandé
+		HintsRemaining int      `json:"hints_remaining"` // Indices restant au joueur (-1 si illimité)
+	}
+
+	// Threat représente un alignement (ou un carré selon la variante) à une pièce du Quarto
+	Threat struct {
+		Squares         []string `json:"squares"`         // Cases de l'alignement
+		Square          string   `json:"square"`          // Case libre à compléter
+		Characteristics []string `json:"characteristics"` // Caractéristiques qu'une pièce doit partager pour le compléter
+	}
+
+	// Hint représente le coup conseillé par le moteur au joueur au trait
+	Hint struct {
+		Position   string  `json:"position,omitempty"` // Case où placer la pièce en main (phase de placement)
+		Piece      Piece   `json:"piece"`              // Pièce à donner à l'adversaire (-1 si aucune)
+		Evaluation float64 `json:"evaluation"`         // Entre -1 et 1 : 1 gain forcé, -1 perte forcée, sinon estimation heuristique (0 nulle ou position équilibrée)
 	}
 
 	GameList []Game
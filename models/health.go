@@ -1,6 +1,9 @@
 package models
 
+import "quarto/scheduler"
+
 type HealthModel struct {
-	Status  string `json:"status"`
-	Version string `json:"version"`
+	Status  string                `json:"status"`
+	Version string                `json:"version"`
+	Jobs    []scheduler.JobStatus `json:"jobs"`
 }
@@ -0,0 +1,23 @@
+package user
+
+import (
+	"fmt"
+	"quarto/models/postgresql"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// BotEmail est l'adresse du compte du bot, créé avec le schéma de la base
+const BotEmail = "bot@bot.quarto.local"
+
+// GetBotAccount retourne le compte qui joue les coups du serveur dans les parties contre un bot
+func GetBotAccount() (User, error) {
+	sqlCo, err := pgx.ConnectConfig(postgresql.SQLCtx, postgresql.SQLConn)
+	if err != nil {
+		return User{}, fmt.Errorf("erreur de connexion DB: %w", err)
+	}
+	defer sqlCo.Close(postgresql.SQLCtx)
+
+	query := "SELECT * FROM account WHERE email = $1"
+	return ScanUser(sqlCo.QueryRow(postgresql.SQLCtx, query, BotEmail))
+}
@@ -491,7 +491,7 @@ func GetUserPublicByID(userID int64) (*UserPublic, error) {
 	err = row.Scan(&user.ID, &user.Username)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("utilisateur non trouvé")
+			return nil, ErrUserNotFound
 		}
 		return nil, fmt.Errorf("erreur lors de la récupération de l'utilisateur: %v", err)
 	}
@@ -0,0 +1,11 @@
+package user
+
+import (
+	"net/http"
+	"quarto/models/apperror"
+)
+
+// Erreurs métier des comptes
+var (
+	ErrUserNotFound = apperror.New("user.not_found", http.StatusNotFound, "utilisateur non trouvé", "user not found")
+)
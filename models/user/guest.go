@@ -0,0 +1,48 @@
+package user
+
+import (
+	"fmt"
+	"quarto/models/postgresql"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+// GuestEmailDomain est le domaine des adresses générées pour les comptes invités
+const GuestEmailDomain = "guest.quarto.local"
+
+// InsertGuestAccount crée un compte invité (pseudo et identifiants générés) dans la transaction donnée et le retourne,
+// pour que le compte ne soit conservé que si le reste de la transaction aboutit
+func InsertGuestAccount(tx pgx.Tx) (User, error) {
+	suffix := strings.ReplaceAll(uuid.New().String(), "-", "")
+
+	query := "insert into account (email, username, password) " +
+		"VALUES ($1,$2,crypt($3, gen_salt('bf'))) RETURNING *"
+
+	return ScanUser(tx.QueryRow(postgresql.SQLCtx, query, suffix+"@"+GuestEmailDomain, "invite_"+suffix[:10], uuid.New().String()))
+}
+
+// GuestSession ouvre la session d'un compte invité créé par InsertGuestAccount
+func GuestSession(u User) UserToken {
+	token := UserToken{
+		User:      u,
+		CreatedAt: time.Now(),
+	}
+	token.Store()
+	return token
+}
+
+// DeleteGuestAccount supprime un compte invité qui n'a pu rejoindre sa partie ; les autres comptes ne sont pas touchés
+func DeleteGuestAccount(id int64) error {
+	sqlCo, err := pgx.ConnectConfig(postgresql.SQLCtx, postgresql.SQLConn)
+	if err != nil {
+		return fmt.Errorf("erreur de connexion DB: %w", err)
+	}
+	defer sqlCo.Close(postgresql.SQLCtx)
+
+	query := "DELETE FROM account WHERE id = $1 AND email LIKE $2"
+	_, err = sqlCo.Exec(postgresql.SQLCtx, query, id, "%@"+GuestEmailDomain)
+	return err
+}
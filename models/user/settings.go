@@ -0,0 +1,51 @@
+package user
+
+import (
+	"fmt"
+	"quarto/models/postgresql"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// GetUserSettings récupère les préférences d'un utilisateur (valeurs par défaut si absentes)
+func GetUserSettings(userID int64) (settings UserSettings, err error) {
+	sqlCo, err := pgx.ConnectConfig(postgresql.SQLCtx, postgresql.SQLConn)
+	if err != nil {
+		err = fmt.Errorf("erreur de connexion à la base de données: %v", err)
+		return
+	}
+	defer sqlCo.Close(postgresql.SQLCtx)
+
+	query := "SELECT friends_only_challenges FROM user_settings WHERE user_id = $1"
+	err = sqlCo.QueryRow(postgresql.SQLCtx, query, userID).Scan(&settings.FriendsOnlyChallenges)
+	if err == pgx.ErrNoRows {
+		return UserSettings{}, nil
+	}
+	if err != nil {
+		err = fmt.Errorf("erreur lors de la récupération des préférences: %v", err)
+	}
+
+	return
+}
+
+// UpdateUserSettings enregistre les préférences d'un utilisateur
+func UpdateUserSettings(userID int64, settings UserSettings) error {
+	sqlCo, err := pgx.ConnectConfig(postgresql.SQLCtx, postgresql.SQLConn)
+	if err != nil {
+		return fmt.Errorf("erreur de connexion à la base de données: %v", err)
+	}
+	defer sqlCo.Close(postgresql.SQLCtx)
+
+	query := `
+		INSERT INTO user_settings (user_id, friends_only_challenges, updated_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (user_id) DO UPDATE
+		SET friends_only_challenges = EXCLUDED.friends_only_challenges, updated_at = NOW()`
+
+	_, err = sqlCo.Exec(postgresql.SQLCtx, query, userID, settings.FriendsOnlyChallenges)
+	if err != nil {
+		return fmt.Errorf("erreur lors de la mise à jour des préférences: %v", err)
+	}
+
+	return nil
+}
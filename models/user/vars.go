@@ -35,6 +35,11 @@ type (
 		ID       int64  `json:"id"`
 		Username string `json:"username"`
 	}
+
+	// Préférences d'un utilisateur
+	UserSettings struct {
+		FriendsOnlyChallenges bool `json:"friends_only_challenges"` // Seuls les amis peuvent envoyer un défi
+	}
 )
 
 func (user User) ToSelfWebDetail() map[string]any {
@@ -2,8 +2,13 @@ package websocket
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
+	"quarto/models/apperror"
+	"quarto/models/friend"
+	"quarto/models/game"
+	"strconv"
 	"sync"
 	"time"
 
@@ -37,6 +42,7 @@ type Client struct {
 	send   chan []byte
 	userID int64
 	gameID string
+	lang   string // Langue des messages d'erreur, d'après l'en-tête Accept-Language de la connexion
 }
 
 type WSMessage struct {
@@ -143,6 +149,81 @@ func (h *Hub) BroadcastToGame(gameID string, message WSMessage) {
 	}
 }
 
+// HasUser indique si un utilisateur a au moins une connexion active sur ce hub
+func (h *Hub) HasUser(userID int64) bool {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	for client := range h.clients {
+		if client.userID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// SendToUser envoie un message à toutes les connexions d'un utilisateur sur ce hub
+func (h *Hub) SendToUser(userID int64, message WSMessage) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	messageBytes, err := json.Marshal(message)
+	if err != nil {
+		log.Printf("Erreur de sérialisation du message: %v", err)
+		return
+	}
+
+	for client := range h.clients {
+		if client.userID != userID {
+			continue
+		}
+		select {
+		case client.send <- messageBytes:
+		default:
+			log.Printf("Failed to send message to client %d", client.userID)
+		}
+	}
+}
+
+// broadcastChat relaie un message de discussion aux clients de la partie qui n'ont pas de blocage avec l'expéditeur.
+// Les blocages sont chargés en une requête, hors du verrou : une base lente ne bloque pas les autres opérations du hub.
+func (h *Hub) broadcastChat(sender *Client, message WSMessage) {
+	message.GameID = sender.gameID
+	message.UserID = strconv.FormatInt(sender.userID, 10)
+	messageBytes, err := json.Marshal(message)
+	if err != nil {
+		log.Printf("Erreur de sérialisation du message: %v", err)
+		return
+	}
+
+	h.mutex.RLock()
+	recipients := make([]*Client, 0, len(h.gameClients[sender.gameID]))
+	for client := range h.gameClients[sender.gameID] {
+		recipients = append(recipients, client)
+	}
+	h.mutex.RUnlock()
+
+	blocked, err := friend.GetBlockedBetween(sender.userID)
+	if err != nil {
+		log.Printf("Erreur de chargement des blocages de %d: %v", sender.userID, err)
+		return
+	}
+
+	// Un destinataire a pu se déconnecter entre-temps : son canal est alors fermé
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	for _, client := range recipients {
+		if !h.clients[client] || (client.userID != sender.userID && blocked[client.userID]) {
+			continue
+		}
+		select {
+		case client.send <- messageBytes:
+		default:
+			log.Printf("Failed to send chat message to client %d", client.userID)
+		}
+	}
+}
+
 func (c *Client) readPump() {
 	defer func() {
 		c.hub.unregister <- c
@@ -213,11 +294,62 @@ func (c *Client) handleMessage(message WSMessage) {
 		responseBytes, _ := json.Marshal(response)
 		c.send <- responseBytes
 
+	case "chat":
+		if c.gameID != "" {
+			c.hub.broadcastChat(c, message)
+		}
+
 	default:
+		if c.gameID != "" && game.IsGameAction(message.Type) {
+			c.hub.handleGameAction(c, message.Type)
+			return
+		}
 		log.Printf("Type de message non géré: %s", message.Type)
 	}
 }
 
+// handleGameAction applique une action de partie reçue par WebSocket et diffuse le résultat
+func (h *Hub) handleGameAction(sender *Client, action string) {
+	g, err := game.GetGame(sender.gameID, sender.userID)
+	if err == nil {
+		var event string
+		event, err = g.PerformAction(sender.userID, action)
+		if errors.Is(err, game.ErrTimeExpired) {
+			h.BroadcastToGame(sender.gameID, WSMessage{
+				Type:   "game_timeout",
+				GameID: sender.gameID,
+				UserID: "server",
+				Data:   g.ToWeb(),
+			})
+		}
+		if err == nil {
+			h.BroadcastToGame(sender.gameID, WSMessage{
+				Type:   event,
+				GameID: sender.gameID,
+				UserID: strconv.FormatInt(sender.userID, 10),
+				Data:   g.ToWeb(),
+			})
+			return
+		}
+	}
+
+	data := map[string]string{"action": action, "message": err.Error()}
+	var appErr *apperror.Error
+	if errors.As(err, &appErr) {
+		data["code"] = appErr.Code
+		data["message"] = appErr.Message(sender.lang)
+	}
+
+	response := WSMessage{
+		Type:   "error",
+		GameID: sender.gameID,
+		UserID: "server",
+		Data:   data,
+	}
+	responseBytes, _ := json.Marshal(response)
+	sender.send <- responseBytes
+}
+
 // HandleWebSocket gère la connexion WebSocket
 func (h *Hub) HandleWebSocket(c echo.Context, userID int64, gameID string) error {
 	conn, err := upgrader.Upgrade(c.Response(), c.Request(), nil)
@@ -232,6 +364,7 @@ func (h *Hub) HandleWebSocket(c echo.Context, userID int64, gameID string) error
 		send:   make(chan []byte, 256),
 		userID: userID,
 		gameID: gameID,
+		lang:   apperror.Language(c.Request().Header.Get("Accept-Language")),
 	}
 
 	client.hub.register <- client
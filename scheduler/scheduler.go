@@ -0,0 +1,169 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+type (
+	// Job représente une tâche exécutée périodiquement par le scheduler
+	Job struct {
+		Name     string
+		Interval time.Duration
+		Run      func(ctx context.Context) error
+	}
+
+	// JobStatus décrit l'état d'une tâche planifiée (exposé par le endpoint de santé)
+	JobStatus struct {
+		Name      string    `json:"name"`
+		Interval  string    `json:"interval"`
+		Running   bool      `json:"running"`
+		LastRun   time.Time `json:"last_run,omitempty"`
+		NextRun   time.Time `json:"next_run"`
+		LastError string    `json:"last_error,omitempty"`
+	}
+
+	// Scheduler exécute un ensemble de tâches périodiques, chacune dans sa propre goroutine
+	Scheduler struct {
+		mutex   sync.RWMutex
+		jobs    []*JobStatus
+		pending []Job
+		started bool
+		ctx     context.Context // Contexte de Start, partagé par les tâches enregistrées ensuite
+	}
+)
+
+var (
+	defaultScheduler = New()
+	errPanic         = errors.New("la tâche s'est interrompue de manière inattendue")
+)
+
+// New crée un scheduler vide
+func New() *Scheduler {
+	return &Scheduler{}
+}
+
+// Register ajoute une tâche au scheduler ; si le scheduler est déjà démarré elle est lancée immédiatement, avec le
+// contexte de Start
+func (s *Scheduler) Register(job Job) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.started {
+		s.launch(s.ctx, job)
+		return
+	}
+	s.pending = append(s.pending, job)
+}
+
+// Start lance toutes les tâches enregistrées ; elles s'arrêtent à l'annulation du contexte
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.started {
+		return
+	}
+	s.started = true
+	s.ctx = ctx
+
+	for _, job := range s.pending {
+		s.launch(ctx, job)
+	}
+	s.pending = nil
+}
+
+// Status retourne l'état de toutes les tâches lancées
+func (s *Scheduler) Status() []JobStatus {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	statuses := make([]JobStatus, len(s.jobs))
+	for i, status := range s.jobs {
+		statuses[i] = *status
+	}
+	return statuses
+}
+
+// launch démarre la goroutine d'une tâche (le verrou doit être détenu par l'appelant)
+func (s *Scheduler) launch(ctx context.Context, job Job) {
+	status := &JobStatus{
+		Name:     job.Name,
+		Interval: job.Interval.String(),
+		NextRun:  time.Now(),
+	}
+	s.jobs = append(s.jobs, status)
+
+	go func() {
+		ticker := time.NewTicker(job.Interval)
+		defer ticker.Stop()
+
+		// select choisit au hasard entre l'annulation et le ticker s'ils sont prêts ensemble : le contexte est
+		// vérifié avant chaque exécution
+		for ctx.Err() == nil {
+			s.runOnce(ctx, job, status)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// runOnce exécute une fois la tâche et met à jour son état
+func (s *Scheduler) runOnce(ctx context.Context, job Job, status *JobStatus) {
+	s.mutex.Lock()
+	status.Running = true
+	s.mutex.Unlock()
+
+	start := time.Now()
+	err := safeRun(ctx, job)
+
+	s.mutex.Lock()
+	status.Running = false
+	status.LastRun = start
+	status.NextRun = start.Add(job.Interval)
+	status.LastError = ""
+	if err != nil {
+		status.LastError = err.Error()
+	}
+	s.mutex.Unlock()
+
+	if err != nil {
+		log.Error("Scheduled job failed", "job", job.Name, "error", err)
+	} else {
+		log.Debug("Scheduled job done", "job", job.Name, "took", time.Since(start).Round(time.Millisecond).String())
+	}
+}
+
+// safeRun exécute la tâche en transformant une panique en erreur
+func safeRun(ctx context.Context, job Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Error("Scheduled job panicked", "job", job.Name, "panic", r)
+			err = errPanic
+		}
+	}()
+	return job.Run(ctx)
+}
+
+// Register ajoute une tâche au scheduler par défaut
+func Register(job Job) {
+	defaultScheduler.Register(job)
+}
+
+// Start démarre le scheduler par défaut
+func Start(ctx context.Context) {
+	defaultScheduler.Start(ctx)
+}
+
+// Status retourne l'état des tâches du scheduler par défaut
+func Status() []JobStatus {
+	return defaultScheduler.Status()
+}
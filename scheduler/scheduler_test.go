@@ -0,0 +1,88 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// countingJob retourne une tâche qui signale chacune de ses exécutions
+func countingJob(name string, runs chan<- string) Job {
+	return Job{
+		Name:     name,
+		Interval: 10 * time.Millisecond,
+		Run: func(ctx context.Context) error {
+			select {
+			case runs <- name:
+			default:
+			}
+			return errors.New("échec")
+		},
+	}
+}
+
+// waitRun attend une exécution de la tâche
+func waitRun(t *testing.T, runs <-chan string, name string) {
+	t.Helper()
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case run := <-runs:
+			if run == name {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("job %s did not run", name)
+		}
+	}
+}
+
+func TestSchedulerRunsJobs(t *testing.T) {
+	s := New()
+	runs := make(chan string, 16)
+	s.Register(countingJob("before", runs))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.Start(ctx)
+	s.Register(countingJob("after", runs))
+
+	// Chaque tâche est exécutée à son lancement puis à chaque intervalle
+	for range 2 {
+		waitRun(t, runs, "before")
+		waitRun(t, runs, "after")
+	}
+
+	statuses := s.Status()
+	if len(statuses) != 2 {
+		t.Fatalf("expected 2 jobs, got %d", len(statuses))
+	}
+	for _, status := range statuses {
+		if status.LastRun.IsZero() || status.LastError != "échec" {
+			t.Errorf("job %s: last run %v, last error %q", status.Name, status.LastRun, status.LastError)
+		}
+	}
+}
+
+func TestSchedulerCancellation(t *testing.T) {
+	s := New()
+	ctx, cancel := context.WithCancel(context.Background())
+	s.Start(ctx)
+
+	// Une tâche enregistrée après Start s'arrête elle aussi à l'annulation du contexte de Start
+	runs := make(chan string, 16)
+	s.Register(countingJob("after", runs))
+	waitRun(t, runs, "after")
+	cancel()
+
+	time.Sleep(30 * time.Millisecond) // Une exécution en cours peut encore se terminer
+	for len(runs) > 0 {
+		<-runs
+	}
+	select {
+	case <-runs:
+		t.Error("the job kept running after cancellation")
+	case <-time.After(50 * time.Millisecond):
+	}
+}